@@ -247,28 +247,43 @@ func (a *application) handleAuthenticationAttempt(w http.ResponseWriter, r *http
 	w.WriteHeader(http.StatusOK)
 }
 
-func (a *application) isAuthorized(w http.ResponseWriter, r *http.Request) bool {
+// Returns the username of the user the request's session belongs to, along with whether
+// the session token should be regenerated. ok is false if there is no valid session.
+func (a *application) sessionUsername(r *http.Request) (username string, shouldRegenerate bool, ok bool) {
 	if !a.RequiresAuth {
-		return true
+		return "", false, false
 	}
 
 	token, err := r.Cookie(AUTH_SESSION_COOKIE_NAME)
 	if err != nil || token.Value == "" {
-		return false
+		return "", false, false
 	}
 
 	usernameHash, shouldRegenerate, err := verifySessionToken(token.Value, a.authSecretKey, time.Now())
 	if err != nil {
-		return false
+		return "", false, false
 	}
 
 	username, exists := a.usernameHashToUsername[string(usernameHash)]
 	if !exists {
-		return false
+		return "", false, false
 	}
 
 	_, exists = a.Config.Auth.Users[username]
 	if !exists {
+		return "", false, false
+	}
+
+	return username, shouldRegenerate, true
+}
+
+func (a *application) isAuthorized(w http.ResponseWriter, r *http.Request) bool {
+	if !a.RequiresAuth {
+		return true
+	}
+
+	username, shouldRegenerate, ok := a.sessionUsername(r)
+	if !ok {
 		return false
 	}
 
@@ -1,6 +1,7 @@
 package glance
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/shirou/gopsutil/v4/disk"
 	"github.com/shirou/gopsutil/v4/sensors"
+	"gopkg.in/yaml.v3"
 )
 
 type cliIntent uint8
@@ -22,6 +24,8 @@ const (
 	cliIntentMountpointInfo
 	cliIntentSecretMake
 	cliIntentPasswordHash
+	cliIntentWidgetValidate
+	cliIntentWidgetCheck
 )
 
 type cliOptions struct {
@@ -55,6 +59,8 @@ func parseCliOptions() (*cliOptions, error) {
 		fmt.Println("  sensors:print         List all sensors")
 		fmt.Println("  mountpoint:info       Print information about a given mountpoint path")
 		fmt.Println("  diagnose              Run diagnostic checks")
+		fmt.Println("  widget:validate <file> Initialize the widget(s) in a file and do a single dry-run fetch")
+		fmt.Println("  widget:check <file>   Check that the feed of every source of the widget(s) in a file is reachable")
 	}
 
 	configPath := flags.String("config", "glance.yml", "Set config path")
@@ -86,6 +92,10 @@ func parseCliOptions() (*cliOptions, error) {
 	} else if len(args) == 2 {
 		if args[0] == "password:hash" {
 			intent = cliIntentPasswordHash
+		} else if args[0] == "widget:validate" {
+			intent = cliIntentWidgetValidate
+		} else if args[0] == "widget:check" {
+			intent = cliIntentWidgetCheck
 		} else {
 			return nil, unknownCommandErr
 		}
@@ -153,3 +163,138 @@ func cliMountpointInfo(requestedPath string) int {
 
 	return 0
 }
+
+// cliLoadWidgets reads the widget definitions in the file at path, which can be either
+// a single widget or a list of widgets, printing what went wrong when that fails
+func cliLoadWidgets(path string) (widgets, bool) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("Could not read file: %v\n", err)
+		return nil, false
+	}
+
+	contents, err = parseConfigVariables(contents)
+	if err != nil {
+		fmt.Printf("Could not parse variables: %v\n", err)
+		return nil, false
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		fmt.Printf("Could not parse file: %v\n", err)
+		return nil, false
+	}
+
+	if len(document.Content) == 0 {
+		fmt.Println("File is empty")
+		return nil, false
+	}
+
+	root := document.Content[0]
+	if root.Kind == yaml.MappingNode {
+		root = &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{root}}
+	}
+
+	var definitions widgets
+	if err := root.Decode(&definitions); err != nil {
+		fmt.Printf("Widget definition is invalid: %v\n", err)
+		return nil, false
+	}
+
+	return definitions, true
+}
+
+// cliWidgetValidate initializes the widget definitions in the file at path and for
+// widgets that support it does a single fetch reporting what each source returned
+func cliWidgetValidate(path string) int {
+	definitions, ok := cliLoadWidgets(path)
+	if !ok {
+		return 1
+	}
+
+	exitCode := 0
+
+	for _, w := range definitions {
+		if err := w.initialize(); err != nil {
+			fmt.Printf("%v\n", formatWidgetInitError(err, w))
+			exitCode = 1
+			continue
+		}
+
+		videos, ok := w.(*videosWidget)
+		if !ok {
+			fmt.Printf("%s widget: OK (dry-run fetch is not supported for this widget type)\n", w.GetType())
+			continue
+		}
+
+		fmt.Printf("%s widget:\n", w.GetType())
+
+		var total, failed int
+		for _, report := range videos.dryRun() {
+			if report.err != nil {
+				failed++
+				fmt.Printf("  %-8s %s: error: %v\n", report.kind, report.source, report.err)
+				continue
+			}
+
+			total += report.count
+			fmt.Printf("  %-8s %s: %d videos\n", report.kind, report.source, report.count)
+		}
+
+		fmt.Printf("  %d videos in total, %d failed sources\n", total, failed)
+		if failed > 0 {
+			exitCode = 1
+		}
+	}
+
+	return exitCode
+}
+
+// cliWidgetCheck initializes the widget definitions in the file at path and for
+// widgets that support it checks that each source is reachable without fetching
+// its videos
+func cliWidgetCheck(path string) int {
+	definitions, ok := cliLoadWidgets(path)
+	if !ok {
+		return 1
+	}
+
+	exitCode := 0
+
+	for _, w := range definitions {
+		if err := w.initialize(); err != nil {
+			fmt.Printf("%v\n", formatWidgetInitError(err, w))
+			exitCode = 1
+			continue
+		}
+
+		videos, ok := w.(*videosWidget)
+		if !ok {
+			fmt.Printf("%s widget: OK (checking sources is not supported for this widget type)\n", w.GetType())
+			continue
+		}
+
+		fmt.Printf("%s widget:\n", w.GetType())
+
+		var reachable, broken int
+		for _, check := range videos.validate(context.Background()) {
+			switch {
+			case check.skipped != "":
+				fmt.Printf("  %-8s %s: skipped, %s\n", check.kind, check.source, check.skipped)
+			case check.err != nil:
+				broken++
+				fmt.Printf("  %-8s %s: broken: %v\n", check.kind, check.source, check.err)
+			default:
+				reachable++
+				fmt.Printf("  %-8s %s: reachable\n", check.kind, check.source)
+			}
+		}
+
+		fmt.Printf("  %d reachable, %d broken sources\n", reachable, broken)
+		if broken > 0 {
+			exitCode = 1
+		}
+	}
+
+	return exitCode
+}
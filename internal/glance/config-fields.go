@@ -218,6 +218,18 @@ func (p *proxyOptionsField) UnmarshalYAML(node *yaml.Node) error {
 		return fmt.Errorf("parsing proxy URL: %v", err)
 	}
 
+	switch parsedUrl.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q, must be one of http, https, socks5 or socks5h", parsedUrl.Scheme)
+	}
+
+	if parsedUrl.Host == "" {
+		return fmt.Errorf("proxy URL %s is missing a host", parsedUrl.Redacted())
+	}
+
+	p.URL = proxyURL
+
 	var timeout = defaultClientTimeout
 	if p.Timeout > 0 {
 		timeout = time.Duration(p.Timeout)
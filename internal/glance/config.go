@@ -7,6 +7,7 @@ import (
 	"html/template"
 	"iter"
 	"log"
+	"log/slog"
 	"maps"
 	"os"
 	"path/filepath"
@@ -34,6 +35,9 @@ type config struct {
 		Proxied    bool   `yaml:"proxied"`
 		AssetsPath string `yaml:"assets-path"`
 		BaseURL    string `yaml:"base-url"`
+		// Path of the file in which per-user widget preferences are stored
+		PreferencesPath string `yaml:"preferences-path"`
+		LogLevel        string `yaml:"log-level"`
 	} `yaml:"server"`
 
 	Auth struct {
@@ -520,6 +524,12 @@ func isConfigStateValid(config *config) error {
 		}
 	}
 
+	if config.Server.LogLevel != "" {
+		if _, err := parseLogLevel(config.Server.LogLevel); err != nil {
+			return err
+		}
+	}
+
 	if config.Server.AssetsPath != "" {
 		if _, err := os.Stat(config.Server.AssetsPath); os.IsNotExist(err) {
 			return fmt.Errorf("assets directory does not exist: %s", config.Server.AssetsPath)
@@ -579,6 +589,21 @@ func isConfigStateValid(config *config) error {
 	return nil
 }
 
+func parseLogLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(value) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+
+	return 0, fmt.Errorf("server.log-level must be one of debug, info, warn or error, got %q", value)
+}
+
 // Read-only way to store ordered maps from a YAML structure
 type orderedYAMLMap[K comparable, V any] struct {
 	keys []K
@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"html/template"
 	"log"
+	"log/slog"
 	"net/http"
 	"path/filepath"
 	"slices"
@@ -36,12 +38,19 @@ type application struct {
 
 	slugToPage map[string]*page
 	widgetByID map[uint64]widget
+	// The page each widget belongs to, whose lock guards the widget's state
+	widgetPage map[uint64]*page
 
 	RequiresAuth           bool
 	authSecretKey          []byte
 	usernameHashToUsername map[string]string
 	authAttemptsMu         sync.Mutex
 	failedAuthAttempts     map[string]*failedAuthAttempt
+
+	preferences *preferencesStore
+	// Keys under which the preferences of each widget are stored, derived from the
+	// widget's position since IDs change whenever the config gets reloaded
+	widgetPreferencesKeys map[uint64]string
 }
 
 func newApplication(c *config) (*application, error) {
@@ -51,9 +60,28 @@ func newApplication(c *config) (*application, error) {
 		Config:     *c,
 		slugToPage: make(map[string]*page),
 		widgetByID: make(map[uint64]widget),
+		widgetPage: make(map[uint64]*page),
+
+		widgetPreferencesKeys: make(map[uint64]string),
 	}
 	config := &app.Config
 
+	//
+	// Init logging
+	//
+
+	logLevel := slog.LevelInfo
+	if config.Server.LogLevel != "" {
+		level, err := parseLogLevel(config.Server.LogLevel)
+		if err != nil {
+			return nil, err
+		}
+
+		logLevel = level
+	}
+
+	slog.SetLogLoggerLevel(logLevel)
+
 	//
 	// Init auth
 	//
@@ -97,6 +125,23 @@ func newApplication(c *config) (*application, error) {
 		app.authSecretKey = secretBytes
 	}
 
+	//
+	// Init preferences
+	//
+
+	if config.Server.PreferencesPath != "" {
+		if !app.RequiresAuth {
+			log.Printf("Warning: server.preferences-path has no effect without any users configured under auth")
+		} else {
+			store, err := newPreferencesStore(config.Server.PreferencesPath)
+			if err != nil {
+				return nil, fmt.Errorf("initializing preferences: %v", err)
+			}
+
+			app.preferences = store
+		}
+	}
+
 	//
 	// Init themes
 	//
@@ -175,6 +220,8 @@ func newApplication(c *config) (*application, error) {
 		for i := range page.HeadWidgets {
 			widget := page.HeadWidgets[i]
 			app.widgetByID[widget.GetID()] = widget
+			app.widgetPage[widget.GetID()] = page
+			app.widgetPreferencesKeys[widget.GetID()] = fmt.Sprintf("%s/head/%d", page.Slug, i)
 			widget.setProviders(providers)
 		}
 
@@ -188,6 +235,8 @@ func newApplication(c *config) (*application, error) {
 			for w := range column.Widgets {
 				widget := column.Widgets[w]
 				app.widgetByID[widget.GetID()] = widget
+				app.widgetPage[widget.GetID()] = page
+				app.widgetPreferencesKeys[widget.GetID()] = fmt.Sprintf("%s/%d/%d", page.Slug, c, w)
 				widget.setProviders(providers)
 			}
 		}
@@ -306,6 +355,8 @@ func (a *application) resolveUserDefinedAssetPath(path string) string {
 
 type templateRequestData struct {
 	Theme *themeProperties
+	// Empty for anonymous users
+	Username string
 }
 
 type templateData struct {
@@ -328,6 +379,7 @@ func (a *application) populateTemplateRequestData(data *templateRequestData, r *
 	}
 
 	data.Theme = theme
+	data.Username, _, _ = a.sessionUsername(r)
 }
 
 func (a *application) handlePageRequest(w http.ResponseWriter, r *http.Request) {
@@ -383,7 +435,9 @@ func (a *application) handlePageContentRequest(w http.ResponseWriter, r *http.Re
 
 	pageData := templateData{
 		Page: page,
+		App:  a,
 	}
+	a.populateTemplateRequestData(&pageData.Request, r)
 
 	var err error
 	var responseBytes bytes.Buffer
@@ -446,26 +500,135 @@ func (a *application) handleNotFound(w http.ResponseWriter, _ *http.Request) {
 }
 
 func (a *application) handleWidgetRequest(w http.ResponseWriter, r *http.Request) {
-	// TODO: this requires a rework of the widget update logic so that rather
-	// than locking the entire page we lock individual widgets
-	w.WriteHeader(http.StatusNotImplemented)
+	// Widgets that handle requests are responsible for guarding their own
+	// state, the page lock is not held here
+	widgetID, err := strconv.ParseUint(r.PathValue("widget"), 10, 64)
+	if err != nil {
+		a.handleNotFound(w, r)
+		return
+	}
+
+	widget, exists := a.widgetByID[widgetID]
+	if !exists {
+		a.handleNotFound(w, r)
+		return
+	}
 
-	// widgetValue := r.PathValue("widget")
+	if a.handleUnauthorizedResponse(w, r, showUnauthorizedJSON) {
+		return
+	}
+
+	switch r.PathValue("path") {
+	case "preferences":
+		a.handleWidgetPreferencesRequest(w, r, widget)
+		return
+	case "content":
+		a.handleWidgetContentRequest(w, r, widget)
+		return
+	case "refresh":
+		a.handleWidgetRefreshRequest(w, r, widget)
+		return
+	}
 
-	// widgetID, err := strconv.ParseUint(widgetValue, 10, 64)
-	// if err != nil {
-	// 	a.handleNotFound(w, r)
-	// 	return
-	// }
+	widget.handleRequest(w, r)
+}
 
-	// widget, exists := a.widgetByID[widgetID]
+// handleWidgetContentRequest renders a single widget, updating it first if its cache
+// has expired, which allows refreshing widgets that weren't ready when the page was
+// loaded without reloading the whole page
+func (a *application) handleWidgetContentRequest(w http.ResponseWriter, r *http.Request, wd widget) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-	// if !exists {
-	// 	a.handleNotFound(w, r)
-	// 	return
-	// }
+	page, exists := a.widgetPage[wd.GetID()]
+	if !exists {
+		a.handleNotFound(w, r)
+		return
+	}
 
-	// widget.handleRequest(w, r)
+	data := templateData{
+		Page: page,
+		App:  a,
+	}
+	a.populateTemplateRequestData(&data.Request, r)
+
+	var content template.HTML
+
+	func() {
+		page.mu.Lock()
+		defer page.mu.Unlock()
+
+		now := time.Now()
+		if wd.requiresUpdate(&now) {
+			wd.update(context.Background())
+		}
+
+		content = data.RenderWidget(wd)
+	}()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(content))
+}
+
+// Widgets that can start fetching their content when the server starts rather than
+// when their page is first requested
+type prefetchingWidget interface {
+	widget
+	prefetch()
+}
+
+// Widgets that can be updated on demand rather than only once their cache expires
+type refreshableWidget interface {
+	widget
+	lastUpdatedAt() time.Time
+}
+
+// handleWidgetRefreshRequest updates a widget right away, regardless of its cache,
+// and renders it. Since updates happen while holding the page lock, requests made
+// while the widget is already being refreshed wait for that refresh and get its
+// result rather than updating the widget once more.
+func (a *application) handleWidgetRefreshRequest(w http.ResponseWriter, r *http.Request, wd widget) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	refreshable, ok := wd.(refreshableWidget)
+	if !ok {
+		http.Error(w, "widget can't be refreshed", http.StatusNotFound)
+		return
+	}
+
+	page, exists := a.widgetPage[wd.GetID()]
+	if !exists {
+		a.handleNotFound(w, r)
+		return
+	}
+
+	data := templateData{
+		Page: page,
+		App:  a,
+	}
+	a.populateTemplateRequestData(&data.Request, r)
+
+	requestedAt := time.Now()
+	var content template.HTML
+
+	func() {
+		page.mu.Lock()
+		defer page.mu.Unlock()
+
+		if !refreshable.lastUpdatedAt().After(requestedAt) {
+			refreshable.update(context.Background())
+		}
+
+		content = data.RenderWidget(refreshable)
+	}()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(content))
 }
 
 func (a *application) StaticAssetPath(asset string) string {
@@ -538,6 +701,12 @@ func (a *application) server() (func() error, func() error) {
 	}
 
 	start := func() error {
+		for _, w := range a.widgetByID {
+			if prefetching, ok := w.(prefetchingWidget); ok {
+				prefetching.prefetch()
+			}
+		}
+
 		log.Printf("Starting server on %s:%d (base-url: \"%s\", assets-path: \"%s\")\n",
 			a.Config.Server.Host,
 			a.Config.Server.Port,
@@ -55,6 +55,10 @@ func Main() int {
 		return cliSensorsPrint()
 	case cliIntentMountpointInfo:
 		return cliMountpointInfo(options.args[1])
+	case cliIntentWidgetValidate:
+		return cliWidgetValidate(options.args[1])
+	case cliIntentWidgetCheck:
+		return cliWidgetCheck(options.args[1])
 	case cliIntentDiagnose:
 		runDiagnostic()
 	case cliIntentSecretMake:
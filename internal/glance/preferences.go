@@ -0,0 +1,185 @@
+package glance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+)
+
+const maxWidgetPreferencesBodySize = 64 * 1024
+
+// widgetPreferences are the display preferences of a single user for a single widget
+type widgetPreferences struct {
+	Style          string   `json:"style,omitempty"`
+	Expanded       bool     `json:"expanded,omitempty"`
+	HiddenChannels []string `json:"hidden-channels,omitempty"`
+}
+
+// Implemented by widgets whose rendering can be adjusted through user preferences
+type preferencesRenderer interface {
+	validatePreferences(prefs *widgetPreferences) error
+	renderWithPreferences(prefs widgetPreferences) template.HTML
+}
+
+// preferencesStore keeps the widget preferences of every user in a JSON file, keyed
+// by username and then by the widget's preferences key
+type preferencesStore struct {
+	path string
+
+	mu    sync.RWMutex
+	users map[string]map[string]widgetPreferences
+}
+
+func newPreferencesStore(path string) (*preferencesStore, error) {
+	store := &preferencesStore{
+		path:  path,
+		users: make(map[string]map[string]widgetPreferences),
+	}
+
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading preferences file: %v", err)
+	}
+
+	if len(contents) == 0 {
+		return store, nil
+	}
+
+	if err := json.Unmarshal(contents, &store.users); err != nil {
+		return nil, fmt.Errorf("parsing preferences file: %v", err)
+	}
+
+	return store, nil
+}
+
+func (s *preferencesStore) get(username string, widgetKey string) (widgetPreferences, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prefs, exists := s.users[username][widgetKey]
+	return prefs, exists
+}
+
+func (s *preferencesStore) set(username string, widgetKey string, prefs widgetPreferences) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.users[username] == nil {
+		s.users[username] = make(map[string]widgetPreferences)
+	}
+
+	s.users[username][widgetKey] = prefs
+
+	return s.save()
+}
+
+func (s *preferencesStore) delete(username string, widgetKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.users[username], widgetKey)
+
+	return s.save()
+}
+
+// save writes the preferences to the file
+func (s *preferencesStore) save() error {
+	contents, err := json.MarshalIndent(s.users, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.path, contents, 0o600)
+}
+
+// RenderWidget renders the widget with the preferences of the user making the
+// request, falling back to the widget's defaults for anonymous users
+func (d templateData) RenderWidget(w widget) template.HTML {
+	renderer, ok := w.(preferencesRenderer)
+	if !ok || d.App == nil || d.App.preferences == nil || d.Request.Username == "" {
+		return w.Render()
+	}
+
+	key, exists := d.App.widgetPreferencesKeys[w.GetID()]
+	if !exists {
+		return w.Render()
+	}
+
+	prefs, exists := d.App.preferences.get(d.Request.Username, key)
+	if !exists {
+		return w.Render()
+	}
+
+	return renderer.renderWithPreferences(prefs)
+}
+
+func (a *application) handleWidgetPreferencesRequest(w http.ResponseWriter, r *http.Request, wd widget) {
+	renderer, ok := wd.(preferencesRenderer)
+	key, exists := a.widgetPreferencesKeys[wd.GetID()]
+	if !ok || !exists {
+		a.handleNotFound(w, r)
+		return
+	}
+
+	if a.preferences == nil {
+		http.Error(w, "preferences are not enabled", http.StatusNotFound)
+		return
+	}
+
+	username, _, ok := a.sessionUsername(r)
+	if !ok {
+		http.Error(w, "preferences are only available to logged in users", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		prefs, _ := a.preferences.get(username, key)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(prefs)
+	case http.MethodPut:
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWidgetPreferencesBodySize))
+		if err != nil {
+			http.Error(w, "could not read request body", http.StatusBadRequest)
+			return
+		}
+
+		var prefs widgetPreferences
+		if err := json.Unmarshal(body, &prefs); err != nil {
+			http.Error(w, "invalid preferences: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := renderer.validatePreferences(&prefs); err != nil {
+			http.Error(w, "invalid preferences: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := a.preferences.set(username, key, prefs); err != nil {
+			slog.Error("Failed to save preferences", "error", err)
+			http.Error(w, "could not save preferences", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if err := a.preferences.delete(username, key); err != nil {
+			slog.Error("Failed to save preferences", "error", err)
+			http.Error(w, "could not save preferences", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,51 @@
+package glance
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestPreferencesStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "preferences.json")
+
+	store, err := newPreferencesStore(path)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	prefs := widgetPreferences{Style: "vertical-list", Expanded: true, HiddenChannels: []string{"Some Channel"}}
+	if err := store.set("alice", "home/0/1", prefs); err != nil {
+		t.Fatalf("Failed to save preferences: %v", err)
+	}
+
+	if err := store.set("alice", "home/0/2", widgetPreferences{Style: "grid-cards"}); err != nil {
+		t.Fatalf("Failed to save preferences: %v", err)
+	}
+
+	if err := store.delete("alice", "home/0/2"); err != nil {
+		t.Fatalf("Failed to delete preferences: %v", err)
+	}
+
+	reloaded, err := newPreferencesStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reload store: %v", err)
+	}
+
+	loaded, exists := reloaded.get("alice", "home/0/1")
+	if !exists {
+		t.Fatal("Expected preferences to exist after reloading")
+	}
+
+	if loaded.Style != prefs.Style || loaded.Expanded != prefs.Expanded || !slices.Equal(loaded.HiddenChannels, prefs.HiddenChannels) {
+		t.Fatalf("Expected %+v, got %+v", prefs, loaded)
+	}
+
+	if _, exists := reloaded.get("alice", "home/0/2"); exists {
+		t.Fatal("Expected deleted preferences to not exist")
+	}
+
+	if _, exists := reloaded.get("bob", "home/0/1"); exists {
+		t.Fatal("Expected preferences of other users to not exist")
+	}
+}
@@ -12,8 +12,8 @@ async function fetchPageContent(pageData) {
     return content;
 }
 
-function setupCarousels() {
-    const carouselElements = document.getElementsByClassName("carousel-container");
+function setupCarousels(root = document) {
+    const carouselElements = root.getElementsByClassName("carousel-container");
 
     if (carouselElements.length == 0) {
         return;
@@ -81,6 +81,72 @@ function timestampToRelativeTime(timestamp) {
     return prefix + Math.floor(delta / yearInSeconds) + "y";
 }
 
+function pluralize(count, singular) {
+    return count + " " + singular + (count == 1 ? "" : "s");
+}
+
+const calendarDayFormatters = {};
+
+// Returns the day of the timestamp in the given timezone, or the browser's when there's none,
+// as a number of days since the epoch so that days can be counted between two timestamps
+function timestampToCalendarDay(timestamp, timezone) {
+    const key = timezone || "";
+
+    if (calendarDayFormatters[key] === undefined) {
+        try {
+            calendarDayFormatters[key] = new Intl.DateTimeFormat("en-CA", { timeZone: timezone || undefined });
+        } catch (e) {
+            calendarDayFormatters[key] = new Intl.DateTimeFormat("en-CA");
+        }
+    }
+
+    // en-CA formats dates as YYYY-MM-DD, which can be parsed back as a UTC date
+    return Math.round(Date.parse(calendarDayFormatters[key].format(timestamp * 1000)) / (dayInSeconds * 1000));
+}
+
+// Mirrors formatRelativeVideoTime on the server
+function timestampToLongRelativeTime(timestamp, timezone) {
+    const now = Date.now() / 1000;
+    let delta = Math.floor(now - timestamp);
+
+    if (delta < 0) {
+        delta = -delta;
+
+        if (delta < hourInSeconds) {
+            return "in " + Math.max(Math.floor(delta / minuteInSeconds), 1) + "m";
+        }
+        if (delta < dayInSeconds) {
+            return "in " + Math.floor(delta / hourInSeconds) + "h";
+        }
+
+        return "in " + pluralize(timestampToCalendarDay(timestamp, timezone) - timestampToCalendarDay(now, timezone), "day");
+    }
+
+    if (delta < minuteInSeconds) {
+        return "just now";
+    }
+    if (delta < hourInSeconds) {
+        return Math.floor(delta / minuteInSeconds) + "m ago";
+    }
+
+    const days = timestampToCalendarDay(now, timezone) - timestampToCalendarDay(timestamp, timezone);
+
+    if (days == 0) {
+        return Math.floor(delta / hourInSeconds) + "h ago";
+    }
+    if (days == 1) {
+        return "yesterday";
+    }
+    if (days < 30) {
+        return pluralize(days, "day") + " ago";
+    }
+    if (days < 365) {
+        return pluralize(Math.floor(days / 30), "month") + " ago";
+    }
+
+    return pluralize(Math.floor(days / 365), "year") + " ago";
+}
+
 function updateRelativeTimeForElements(elements)
 {
     for (let i = 0; i < elements.length; i++)
@@ -91,10 +157,121 @@ function updateRelativeTimeForElements(elements)
         if (timestamp === undefined)
             continue
 
+        if (element.dataset.relativeTimeStyle === "long") {
+            element.textContent = timestampToLongRelativeTime(timestamp, element.dataset.timezone);
+            continue;
+        }
+
         element.textContent = timestampToRelativeTime(timestamp);
     }
 }
 
+function updateCountdowns(elements) {
+    for (let i = 0; i < elements.length; i++) {
+        const element = elements[i];
+        const until = Number(element.dataset.countdownUntil);
+
+        element.textContent = until * 1000 <= Date.now() ? "now" : timestampToRelativeTime(until);
+    }
+}
+
+let countdownsScheduled = false;
+
+// updates the countdowns within root right away, all countdowns on the page are then
+// updated by a single interval so that replaced widgets keep counting down
+function setupCountdowns(root = document) {
+    updateCountdowns(root.querySelectorAll("[data-countdown-until]"));
+    if (countdownsScheduled) return;
+
+    countdownsScheduled = true;
+    setInterval(() => updateCountdowns(document.querySelectorAll("[data-countdown-until]")), 30 * 1000);
+}
+
+function setupNewVideoHighlights(root = document) {
+    const elements = root.querySelectorAll("[data-video-new]");
+    if (elements.length == 0) return;
+
+    // only highlight each video once rather than on every page load
+    const storageKey = "highlighted-videos";
+    const maxRemembered = 500;
+    let highlighted = [];
+
+    try {
+        highlighted = JSON.parse(localStorage.getItem(storageKey)) || [];
+    } catch (e) {}
+
+    for (let i = 0; i < elements.length; i++) {
+        const element = elements[i];
+        const url = element.dataset.videoNew;
+
+        if (highlighted.includes(url)) continue;
+
+        highlighted.push(url);
+        element.classList.add("video-highlight");
+        element.addEventListener("animationend", () => element.classList.remove("video-highlight"), { once: true });
+    }
+
+    localStorage.setItem(storageKey, JSON.stringify(highlighted.slice(-maxRemembered)));
+}
+
+// reveals the badge of videos posted since the widget was last viewed in this browser,
+// videos only have a data-video-posted attribute when show-new-badge is enabled
+function setupNewVideoBadges(root = document) {
+    const badges = root.querySelectorAll("[data-video-posted]");
+    if (badges.length == 0) return;
+
+    const now = Math.floor(Date.now() / 1000);
+    const lastSeenByWidget = {};
+
+    for (let i = 0; i < badges.length; i++) {
+        const badge = badges[i];
+        const widgetElement = badge.closest("[data-widget-id]");
+        if (widgetElement === null) continue;
+
+        const storageKey = `videos-last-seen-${widgetElement.dataset.widgetId}`;
+
+        if (!(storageKey in lastSeenByWidget)) {
+            lastSeenByWidget[storageKey] = Number(localStorage.getItem(storageKey)) || 0;
+        }
+
+        // nothing is new the first time a widget is viewed
+        const lastSeen = lastSeenByWidget[storageKey];
+        if (lastSeen > 0 && Number(badge.dataset.videoPosted) > lastSeen) {
+            badge.hidden = false;
+        }
+    }
+
+    for (const storageKey in lastSeenByWidget) {
+        localStorage.setItem(storageKey, now);
+    }
+}
+
+// marks videos as watched when one of their links gets clicked, videos only
+// have a data-watched-url attribute when mark-watched is enabled
+function setupMarkWatchedVideos() {
+    const markWatched = (event) => {
+        if (event.type == "auxclick" && event.button != 1) return;
+
+        const link = event.target.closest("a");
+        if (link === null) return;
+
+        const videoElement = link.closest("[data-watched-url]");
+        if (videoElement === null || videoElement.classList.contains("video-watched")) return;
+
+        const widgetElement = videoElement.closest("[data-widget-id]");
+        if (widgetElement === null) return;
+
+        videoElement.classList.add("video-watched");
+        navigator.sendBeacon(
+            `${pageData.baseURL}/api/widgets/${widgetElement.dataset.widgetId}/watched`,
+            JSON.stringify({ url: videoElement.dataset.watchedUrl })
+        );
+    };
+
+    document.addEventListener("click", markWatched);
+    document.addEventListener("auxclick", markWatched);
+}
+
 function setupSearchBoxes() {
     const searchWidgets = document.getElementsByClassName("search");
 
@@ -206,15 +383,20 @@ function setupSearchBoxes() {
     }
 }
 
-function setupDynamicRelativeTime() {
-    const elements = document.querySelectorAll("[data-dynamic-relative-time]");
+let dynamicRelativeTimeScheduled = false;
+
+// updates the times within root right away, all times on the page are then updated by
+// a single interval so that replaced widgets and pages of videos don't add more of them
+function setupDynamicRelativeTime(root = document) {
+    updateRelativeTimeForElements(root.querySelectorAll("[data-dynamic-relative-time]"));
+    if (dynamicRelativeTimeScheduled) return;
+
+    dynamicRelativeTimeScheduled = true;
     const updateInterval = 60 * 1000;
     let lastUpdateTime = Date.now();
 
-    updateRelativeTimeForElements(elements);
-
     const updateElementsAndTimestamp = () => {
-        updateRelativeTimeForElements(elements);
+        updateRelativeTimeForElements(document.querySelectorAll("[data-dynamic-relative-time]"));
         lastUpdateTime = Date.now();
     };
 
@@ -308,8 +490,8 @@ function setupGroups() {
     }
 }
 
-function setupLazyImages() {
-    const images = document.querySelectorAll("img[loading=lazy]");
+function setupLazyImages(root = document) {
+    const images = root.querySelectorAll("img[loading=lazy]");
 
     if (images.length == 0) {
         return;
@@ -383,8 +565,8 @@ function attachExpandToggleButton(collapsibleContainer) {
 };
 
 
-function setupCollapsibleLists() {
-    const collapsibleLists = document.querySelectorAll(".list.collapsible-container");
+function setupCollapsibleLists(root = document) {
+    const collapsibleLists = root.querySelectorAll(".list.collapsible-container, .cards-horizontal.collapsible-container");
 
     if (collapsibleLists.length == 0) {
         return;
@@ -417,8 +599,8 @@ function setupCollapsibleLists() {
     }
 }
 
-function setupCollapsibleGrids() {
-    const collapsibleGridElements = document.querySelectorAll(".cards-grid.collapsible-container");
+function setupCollapsibleGrids(root = document) {
+    const collapsibleGridElements = root.querySelectorAll(".cards-grid.collapsible-container");
 
     if (collapsibleGridElements.length == 0) {
         return;
@@ -493,11 +675,172 @@ function setupCollapsibleGrids() {
 }
 
 const contentReadyCallbacks = [];
+let contentReady = false;
 
 function afterContentReady(callback) {
+    if (contentReady) {
+        callback();
+        return;
+    }
+
     contentReadyCallbacks.push(callback);
 }
 
+// how many times a placeholder gets refreshed before giving up, after which the
+// widget only shows up again once the page gets reloaded
+const maxLoadingWidgetRefreshes = 5;
+
+function scheduleLoadingWidgetRefresh(placeholder, attempt = 1) {
+    if (attempt > maxLoadingWidgetRefreshes) {
+        placeholder.textContent = "Still loading, reload the page to try again";
+        return;
+    }
+
+    const delay = parseInt(placeholder.dataset.refreshAfter);
+    setTimeout(() => refreshLoadingWidget(placeholder, attempt), isNaN(delay) ? 5000 : delay);
+}
+
+function setupLoadingWidgets(root = document) {
+    const placeholders = root.querySelectorAll("[data-loading-widget-id]");
+
+    for (let i = 0; i < placeholders.length; i++) {
+        scheduleLoadingWidgetRefresh(placeholders[i]);
+    }
+}
+
+// replaces the placeholder of a widget that wasn't ready when the page was loaded
+// with the widget's current content, without reloading the rest of the page
+async function refreshLoadingWidget(placeholder, attempt) {
+    let content;
+
+    try {
+        const response = await fetch(`${pageData.baseURL}/api/widgets/${placeholder.dataset.loadingWidgetId}/content`);
+        if (!response.ok) throw new Error(`unexpected status code ${response.status}`);
+        content = await response.text();
+    } catch (e) {
+        console.error("Failed to refresh widget", e);
+        placeholder.dataset.refreshAfter = 30 * 1000;
+        scheduleLoadingWidgetRefresh(placeholder, attempt + 1);
+        return;
+    }
+
+    const template = document.createElement("template");
+    template.innerHTML = content;
+
+    const widget = template.content.firstElementChild;
+    if (widget === null) return;
+
+    placeholder.replaceWith(widget);
+
+    if (widget.dataset.loadingWidgetId !== undefined) {
+        scheduleLoadingWidgetRefresh(widget, attempt + 1);
+        return;
+    }
+
+    setupReplacedWidget(widget);
+}
+
+// sets up the elements of a widget that was replaced after the page was loaded
+function setupReplacedWidget(widget) {
+    setupCarousels(widget);
+    setupCollapsibleLists(widget);
+    setupCollapsibleGrids(widget);
+    setupDynamicRelativeTime(widget);
+    setupCountdowns(widget);
+    setupNewVideoHighlights(widget);
+    setupNewVideoBadges(widget);
+    setupLazyImages(widget);
+}
+
+function setupWidgetRefreshButtons() {
+    document.addEventListener("click", async (event) => {
+        const button = event.target.closest("[data-refresh-widget]");
+        if (button === null || button.disabled) return;
+
+        const widget = button.closest("[data-widget-id]");
+        if (widget === null) return;
+
+        button.disabled = true;
+        let content;
+
+        try {
+            const response = await fetch(`${pageData.baseURL}/api/widgets/${widget.dataset.widgetId}/refresh`, { method: "POST" });
+            if (!response.ok) throw new Error(`unexpected status code ${response.status}`);
+            content = await response.text();
+        } catch (e) {
+            console.error("Failed to refresh widget", e);
+            button.disabled = false;
+            return;
+        }
+
+        const template = document.createElement("template");
+        template.innerHTML = content;
+
+        const refreshed = template.content.firstElementChild;
+        if (refreshed === null) {
+            button.disabled = false;
+            return;
+        }
+
+        widget.replaceWith(refreshed);
+
+        if (refreshed.dataset.loadingWidgetId !== undefined) {
+            scheduleLoadingWidgetRefresh(refreshed);
+            return;
+        }
+
+        setupReplacedWidget(refreshed);
+    });
+}
+
+// adds the next page of a section of videos to it, sections only have a button for
+// the next page when page-size is set and there are more videos to show
+function setupVideosPagination() {
+    document.addEventListener("click", async (event) => {
+        const button = event.target.closest("[data-videos-next-page]");
+        if (button === null || button.disabled) return;
+
+        const widget = button.closest("[data-widget-id]");
+        const section = button.closest(".videos-section");
+        if (widget === null || section === null) return;
+
+        button.disabled = true;
+        let content;
+
+        try {
+            const response = await fetch(`${pageData.baseURL}/api/widgets/${widget.dataset.widgetId}/page?${button.dataset.videosNextPage}`);
+            if (!response.ok) throw new Error(`unexpected status code ${response.status}`);
+            content = await response.text();
+        } catch (e) {
+            console.error("Failed to load the next page of videos", e);
+            button.disabled = false;
+            return;
+        }
+
+        const template = document.createElement("template");
+        template.innerHTML = content;
+
+        const items = template.content.querySelector("[data-videos-page-items]");
+        const container = section.querySelector("[data-videos-page-items]");
+        if (items === null || container === null) {
+            button.remove();
+            return;
+        }
+
+        setupDynamicRelativeTime(items);
+        setupNewVideoHighlights(items);
+        setupLazyImages(items);
+        container.append(...items.children);
+
+        const nextButton = template.content.querySelector("[data-videos-next-page]");
+        if (nextButton === null) {
+            button.remove();
+        } else {
+            button.replaceWith(nextButton);
+        }
+    });
+}
+
 const weekDayNames = ['Sunday', 'Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday'];
 const monthNames = ['January', 'February', 'March', 'April', 'May', 'June', 'July', 'August', 'September', 'October', 'November', 'December'];
 
@@ -764,9 +1107,17 @@ async function setupPage() {
         setupGroups();
         setupMasonries();
         setupDynamicRelativeTime();
+        setupCountdowns();
+        setupNewVideoHighlights();
+        setupNewVideoBadges();
+        setupMarkWatchedVideos();
+        setupWidgetRefreshButtons();
+        setupVideosPagination();
         setupLazyImages();
+        setupLoadingWidgets();
     } finally {
         pageElement.classList.add("content-ready");
+        contentReady = true;
         pageElement.setAttribute("aria-busy", "false");
 
         for (let i = 0; i < contentReadyCallbacks.length; i++) {
@@ -0,0 +1,81 @@
+package glance
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// The last path segment of links to a tab of a channel's page rather than the page itself
+var videoChannelTabs = []string{"videos", "featured", "streams", "shorts", "live"}
+
+// Path prefixes after which the next segment is the channel's name rather than a tab,
+// so that a channel named "videos" doesn't lose its name
+var videoChannelPathPrefixes = []string{"", "/c", "/channel", "/user"}
+
+// videoAuthorUrl returns the link to the given tab of the channel's page, or to the page
+// itself when the tab is empty. The link from the feed may already point to a tab, end
+// with a slash or have a query, none of which should end up in the middle of the link.
+// Links that can't be parsed are returned as they are.
+func videoAuthorUrl(channelLink string, tab string) string {
+	channelLink = strings.TrimSpace(channelLink)
+
+	parsed, err := url.Parse(channelLink)
+	if err != nil || parsed.Host == "" {
+		return channelLink
+	}
+
+	path := strings.TrimRight(parsed.Path, "/")
+	if i := strings.LastIndex(path, "/"); i >= 0 && slices.Contains(videoChannelTabs, path[i+1:]) && !slices.Contains(videoChannelPathPrefixes, path[:i]) {
+		path = path[:i]
+	}
+
+	if tab != "" {
+		path += "/" + tab
+	}
+
+	parsed.Path, parsed.RawPath = path, ""
+
+	return parsed.String()
+}
+
+// youtubeAuthorUrl returns the link to the videos of a YouTube channel, which is built from
+// author-url-template when it's set and the ID of the channel is known
+func youtubeAuthorUrl(authorUrlTemplate string, channelID string, channelLink string) string {
+	if authorUrlTemplate != "" && channelID != "" {
+		return strings.ReplaceAll(authorUrlTemplate, "{CHANNEL-ID}", channelID)
+	}
+
+	return videoAuthorUrl(channelLink, "videos")
+}
+
+// extractYoutubeChannelIDFromUrl returns the ID of the channel from a link to its page,
+// for feeds that leave out the channel's ID but link to the channel by it
+func extractYoutubeChannelIDFromUrl(channelLink string) string {
+	parsed, err := url.Parse(strings.TrimSpace(channelLink))
+	if err != nil {
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "channel" {
+		return ""
+	}
+
+	return segments[1]
+}
+
+// setAuthorLinks points the links of the videos' authors to what author-link is set to,
+// the feeds' links to YouTube channels go to their videos tab already
+func (widget *videosWidget) setAuthorLinks(videos videoList) {
+	switch widget.AuthorLink {
+	case videosAuthorLinkChannel:
+		for i := range videos {
+			videos[i].AuthorUrl = videoAuthorUrl(videos[i].AuthorUrl, "")
+		}
+	case videosAuthorLinkNone:
+		for i := range videos {
+			videos[i].AuthorUrl = ""
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package glance
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestVideoAuthorUrl(t *testing.T) {
+	tests := []struct {
+		link     string
+		tab      string
+		expected string
+	}{
+		{"https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw", "videos", "https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/videos"},
+		{"https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/", "videos", "https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/videos"},
+		{"https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/videos", "videos", "https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/videos"},
+		{"https://www.youtube.com/@someone/featured", "", "https://www.youtube.com/@someone"},
+		{"https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw?sub_confirmation=1", "videos", "https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/videos?sub_confirmation=1"},
+		{" https://rumble.com/c/SomeChannel/ ", "", "https://rumble.com/c/SomeChannel"},
+		// Channels that happen to be named like a tab keep their name
+		{"https://rumble.com/c/videos", "", "https://rumble.com/c/videos"},
+		{"https://www.youtube.com", "videos", "https://www.youtube.com/videos"},
+		{"", "videos", ""},
+		{"/channel/relative", "videos", "/channel/relative"},
+	}
+
+	for _, test := range tests {
+		if result := videoAuthorUrl(test.link, test.tab); result != test.expected {
+			t.Errorf("Expected %q with tab %q to become %q, got %q", test.link, test.tab, test.expected, result)
+		}
+	}
+
+	widget := newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+author-link: channel
+`)
+	videos := videoList{{Title: "Video", Url: "https://www.youtube.com/watch?v=jNQXAC9IVRw", Author: "Channel", AuthorUrl: "https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/videos"}}
+	widget.setAuthorLinks(videos)
+	if videos[0].AuthorUrl != "https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw" {
+		t.Errorf("Expected the link to go to the channel's page, got %q", videos[0].AuthorUrl)
+	}
+
+	widget.AuthorLink = videosAuthorLinkNone
+	widget.setAuthorLinks(videos)
+	widget.Videos, widget.ContentAvailable = videos, true
+
+	if html := string(widget.Render()); strings.Contains(html, `href=""`) || !strings.Contains(html, "Channel") {
+		t.Error("Expected the author to be shown without a link")
+	}
+
+	invalid := &videosWidget{}
+	if err := yaml.Unmarshal([]byte("author-link: about\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\n"), invalid); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := invalid.initialize(); err == nil {
+		t.Fatal("Expected an error for an unknown author-link")
+	}
+}
@@ -0,0 +1,172 @@
+package glance
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// BitChute provides an RSS feed of the uploads of every channel, with the thumbnail
+// of each video as an image enclosure. Its links are sometimes relative to the site
+// and its dates don't always follow RFC 1123.
+
+const bitchuteBaseUrl = "https://www.bitchute.com"
+
+type bitchuteFeedResponseXml struct {
+	Channel     string `xml:"channel>title"`
+	ChannelLink string `xml:"channel>link"`
+	Videos      []struct {
+		Title     string `xml:"title"`
+		Published string `xml:"pubDate"`
+		Link      string `xml:"link"`
+		Enclosure struct {
+			Url  string `xml:"url,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"enclosure"`
+		Thumbnail struct {
+			Url string `xml:"url,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+		Categories []string `xml:"category"`
+	} `xml:"channel>item"`
+}
+
+// bitchuteChannelName returns the name of a channel from either the name itself or a
+// link to the channel
+func bitchuteChannelName(channel string) string {
+	channel = strings.TrimPrefix(channel, "https://")
+	channel = strings.TrimPrefix(channel, "www.")
+	channel = strings.TrimPrefix(channel, "bitchute.com/")
+	channel = strings.TrimPrefix(channel, "channel/")
+
+	return strings.Trim(channel, "/")
+}
+
+func bitchuteFeedUrl(channel string) string {
+	return bitchuteBaseUrl + "/feeds/rss/channel/" + bitchuteChannelName(channel) + "/"
+}
+
+// bitchuteAbsoluteUrl returns the link with the site prepended when it's relative
+func bitchuteAbsoluteUrl(link string) string {
+	link = strings.TrimSpace(link)
+	if strings.HasPrefix(link, "/") {
+		return bitchuteBaseUrl + link
+	}
+
+	return link
+}
+
+// parseBitchuteFeedTime parses the publish times of BitChute feeds, which are mostly
+// RFC 1123 but have been seen with named time zones other than GMT and without any
+func parseBitchuteFeedTime(t string) (time.Time, error) {
+	t = strings.TrimSpace(t)
+
+	if parsed, err := parseRumbleFeedTime(t); err == nil {
+		return parsed, nil
+	}
+
+	formats := []string{
+		time.RFC1123,
+		"Mon, 2 Jan 2006 15:04:05 MST",
+		"Mon, 2 Jan 2006 15:04:05 -0700",
+		"02 Jan 2006 15:04:05 -0700",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05",
+	}
+
+	for _, format := range formats {
+		parsedTime, err := time.Parse(format, t)
+		if err == nil {
+			return parsedTime, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unknown time format %q", t)
+}
+
+// fetchBitchuteChannelUploads fetches videos from BitChute channels
+func fetchBitchuteChannelUploads(ctx context.Context, sources []videoSourceField, client requestDoer, retry videoRetryOptions, workers int) (videoList, error) {
+	channels := videoSourceIDs(sources)
+	requests := make([]videoFeedRequest, 0, len(channels))
+
+	for i := range channels {
+		request, _ := http.NewRequestWithContext(ctx, "GET", bitchuteFeedUrl(channels[i]), nil)
+		requests = append(requests, videoFeedRequest{request: request, client: retry.wrap(sources[i].clientFor(client))})
+	}
+
+	job := newJob(decodeVideoFeedTask[bitchuteFeedResponseXml], requests).withWorkers(workers)
+	responses, errs, err := workerPoolDo(job)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNoContent, err)
+	}
+
+	videos := make(videoList, 0, len(channels)*15)
+	var failed []videoSourceError
+
+	for i := range responses {
+		if errs[i] != nil {
+			failed = append(failed, videoSourceError{source: channels[i], err: errs[i]})
+			slog.Error("Failed to fetch bitchute feed", "channel", channels[i], "error", errs[i])
+			continue
+		}
+
+		response := responses[i]
+
+		for j := range response.Videos {
+			v := &response.Videos[j]
+
+			if v.Title == "" || v.Link == "" {
+				continue
+			}
+
+			if sources[i].excludesTitle(v.Title) {
+				continue
+			}
+
+			timePosted, err := parseBitchuteFeedTime(v.Published)
+			if err != nil {
+				slog.Warn("Skipping BitChute video with invalid publish time", "channel", channels[i], "title", v.Title, "published", v.Published)
+				continue
+			}
+
+			thumbnailUrl := v.Thumbnail.Url
+			if thumbnailUrl == "" && (v.Enclosure.Type == "" || strings.HasPrefix(v.Enclosure.Type, "image/")) {
+				thumbnailUrl = v.Enclosure.Url
+			}
+			if thumbnailUrl == "" {
+				thumbnailUrl = videoThumbnailPlaceholder
+			}
+
+			videos = append(videos, video{
+				ThumbnailUrl: bitchuteAbsoluteUrl(thumbnailUrl),
+				Title:        v.Title,
+				Url:          bitchuteAbsoluteUrl(v.Link),
+				Author:       response.Channel,
+				AuthorUrl:    videoAuthorUrl(cmp.Or(bitchuteAbsoluteUrl(response.ChannelLink), bitchuteBaseUrl+"/channel/"+bitchuteChannelName(channels[i])), ""),
+				Source:       videoSourceBitchute,
+				sourceID:     sources[i].ID,
+				TimePosted:   timePosted,
+				Categories:   videoCategories(v.Categories...),
+			})
+		}
+	}
+
+	if len(videos) == 0 {
+		if len(failed) > 0 {
+			return nil, &videoSourceFailures{err: errNoContent, failed: failed, total: len(sources)}
+		}
+
+		return nil, errNoContent
+	}
+
+	videos.sortByNewest()
+
+	if len(failed) > 0 {
+		return videos, &videoSourceFailures{err: errPartialContent, failed: failed, total: len(sources)}
+	}
+
+	return videos, nil
+}
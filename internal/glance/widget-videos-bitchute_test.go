@@ -0,0 +1,55 @@
+package glance
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFetchBitchuteChannelUploads(t *testing.T) {
+	client := mapResponseDoer{
+		"https://www.bitchute.com/feeds/rss/channel/someone/": `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Someone</title>
+    <link>/channel/someone/</link>
+    <item>
+      <title>Relative link</title>
+      <link>/video/AbCdEf123/</link>
+      <pubDate>Tue, 14 Jan 2025 14:00:00 +0000</pubDate>
+      <enclosure url="https://static-3.bitchute.com/live/cover_images/AbCdEf123_640x360.jpg" type="image/jpeg" length="0"/>
+    </item>
+    <item>
+      <title>Named time zone</title>
+      <link>https://www.bitchute.com/video/GhIjKl456/</link>
+      <pubDate>Mon, 13 Jan 2025 09:00:00 EST</pubDate>
+    </item>
+    <item>
+      <title>Invalid date</title>
+      <link>https://www.bitchute.com/video/MnOpQr789/</link>
+      <pubDate>yesterday</pubDate>
+    </item>
+  </channel>
+</rss>`,
+	}
+
+	sources := []videoSourceField{{ID: "https://www.bitchute.com/channel/someone/"}, {ID: "missing"}}
+	videos, err := fetchBitchuteChannelUploads(context.Background(), sources, client, videoRetryOptions{}, videosDefaultConcurrency)
+	if !errors.Is(err, errPartialContent) || len(videos) != 2 {
+		t.Fatalf("Expected two videos and a partial content error, got %+v, %v", videos, err)
+	}
+
+	if v := videos[0]; v.Url != "https://www.bitchute.com/video/AbCdEf123/" || v.ThumbnailUrl != "https://static-3.bitchute.com/live/cover_images/AbCdEf123_640x360.jpg" || v.AuthorUrl != "https://www.bitchute.com/channel/someone" || v.Source != videoSourceBitchute {
+		t.Errorf("Unexpected video details: %+v", v)
+	}
+
+	if v := videos[1]; v.ThumbnailUrl != videoThumbnailPlaceholder || v.TimePosted.IsZero() {
+		t.Errorf("Unexpected video details: %+v", v)
+	}
+
+	for _, value := range []string{"Tue, 14 Jan 2025 14:00:00 GMT", "Tue, 14 Jan 2025 14:00:00 +0000", "Tue, 14 Jan 2025 14:00:00 UTC", "Tue, 4 Jan 2025 14:00:00 +0000", "2025-01-14 14:00:00"} {
+		if _, err := parseBitchuteFeedTime(value); err != nil {
+			t.Errorf("Expected %q to be parsed, got %v", value, err)
+		}
+	}
+}
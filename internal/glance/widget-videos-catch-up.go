@@ -0,0 +1,54 @@
+package glance
+
+import (
+	"strings"
+	"time"
+)
+
+// With mode set to catch-up, only the videos of each channel that were posted after the
+// newest one that was watched are shown, which turns the widget into a list of what's
+// left to catch up on. Channels without any watched videos show their recent videos.
+
+const videosModeCatchUp = "catch-up"
+
+// videoCatchUpKey tells channels apart by the source they were specified through,
+// falling back to their ID or name for videos that don't have one
+func videoCatchUpKey(v *video) string {
+	channel := v.sourceID
+	if channel == "" {
+		channel = v.ChannelID
+	}
+	if channel == "" {
+		channel = strings.ToLower(v.Author)
+	}
+
+	return videoSourceLimitKey(v.Source, channel)
+}
+
+// catchUp moves the mark of each channel forward to its newest watched video and leaves
+// out the videos that weren't posted after it. The marks are kept between updates, so
+// they stay in place once the watched videos are no longer in the feeds. Must be called
+// before watch-history hides the watched videos.
+func (widget *videosWidget) catchUp(videos videoList) videoList {
+	if widget.catchUpMarks == nil {
+		widget.catchUpMarks = make(map[string]time.Time)
+	}
+
+	for i := range videos {
+		v := &videos[i]
+
+		if v.TimePosted.IsZero() || !(widget.WatchHistory.isWatched(v) || widget.MarkWatched.isWatched(v)) {
+			continue
+		}
+
+		key := videoCatchUpKey(v)
+		if v.TimePosted.After(widget.catchUpMarks[key]) {
+			widget.catchUpMarks[key] = v.TimePosted
+		}
+	}
+
+	return videos.filter(func(v *video) bool {
+		mark, marked := widget.catchUpMarks[videoCatchUpKey(v)]
+		return !marked || v.TimePosted.IsZero() || v.TimePosted.After(mark)
+	})
+}
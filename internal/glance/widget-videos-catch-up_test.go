@@ -0,0 +1,96 @@
+package glance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestVideosWidgetCatchUpMode(t *testing.T) {
+	now := time.Now().UTC()
+	feedFor := func(channelID string, count int) string {
+		var entries strings.Builder
+		for i := range count {
+			fmt.Fprintf(&entries, `
+  <entry>
+    <title>%s %d</title>
+    <yt:videoId>%s-%d</yt:videoId>
+    <link href="https://www.youtube.com/watch?v=%s-%d"/>
+    <published>%s</published>
+  </entry>`, channelID, i, channelID, i, channelID, i, now.Add(-time.Duration(i+1)*time.Hour).Format("2006-01-02T15:04:05-07:00"))
+		}
+
+		return `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:yt="http://www.youtube.com/xml/schemas/2015">
+  <yt:channelId>` + channelID + `</yt:channelId>
+  <author><name>` + channelID + `</name></author>` + entries.String() + `
+</feed>`
+	}
+
+	newsVideos := 4
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("channel_id") {
+		case "UCnews":
+			w.Write([]byte(feedFor("UCnews", newsVideos)))
+		case "UCother":
+			w.Write([]byte(feedFor("UCother", 2)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+mode: catch-up
+include-shorts: true
+mark-watched:
+  enabled: true
+channels:
+  - UCnews
+  - UCother
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+
+	titles := func() []string {
+		titles := make([]string, 0, len(widget.Videos))
+		for _, v := range widget.Videos {
+			titles = append(titles, v.Title)
+		}
+		slices.Sort(titles)
+		return titles
+	}
+
+	widget.update(context.Background())
+	if len(widget.Videos) != 6 {
+		t.Fatalf("Expected all 6 videos before any were watched, got %v", titles())
+	}
+
+	widget.MarkWatched.store.markWatched("https://www.youtube.com/watch?v=UCnews-2")
+	widget.update(context.Background())
+
+	// Channels without a watched video keep showing their recent videos
+	expected := []string{"UCnews 0", "UCnews 1", "UCother 0", "UCother 1"}
+	if !slices.Equal(titles(), expected) {
+		t.Errorf("Expected videos %v, got %v", expected, titles())
+	}
+
+	// The mark stays in place once the watched video is no longer in the feed
+	newsVideos = 2
+	widget.update(context.Background())
+	if !slices.Equal(titles(), expected) {
+		t.Errorf("Expected videos %v after the watched video left the feed, got %v", expected, titles())
+	}
+
+	invalid := &videosWidget{}
+	yaml.Unmarshal([]byte("mode: catch-up\nchannels:\n  - UCnews"), invalid)
+	if err := invalid.initialize(); err == nil {
+		t.Error("Expected an error for catch-up mode without mark-watched or watch-history")
+	}
+}
@@ -0,0 +1,76 @@
+package glance
+
+import (
+	"slices"
+	"strings"
+)
+
+// Feeds don't agree on how videos are categorized, YouTube has keywords in the media
+// group while RSS feeds have a category element per category, so every source ends up
+// with a plain list of categories which include-categories and exclude-categories match.
+
+// How many of a video's categories are shown when show-categories is enabled, since
+// some feeds list dozens of keywords for each video
+const videoMaxShownCategories = 3
+
+// videoCategories returns the categories with surrounding whitespace removed, without
+// empty ones and without case-insensitive duplicates, in the order they first appear
+func videoCategories(categories ...string) []string {
+	result := make([]string, 0, len(categories))
+
+	for _, category := range categories {
+		category = strings.TrimSpace(category)
+		if category == "" {
+			continue
+		}
+
+		if slices.ContainsFunc(result, func(c string) bool { return strings.EqualFold(c, category) }) {
+			continue
+		}
+
+		result = append(result, category)
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+// splitVideoKeywords splits a comma separated list of keywords, as found in the
+// media:keywords element of feeds
+func splitVideoKeywords(keywords string) []string {
+	if strings.TrimSpace(keywords) == "" {
+		return nil
+	}
+
+	return strings.Split(keywords, ",")
+}
+
+// hasCategory reports whether any of the video's categories matches one of the given
+// lowercase categories, ignoring case
+func (v *video) hasCategory(categories []string) bool {
+	for _, category := range v.Categories {
+		if slices.Contains(categories, strings.ToLower(category)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// categoriesAllowed reports whether the video isn't in any of the excluded categories
+// and, when there are included categories, is in at least one of them. Videos whose
+// source doesn't provide categories are always kept.
+func (widget *videosWidget) categoriesAllowed(v *video) bool {
+	if len(v.Categories) == 0 {
+		return true
+	}
+
+	if v.hasCategory(widget.ExcludeCategories) {
+		return false
+	}
+
+	return len(widget.IncludeCategories) == 0 || v.hasCategory(widget.IncludeCategories)
+}
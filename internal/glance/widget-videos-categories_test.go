@@ -0,0 +1,78 @@
+package glance
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestVideosWidgetCategoryFilters(t *testing.T) {
+	feed := staticResponseDoer(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+  <yt:channelId>UCXuqSBlHAE6Xw-yeJA0Tunw</yt:channelId>
+  <author><name>Channel</name><uri>https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw</uri></author>
+  <entry>
+    <yt:videoId>dQw4w9WgXcQ</yt:videoId>
+    <title>Music video</title>
+    <published>2025-01-03T15:04:05+00:00</published>
+    <category term="Music"/>
+    <media:group><media:keywords>pop, music, 80s</media:keywords></media:group>
+  </entry>
+  <entry>
+    <yt:videoId>9bZkp7q19f0</yt:videoId>
+    <title>Live concert</title>
+    <published>2025-01-02T15:04:05+00:00</published>
+    <media:group><media:keywords>Music, Live</media:keywords></media:group>
+  </entry>
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Without categories</title>
+    <published>2025-01-01T15:04:05+00:00</published>
+  </entry>
+  <entry>
+    <yt:videoId>M7lc1UVf-VE</yt:videoId>
+    <title>Vlog</title>
+    <published>2024-12-31T15:04:05+00:00</published>
+    <category term="People &amp; Blogs"/>
+  </entry>
+</feed>`)
+
+	videos, err := fetchYoutubeChannelUploads(context.Background(), []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}}, "", "", true, youtubeThumbnailDefault, nil, feed, videoRetryOptions{}, videosDefaultConcurrency)
+	if err != nil {
+		t.Fatalf("Failed to fetch uploads: %v", err)
+	}
+
+	// Keywords come first and the category term that repeats one of them is left out
+	if expected := []string{"pop", "music", "80s"}; !slices.Equal(videos[0].Categories, expected) {
+		t.Errorf("Expected categories %v, got %v", expected, videos[0].Categories)
+	}
+
+	widget := newTestVideosWidget(t, `
+include-categories: [" MUSIC "]
+exclude-categories: [live]
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+`)
+
+	filtered := videos.filter(widget.categoriesAllowed)
+
+	result := make([]string, len(filtered))
+	for i := range filtered {
+		result[i] = filtered[i].Title
+	}
+
+	expected := []string{"Music video", "Without categories"}
+	if !slices.Equal(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+
+	shown := videos[0]
+	if len(shown.ShownCategories()) != 0 {
+		t.Errorf("Expected no categories to be shown without show-categories")
+	}
+
+	shown.showCategories = true
+	shown.Categories = []string{"a", "b", "c", "d"}
+	if expected := []string{"a", "b", "c"}; !slices.Equal(shown.ShownCategories(), expected) {
+		t.Errorf("Expected %v to be shown, got %v", expected, shown.ShownCategories())
+	}
+}
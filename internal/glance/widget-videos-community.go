@@ -0,0 +1,131 @@
+package glance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Community posts aren't available through YouTube's feeds, so they're fetched from
+// a user provided feed, such as one generated by RSS-Bridge, and shown alongside the
+// videos as cards without a playable URL.
+
+type communityFeedRequest struct {
+	ctx    context.Context
+	url    string
+	client requestDoer
+}
+
+// fetchLatestCommunityPostTask returns the most recent post of a community feed
+func fetchLatestCommunityPostTask(r communityFeedRequest) (video, error) {
+	request, err := http.NewRequestWithContext(r.ctx, "GET", r.url, nil)
+	if err != nil {
+		return video{}, err
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return video{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return video{}, fmt.Errorf("unexpected status code %d from %s", response.StatusCode, r.url)
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return video{}, err
+	}
+
+	feed, err := feedParser.ParseString(string(body))
+	if err != nil {
+		return video{}, err
+	}
+
+	var post video
+	found := false
+
+	for _, item := range feed.Items {
+		if item.PublishedParsed == nil || (found && !item.PublishedParsed.After(post.TimePosted)) {
+			continue
+		}
+
+		post = video{
+			Title:           html.UnescapeString(item.Title),
+			Url:             item.Link,
+			Author:          feed.Title,
+			AuthorUrl:       feed.Link,
+			Source:          videoSourceYoutube,
+			TimePosted:      *item.PublishedParsed,
+			IsCommunityPost: true,
+		}
+
+		if post.Title == "" {
+			post.Title = shortenFeedDescriptionLen(item.Description, 200)
+		}
+
+		if item.Author != nil && item.Author.Name != "" {
+			post.Author = item.Author.Name
+		}
+
+		post.ThumbnailUrl = findThumbnailInFeedItem(item)
+
+		found = true
+	}
+
+	if !found {
+		return video{}, errNoContent
+	}
+
+	return post, nil
+}
+
+// fetchCommunityPosts returns the latest community post of each of the YouTube
+// channels, playlists and unresolved handles are skipped since the feed URL
+// requires a channel ID
+func fetchCommunityPosts(ctx context.Context, feedUrl string, channels []videoSourceField, client requestDoer, retry videoRetryOptions, workers int) videoList {
+	requests := make([]communityFeedRequest, 0, len(channels))
+
+	for i := range channels {
+		if !strings.HasPrefix(channels[i].ID, "UC") {
+			continue
+		}
+
+		requests = append(requests, communityFeedRequest{
+			ctx:    ctx,
+			url:    strings.ReplaceAll(feedUrl, "{CHANNEL-ID}", channels[i].ID),
+			client: retry.wrap(channels[i].clientFor(client)),
+		})
+	}
+
+	if len(requests) == 0 {
+		return nil
+	}
+
+	job := newJob(fetchLatestCommunityPostTask, requests).withWorkers(workers)
+	posts, errs, err := workerPoolDo(job)
+	if err != nil {
+		slog.Error("Failed to fetch community posts", "error", err)
+		return nil
+	}
+
+	result := make(videoList, 0, len(posts))
+	for i := range posts {
+		if errs[i] != nil {
+			if !errors.Is(errs[i], errNoContent) {
+				slog.Error("Failed to fetch community feed", "url", requests[i].url, "error", errs[i])
+			}
+			continue
+		}
+
+		result = append(result, posts[i])
+	}
+
+	return result
+}
@@ -0,0 +1,61 @@
+package glance
+
+import (
+	"context"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestVideosWidgetCommunityPosts(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+include-community: true
+community-feed-url: https://bridge.example/community?channel={CHANNEL-ID}
+channels:
+  - UCXuqSBlHAE6Xw-yeJA0Tunw
+  - UCBJycsmduvYEL83R_U4JriQ
+  - "@handle"
+`)
+
+	client := mapResponseDoer{
+		"https://bridge.example/community?channel=UCXuqSBlHAE6Xw-yeJA0Tunw": `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+  <title>Some Channel</title>
+  <link href="https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/community"/>
+  <entry>
+    <title>Older post</title>
+    <link href="https://www.youtube.com/post/older"/>
+    <published>2025-01-01T15:04:05Z</published>
+  </entry>
+  <entry>
+    <title>Which video should we make next?</title>
+    <link href="https://www.youtube.com/post/latest"/>
+    <published>2025-01-02T15:04:05Z</published>
+    <media:thumbnail url="https://example.com/poll.jpg"/>
+  </entry>
+</feed>`,
+	}
+
+	posts := fetchCommunityPosts(context.Background(), widget.CommunityFeedUrl, widget.Channels, client, videoRetryOptions{}, videosDefaultConcurrency)
+	if len(posts) != 1 {
+		t.Fatalf("Expected a single post, got %v", posts)
+	}
+
+	post := posts[0]
+	if !post.IsCommunityPost || post.Title != "Which video should we make next?" || post.Url != "https://www.youtube.com/post/latest" {
+		t.Fatalf("Expected the latest post, got %+v", post)
+	}
+
+	if post.ThumbnailUrl != "https://example.com/poll.jpg" || post.Author != "Some Channel" {
+		t.Fatalf("Unexpected post details: %+v", post)
+	}
+
+	invalid := &videosWidget{}
+	if err := yaml.Unmarshal([]byte("include-community: true\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\n"), invalid); err != nil {
+		t.Fatalf("Failed to decode widget config: %v", err)
+	}
+
+	if err := invalid.initialize(); err == nil {
+		t.Fatal("Expected an error when include-community is set without a feed URL")
+	}
+}
@@ -0,0 +1,145 @@
+package glance
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Most feeds don't change between updates, so the ETag and Last-Modified headers of
+// each response are kept and sent back on the next request for the same URL. When the
+// server responds with 304 Not Modified, the previous response is reused as is.
+
+// Responses larger than this aren't kept since they'd have to be held in memory
+const videoConditionalMaxBodySize = 2 * 1024 * 1024
+
+type videoConditionalResponse struct {
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+}
+
+type videoConditionalCache struct {
+	mu        sync.Mutex
+	responses map[string]*videoConditionalResponse
+}
+
+func newVideoConditionalCache() *videoConditionalCache {
+	return &videoConditionalCache{responses: make(map[string]*videoConditionalResponse)}
+}
+
+func (c *videoConditionalCache) get(url string) *videoConditionalResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.responses[url]
+}
+
+func (c *videoConditionalCache) set(url string, response *videoConditionalResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.responses[url] = response
+}
+
+// clear forgets all responses so that the next requests download the feeds in full
+func (c *videoConditionalCache) clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.responses)
+}
+
+// wrap returns a client which makes conditional requests through the given one, or
+// the given one itself when there's no cache
+func (c *videoConditionalCache) wrap(client requestDoer) requestDoer {
+	if c == nil {
+		return client
+	}
+
+	return &conditionalRequestDoer{client: client, cache: c}
+}
+
+type conditionalRequestDoer struct {
+	client requestDoer
+	cache  *videoConditionalCache
+}
+
+func (d *conditionalRequestDoer) Do(request *http.Request) (*http.Response, error) {
+	if request.Method != http.MethodGet {
+		return d.client.Do(request)
+	}
+
+	url := request.URL.String()
+	cached := d.cache.get(url)
+
+	if cached != nil {
+		request = request.Clone(request.Context())
+		if cached.etag != "" {
+			request.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			request.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusNotModified && cached != nil {
+		response.Body.Close()
+
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         response.Proto,
+			ProtoMajor:    response.ProtoMajor,
+			ProtoMinor:    response.ProtoMinor,
+			Header:        cached.header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(cached.body)),
+			ContentLength: int64(len(cached.body)),
+			Request:       request,
+		}, nil
+	}
+
+	etag, lastModified := response.Header.Get("ETag"), response.Header.Get("Last-Modified")
+	if response.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return response, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, videoConditionalMaxBodySize+1))
+	if err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+
+	if len(body) > videoConditionalMaxBodySize {
+		// Hand over the rest of the body without keeping any of it
+		response.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), response.Body), response.Body}
+
+		return response, nil
+	}
+
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	d.cache.set(url, &videoConditionalResponse{
+		etag:         etag,
+		lastModified: lastModified,
+		header:       response.Header.Clone(),
+		body:         body,
+	})
+
+	return response, nil
+}
@@ -0,0 +1,57 @@
+package glance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestVideosConditionalRequests(t *testing.T) {
+	var fullResponses, notModified atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		fullResponses.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Vimeo / Someone's videos</title>
+    <link>https://vimeo.com/someone/videos</link>
+    <item>
+      <title>Short film</title>
+      <pubDate>Tue, 14 Jan 2025 14:00:00 -0500</pubDate>
+      <link>https://vimeo.com/123456</link>
+    </item>
+  </channel>
+</rss>`)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: redirectTransport{server: server}}
+	retry := videoRetryOptions{conditional: newVideoConditionalCache()}
+
+	for i := range 2 {
+		videos, err := fetchVimeoChannelUploads(context.Background(), []videoSourceField{{ID: "someone"}}, "", client, retry, videosDefaultConcurrency)
+		if err != nil || len(videos) != 1 || videos[0].Title != "Short film" {
+			t.Fatalf("Expected the same video on update %d, got %+v, %v", i+1, videos, err)
+		}
+	}
+
+	if fullResponses.Load() != 1 || notModified.Load() != 1 {
+		t.Errorf("Expected the second request to be conditional, got %d full and %d not modified responses", fullResponses.Load(), notModified.Load())
+	}
+
+	retry.conditional.clear()
+	if _, err := fetchVimeoChannelUploads(context.Background(), []videoSourceField{{ID: "someone"}}, "", client, retry, videosDefaultConcurrency); err != nil || fullResponses.Load() != 2 {
+		t.Errorf("Expected the feed to be downloaded in full after clearing the cache, got %v", err)
+	}
+}
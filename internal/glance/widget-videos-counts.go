@@ -0,0 +1,88 @@
+package glance
+
+import (
+	"fmt"
+	"strings"
+)
+
+// With show-counts, the header shows how many videos there are and how many channels
+// of each kind of source they come from, which makes sources that never contribute any
+// videos easy to notice.
+
+// How each kind of source is named in the counts
+var videoSourceCountLabels = map[string]string{
+	videoSourceYoutube:  "YouTube",
+	videoSourceRumble:   "Rumble",
+	videoSourceVimeo:    "Vimeo",
+	videoSourceTwitch:   "Twitch",
+	videoSourceOdysee:   "Odysee",
+	videoSourceNebula:   "Nebula",
+	videoSourceBitchute: "BitChute",
+	videoSourceFeed:     "feeds",
+}
+
+// videosCounts summarizes the videos of the last update
+type videosCounts struct {
+	videos   int
+	channels int
+	// How many of the channels are of each kind of source
+	bySource map[string]int
+}
+
+// countVideos counts the videos and the distinct channels they come from, telling
+// channels apart by their ID when the source provides one and by their name otherwise
+func countVideos(videos videoList) videosCounts {
+	counts := videosCounts{videos: len(videos), bySource: make(map[string]int)}
+	seen := make(map[string]struct{})
+
+	for i := range videos {
+		v := &videos[i]
+
+		channel := v.ChannelID
+		if channel == "" {
+			channel = strings.ToLower(v.Author)
+		}
+
+		key := v.Source + "\x00" + channel
+		if _, exists := seen[key]; exists {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		counts.channels++
+		counts.bySource[v.Source]++
+	}
+
+	return counts
+}
+
+func (c videosCounts) String() string {
+	if c.videos == 0 {
+		return ""
+	}
+
+	summary := pluralize(c.videos, "video") + " from " + pluralize(c.channels, "channel")
+
+	// The kind of source is only worth mentioning when there's more than one
+	if len(c.bySource) < 2 {
+		return summary
+	}
+
+	parts := make([]string, 0, len(c.bySource))
+	for _, source := range videoSources {
+		if count := c.bySource[source]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, videoSourceCountLabels[source]))
+		}
+	}
+
+	return summary + " (" + strings.Join(parts, ", ") + ")"
+}
+
+// Counts returns the summary shown in the header with show-counts, empty otherwise
+func (widget *videosWidget) Counts() string {
+	if !widget.ShowCounts {
+		return ""
+	}
+
+	return widget.counts.String()
+}
@@ -0,0 +1,36 @@
+package glance
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVideosWidgetCounts(t *testing.T) {
+	videos := videoList{
+		{Title: "a", Author: "Channel A", ChannelID: "UCa", Source: videoSourceYoutube},
+		{Title: "b", Author: "Channel A", ChannelID: "UCa", Source: videoSourceYoutube},
+		{Title: "c", Author: "Renamed", ChannelID: "UCb", Source: videoSourceYoutube},
+		{Title: "d", Author: "Rumbler", Source: videoSourceRumble},
+		{Title: "e", Author: "rumbler", Source: videoSourceRumble},
+	}
+
+	if summary, expected := countVideos(videos).String(), "5 videos from 3 channels (2 YouTube, 1 Rumble)"; summary != expected {
+		t.Errorf("Expected %q, got %q", expected, summary)
+	}
+
+	if summary, expected := countVideos(videos[:1]).String(), "1 video from 1 channel"; summary != expected {
+		t.Errorf("Expected %q, got %q", expected, summary)
+	}
+
+	widget := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	widget.Videos, widget.counts, widget.ContentAvailable = videos, countVideos(videos), true
+
+	if html := string(widget.Render()); strings.Contains(html, "videos-counts") {
+		t.Error("Expected no counts without show-counts")
+	}
+
+	widget.ShowCounts = true
+	if html := string(widget.Render()); !strings.Contains(html, "5 videos from 3 channels (2 YouTube, 1 Rumble)") {
+		t.Error("Expected the counts to be shown in the header")
+	}
+}
@@ -0,0 +1,198 @@
+package glance
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Which of the sources failed during the last update and why, so that a mistyped
+// channel ID can be told apart from a site that was briefly unreachable. Sources that
+// don't exist are logged once every update completes, while the full list is served
+// by the widget's diagnostics endpoint.
+
+// Returned for sources the site reported as not existing without responding with a
+// status code, such as Twitch logins that don't belong to any user
+var errVideoSourceNotFound = errors.New("source not found")
+
+// Reasons for which a source failed to be fetched
+const (
+	videoSourceFailureNotFound     = "not-found"
+	videoSourceFailureUnauthorized = "unauthorized"
+	videoSourceFailureStatus       = "status"
+	videoSourceFailureTimeout      = "timeout"
+	videoSourceFailureNetwork      = "network"
+	videoSourceFailureNotXml       = "not-xml"
+	videoSourceFailureInvalid      = "invalid-response"
+)
+
+// videoSourceError is the error of a single source, as collected by the fetchers
+type videoSourceError struct {
+	source string
+	err    error
+}
+
+// videoFeedStatusError is returned for feeds that responded with an unsuccessful status code
+type videoFeedStatusError struct {
+	status int
+	err    error
+}
+
+func (e *videoFeedStatusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *videoFeedStatusError) Unwrap() error {
+	return e.err
+}
+
+// errVideoFeedNotXml is returned for feeds that responded with a web page rather than
+// the feed, such as YouTube's consent page or an error page served with status 200
+var errVideoFeedNotXml = errors.New("feed returned non-XML content")
+
+// checkVideoFeedContent returns errVideoFeedNotXml when the successful response to a
+// feed request is a web page. Goes by the contents since some sites serve feeds with the
+// wrong Content-Type, which only counts when the contents don't look like a feed either.
+func checkVideoFeedContent(response *http.Response, body []byte) error {
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	isPage := mediaType == "text/html" || mediaType == "application/xhtml+xml"
+
+	if !strings.HasPrefix(http.DetectContentType(body), "text/html") && (!isPage || looksLikeXmlFeed(body)) {
+		return nil
+	}
+
+	err := fmt.Errorf("%w (%s)", errVideoFeedNotXml, cmp.Or(mediaType, "text/html"))
+
+	// Redirects to pages such as consent.youtube.com are only apparent from the final URL
+	if response.Request != nil {
+		err = fmt.Errorf("%w from %s", err, response.Request.URL)
+	}
+
+	return err
+}
+
+func looksLikeXmlFeed(body []byte) bool {
+	body = bytes.TrimSpace(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")))
+
+	for _, prefix := range []string{"<?xml", "<rss", "<feed", "<rdf:RDF"} {
+		if bytes.HasPrefix(body, []byte(prefix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// videoSourceFailure describes a source that failed to be fetched during the last update
+type videoSourceFailure struct {
+	Kind string `json:"kind"`
+	// Empty when none of the sources of the kind could be fetched for a reason that
+	// isn't specific to any of them
+	Source string `json:"source,omitempty"`
+	Reason string `json:"reason"`
+	// The status code of the response, when there was one
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error"`
+}
+
+func newVideoSourceFailure(kind string, source string, err error) videoSourceFailure {
+	reason, status := classifyVideoSourceError(err)
+
+	return videoSourceFailure{
+		Kind:   kind,
+		Source: source,
+		Reason: reason,
+		Status: status,
+		Error:  err.Error(),
+	}
+}
+
+// classifyVideoSourceError returns why a source failed to be fetched along with the
+// status code of the response, or 0 when there was none
+func classifyVideoSourceError(err error) (string, int) {
+	var statusErr *videoFeedStatusError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, errVideoSourceNotFound):
+		return videoSourceFailureNotFound, 0
+	case errors.Is(err, errTwitchUnauthorized), errors.Is(err, errNebulaUnauthorized):
+		return videoSourceFailureUnauthorized, 0
+	case errors.As(err, &statusErr):
+		if statusErr.status == http.StatusNotFound || statusErr.status == http.StatusGone {
+			return videoSourceFailureNotFound, statusErr.status
+		}
+
+		return videoSourceFailureStatus, statusErr.status
+	case errors.Is(err, errVideoFeedNotXml):
+		return videoSourceFailureNotXml, 0
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return videoSourceFailureTimeout, 0
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return videoSourceFailureTimeout, 0
+		}
+
+		return videoSourceFailureNetwork, 0
+	default:
+		return videoSourceFailureInvalid, 0
+	}
+}
+
+// logSourceFailures logs the sources that don't exist together, since unlike the
+// other failures they won't go away without changing the config
+func logSourceFailures(failures []videoSourceFailure) {
+	notFound := make([]string, 0)
+
+	for i := range failures {
+		if failures[i].Reason == videoSourceFailureNotFound && failures[i].Source != "" {
+			notFound = append(notFound, failures[i].Kind+":"+failures[i].Source)
+		}
+	}
+
+	if len(notFound) > 0 {
+		slog.Warn("Some video sources were not found, check that their IDs are correct", "sources", notFound)
+	}
+
+	if len(failures) > 0 {
+		slog.Debug("Video sources failed to be fetched", "failures", failures)
+	}
+}
+
+type videoSourceFailuresJson struct {
+	Failures []videoSourceFailure `json:"failures"`
+}
+
+// handleDiagnosticsRequest serves the sources that failed during the last update
+// when diagnostics are enabled
+func (widget *videosWidget) handleDiagnosticsRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !widget.Diagnostics {
+		http.Error(w, "diagnostics are not enabled for this widget", http.StatusNotFound)
+		return
+	}
+
+	widget.mu.RLock()
+	failures := slices.Clone(widget.lastSourceFailures)
+	widget.mu.RUnlock()
+
+	if failures == nil {
+		failures = make([]videoSourceFailure, 0)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(videoSourceFailuresJson{Failures: failures})
+}
@@ -0,0 +1,129 @@
+package glance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVideosWidgetDiagnostics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("channel_id") {
+		case "UCmistyped":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case "UCunreachable":
+			panic(http.ErrAbortHandler)
+		}
+
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	request := httptest.NewRequest("GET", "/api/widgets/1/diagnostics", nil)
+	request.SetPathValue("path", "diagnostics")
+
+	disabled := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	recorder := httptest.NewRecorder()
+	disabled.handleRequest(recorder, request)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404 without diagnostics enabled, got %d", recorder.Code)
+	}
+
+	widget := newTestVideosWidget(t, `
+diagnostics: true
+max-retries: -1
+include-shorts: true
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw, UCmistyped, UCunreachable]
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+	widget.update(context.Background())
+
+	recorder = httptest.NewRecorder()
+	widget.handleRequest(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+
+	var response videoSourceFailuresJson
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	reasons := make(map[string]videoSourceFailure)
+	for _, failure := range response.Failures {
+		reasons[failure.Source] = failure
+	}
+
+	if len(reasons) != 2 {
+		t.Fatalf("Expected 2 failed sources, got %+v", response.Failures)
+	}
+
+	if failure := reasons["UCmistyped"]; failure.Kind != videoSourceYoutube || failure.Reason != videoSourceFailureNotFound || failure.Status != http.StatusNotFound {
+		t.Errorf("Expected the mistyped channel to be reported as not found, got %+v", failure)
+	}
+
+	if failure := reasons["UCunreachable"]; failure.Reason != videoSourceFailureNetwork || failure.Status != 0 {
+		t.Errorf("Expected the unreachable channel to be reported as a network error, got %+v", failure)
+	}
+}
+
+func TestVideoFeedNonXmlContent(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:yt="http://www.youtube.com/xml/schemas/2015">
+  <author><name>Mislabeled</name><uri>https://www.youtube.com/channel/UCmislabeled</uri></author>
+  <entry>
+    <title>Video</title>
+    <yt:videoId>video</yt:videoId>
+    <link href="https://www.youtube.com/watch?v=video"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("channel_id") {
+		case "UCconsent":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<!DOCTYPE html><html><head><title>Before you continue</title></head></html>"))
+		case "UCunlabeled":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte("\n<html><body>Not found</body></html>"))
+		case "UCmislabeled":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(feed))
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: redirectTransport{server: server}}
+	sources := []videoSourceField{{ID: "UCconsent"}, {ID: "UCunlabeled"}, {ID: "UCmislabeled"}}
+
+	videos, err := fetchYoutubeChannelUploads(context.Background(), sources, "", "", true, youtubeThumbnailDefault, nil, client, videoRetryOptions{}, 1)
+	if len(videos) != 1 || videos[0].Title != "Video" {
+		t.Fatalf("Expected the feed served as HTML to still be decoded, got %+v", videos)
+	}
+
+	var failures *videoSourceFailures
+	if !errors.As(err, &failures) || len(failures.failed) != 2 {
+		t.Fatalf("Expected both web pages to fail, got %v", err)
+	}
+
+	for _, failure := range failures.failed {
+		reason, _ := classifyVideoSourceError(failure.err)
+		if !errors.Is(failure.err, errVideoFeedNotXml) || reason != videoSourceFailureNotXml || !strings.Contains(failure.err.Error(), "non-XML content") {
+			t.Errorf("Expected %s to fail with a non-XML content error, got %v", failure.source, failure.err)
+		}
+	}
+}
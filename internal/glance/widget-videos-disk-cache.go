@@ -0,0 +1,120 @@
+package glance
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+)
+
+// The videos of the last successful update can be kept on disk so that they can be
+// shown right away after a restart rather than only once they've been fetched again.
+
+// How long cached videos are considered fresh enough to not be fetched again on
+// startup, same as the cache duration of the first update
+const videosDiskCacheFreshFor = 5 * time.Minute
+
+type videosDiskCache struct {
+	FetchedAt time.Time `json:"fetched-at"`
+	// Used to tell whether the cache was written for the same sources, since
+	// changing them should not show the videos of the previous ones
+	Sources  []string    `json:"sources"`
+	Sections []videoList `json:"sections"`
+}
+
+// diskCacheSources returns the IDs of the sources of every section, which identify
+// the cached videos
+func (widget *videosWidget) diskCacheSources() []string {
+	sources := make([]string, 0)
+
+	for _, section := range widget.Sections() {
+		for _, list := range [][]videoSourceField{section.Channels, section.RumbleChannels, section.VimeoChannels, section.TwitchChannels, section.OdyseeChannels, section.NebulaChannels, section.BitchuteChannels, section.Feeds} {
+			sources = append(sources, videoSourceIDs(list)...)
+		}
+	}
+
+	return sources
+}
+
+// loadDiskCache shows the cached videos if there are any for the widget's current
+// sources. Stale videos are still shown, but get fetched again on the next update.
+func (widget *videosWidget) loadDiskCache() {
+	if widget.CacheFile == "" {
+		return
+	}
+
+	contents, err := os.ReadFile(widget.CacheFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		slog.Warn("Failed to read videos cache file", "path", widget.CacheFile, "error", err)
+		return
+	}
+
+	var cache videosDiskCache
+	if err := json.Unmarshal(contents, &cache); err != nil {
+		slog.Warn("Failed to parse videos cache file, ignoring it", "path", widget.CacheFile, "error", err)
+		return
+	}
+
+	sections := widget.Sections()
+	if !slices.Equal(cache.Sources, widget.diskCacheSources()) || len(cache.Sections) != len(sections) {
+		slog.Info("Ignoring videos cache file written for different sources", "path", widget.CacheFile)
+		return
+	}
+
+	var allVideos videoList
+	for i := range cache.Sections {
+		widget.setPlaceholderThumbnails(cache.Sections[i])
+		widget.setThumbnailOrientations(cache.Sections[i])
+		allVideos = append(allVideos, cache.Sections[i]...)
+		if len(widget.Groups) > 0 {
+			widget.Groups[i].Videos = cache.Sections[i]
+		}
+	}
+
+	if len(allVideos) == 0 {
+		return
+	}
+
+	widget.Videos = allVideos
+	widget.counts = countVideos(allVideos)
+	widget.LastFetchedAt = cache.FetchedAt
+	widget.ContentAvailable = true
+
+	if fresh := cache.FetchedAt.Add(videosDiskCacheFreshFor); fresh.After(time.Now()) {
+		widget.nextUpdate = fresh
+	}
+
+	slog.Info("Loaded videos from cache file", "path", widget.CacheFile, "video_count", len(allVideos), "fetched_at", cache.FetchedAt)
+}
+
+// saveDiskCache writes the videos of every section to the cache file. If that fails
+// the cache file is no longer used until the next restart.
+func (widget *videosWidget) saveDiskCache(lists []videoList) {
+	if widget.CacheFile == "" {
+		return
+	}
+
+	if err := writeVideosDiskCache(widget.CacheFile, videosDiskCache{
+		FetchedAt: time.Now(),
+		Sources:   widget.diskCacheSources(),
+		Sections:  lists,
+	}); err != nil {
+		slog.Warn("Failed to write videos cache file, no longer using it", "path", widget.CacheFile, "error", err)
+		widget.CacheFile = ""
+	}
+}
+
+// writeVideosDiskCache writes the cache to the file
+func writeVideosDiskCache(path string, cache videosDiskCache) error {
+	contents, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, contents, 0o600)
+}
@@ -0,0 +1,54 @@
+package glance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVideosWidgetDiskCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "videos.json")
+	config := "cache-file: " + path + "\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]"
+
+	widget := newTestVideosWidget(t, config)
+	if widget.ContentAvailable {
+		t.Fatal("Expected no content before anything was cached")
+	}
+
+	posted := time.Date(2025, 1, 14, 14, 0, 0, 0, time.UTC)
+	widget.saveDiskCache([]videoList{{{Title: "Cached", Url: "https://a", TimePosted: posted, Duration: time.Minute}}})
+
+	restarted := newTestVideosWidget(t, config)
+	if !restarted.ContentAvailable || len(restarted.Videos) != 1 {
+		t.Fatalf("Expected the cached videos to be available right away, got %+v", restarted.Videos)
+	}
+
+	if v := restarted.Videos[0]; v.Title != "Cached" || !v.TimePosted.Equal(posted) || v.Duration != time.Minute {
+		t.Errorf("Unexpected cached video: %+v", v)
+	}
+
+	if !restarted.nextUpdate.After(time.Now()) {
+		t.Error("Expected freshly cached videos to not be fetched again right away")
+	}
+
+	restarted.prefetch()
+	if restarted.prefetchDone != nil {
+		t.Error("Expected freshly cached videos to not be prefetched")
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected the cache file to only be readable by its owner, got %v", err)
+	}
+
+	changed := newTestVideosWidget(t, "cache-file: "+path+"\nchannels: [UCBJycsmduvYEL83R_U4JriQ]")
+	if changed.ContentAvailable {
+		t.Error("Expected videos cached for other sources to be ignored")
+	}
+
+	unwritable := newTestVideosWidget(t, "cache-file: "+filepath.Join(path, "missing", "videos.json")+"\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	unwritable.saveDiskCache([]videoList{{{Title: "Video", Url: "https://a"}}})
+	if unwritable.CacheFile != "" {
+		t.Error("Expected an unwritable cache file to no longer be used")
+	}
+}
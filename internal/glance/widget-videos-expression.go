@@ -0,0 +1,316 @@
+package glance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A tiny arithmetic expression language used by the videos widget's sort-expression
+// option. It only supports numbers, a fixed set of variables, the operators
+// + - * / ^, parentheses and a handful of pure math functions, so there is no way
+// for a user provided expression to do anything other than compute a number.
+
+var videoSortExpressionVariables = []string{"views", "age", "duration", "channel_boost"}
+
+var videoSortExpressionFunctions = map[string]struct {
+	args int
+	fn   func(args []float64) float64
+}{
+	"abs":  {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"sqrt": {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"log":  {1, func(a []float64) float64 { return math.Log1p(math.Max(a[0], 0)) }},
+	"min":  {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":  {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+}
+
+const videoSortExpressionMaxLength = 512
+
+type sortExpression interface {
+	eval(vars map[string]float64) float64
+}
+
+type sortExpressionNumber float64
+
+func (n sortExpressionNumber) eval(map[string]float64) float64 {
+	return float64(n)
+}
+
+type sortExpressionVariable string
+
+func (v sortExpressionVariable) eval(vars map[string]float64) float64 {
+	return vars[string(v)]
+}
+
+type sortExpressionUnary struct {
+	operand sortExpression
+}
+
+func (u *sortExpressionUnary) eval(vars map[string]float64) float64 {
+	return -u.operand.eval(vars)
+}
+
+type sortExpressionBinary struct {
+	op          byte
+	left, right sortExpression
+}
+
+func (b *sortExpressionBinary) eval(vars map[string]float64) float64 {
+	left, right := b.left.eval(vars), b.right.eval(vars)
+
+	switch b.op {
+	case '+':
+		return left + right
+	case '-':
+		return left - right
+	case '*':
+		return left * right
+	case '/':
+		if right == 0 {
+			return 0
+		}
+		return left / right
+	case '^':
+		return math.Pow(left, right)
+	}
+
+	return 0
+}
+
+type sortExpressionCall struct {
+	name string
+	args []sortExpression
+}
+
+func (c *sortExpressionCall) eval(vars map[string]float64) float64 {
+	args := make([]float64, len(c.args))
+	for i := range c.args {
+		args[i] = c.args[i].eval(vars)
+	}
+
+	return videoSortExpressionFunctions[c.name].fn(args)
+}
+
+// evalSortExpression evaluates the expression and ensures that the result is
+// always usable as a sort key
+func evalSortExpression(expr sortExpression, vars map[string]float64) float64 {
+	result := expr.eval(vars)
+	if math.IsNaN(result) {
+		return math.Inf(-1)
+	}
+
+	return result
+}
+
+type sortExpressionParser struct {
+	input string
+	pos   int
+}
+
+func parseSortExpression(input string) (sortExpression, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, errors.New("expression is empty")
+	}
+
+	if len(input) > videoSortExpressionMaxLength {
+		return nil, fmt.Errorf("expression exceeds %d characters", videoSortExpressionMaxLength)
+	}
+
+	p := &sortExpressionParser{input: input}
+	expr, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipWhitespace()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos+1)
+	}
+
+	return expr, nil
+}
+
+func (p *sortExpressionParser) skipWhitespace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *sortExpressionParser) peek() byte {
+	p.skipWhitespace()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+
+	return p.input[p.pos]
+}
+
+func (p *sortExpressionParser) parseSum() (sortExpression, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &sortExpressionBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *sortExpressionParser) parseProduct() (sortExpression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &sortExpressionBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *sortExpressionParser) parseUnary() (sortExpression, error) {
+	if p.peek() == '-' {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &sortExpressionUnary{operand: operand}, nil
+	}
+
+	return p.parsePower()
+}
+
+func (p *sortExpressionParser) parsePower() (sortExpression, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek() != '^' {
+		return base, nil
+	}
+	p.pos++
+
+	// right associative, so 2^3^2 is 2^(3^2)
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &sortExpressionBinary{op: '^', left: base, right: exponent}, nil
+}
+
+func (p *sortExpressionParser) parsePrimary() (sortExpression, error) {
+	c := p.peek()
+
+	switch {
+	case c == 0:
+		return nil, errors.New("unexpected end of expression")
+	case c == '(':
+		p.pos++
+		expr, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("expected ')' at position %d", p.pos+1)
+		}
+		p.pos++
+
+		return expr, nil
+	case c >= '0' && c <= '9' || c == '.':
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+			p.pos++
+		}
+
+		value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p.input[start:p.pos])
+		}
+
+		return sortExpressionNumber(value), nil
+	case unicode.IsLetter(rune(c)):
+		start := p.pos
+		for p.pos < len(p.input) && (unicode.IsLetter(rune(p.input[p.pos])) || p.input[p.pos] == '_') {
+			p.pos++
+		}
+		name := p.input[start:p.pos]
+
+		if p.peek() == '(' {
+			return p.parseCall(name)
+		}
+
+		for _, variable := range videoSortExpressionVariables {
+			if variable == name {
+				return sortExpressionVariable(name), nil
+			}
+		}
+
+		return nil, fmt.Errorf("unknown variable %q, available variables are %s", name, strings.Join(videoSortExpressionVariables, ", "))
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", c, p.pos+1)
+}
+
+func (p *sortExpressionParser) parseCall(name string) (sortExpression, error) {
+	function, exists := videoSortExpressionFunctions[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+
+	p.pos++ // (
+	call := &sortExpressionCall{name: name}
+
+	for {
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+
+		c := p.peek()
+		p.pos++
+
+		if c == ')' {
+			break
+		}
+
+		if c != ',' {
+			return nil, fmt.Errorf("expected ',' or ')' in call to %s", name)
+		}
+	}
+
+	if len(call.args) != function.args {
+		return nil, fmt.Errorf("%s expects %d argument(s), got %d", name, function.args, len(call.args))
+	}
+
+	return call, nil
+}
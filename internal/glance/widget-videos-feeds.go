@@ -0,0 +1,267 @@
+package glance
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	gofeedext "github.com/mmcdole/gofeed/extensions"
+)
+
+// Platforms such as PeerTube and video podcasts publish standard Atom or RSS feeds,
+// so rather than having an integration for each of them, any feed can be added as a
+// source and its entries get shown as videos.
+
+type genericVideoFeedRequest struct {
+	ctx    context.Context
+	url    string
+	client requestDoer
+}
+
+func fetchGenericVideoFeedTask(r genericVideoFeedRequest) (*gofeed.Feed, error) {
+	request, err := http.NewRequestWithContext(r.ctx, "GET", r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, &videoFeedStatusError{status: response.StatusCode, err: fmt.Errorf("unexpected status code %d from %s", response.StatusCode, r.url)}
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return feedParser.ParseString(string(body))
+}
+
+// fetchGenericVideoFeeds fetches the entries of Atom and RSS feeds as videos
+func fetchGenericVideoFeeds(ctx context.Context, sources []videoSourceField, client requestDoer, retry videoRetryOptions, workers int) (videoList, error) {
+	requests := make([]genericVideoFeedRequest, 0, len(sources))
+
+	for i := range sources {
+		requests = append(requests, genericVideoFeedRequest{
+			ctx:    ctx,
+			url:    sources[i].ID,
+			client: retry.wrap(sources[i].clientFor(client)),
+		})
+	}
+
+	job := newJob(fetchGenericVideoFeedTask, requests).withWorkers(workers)
+	feeds, errs, err := workerPoolDo(job)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNoContent, err)
+	}
+
+	videos := make(videoList, 0, len(sources)*15)
+	var failed []videoSourceError
+
+	for i := range feeds {
+		if errs[i] != nil {
+			failed = append(failed, videoSourceError{source: sources[i].ID, err: errs[i]})
+			slog.Error("Failed to fetch video feed", "url", sources[i].ID, "error", errs[i])
+			continue
+		}
+
+		videos = append(videos, videosFromGenericFeed(feeds[i], &sources[i])...)
+	}
+
+	if len(videos) == 0 {
+		if len(failed) > 0 {
+			return nil, &videoSourceFailures{err: errNoContent, failed: failed, total: len(sources)}
+		}
+
+		return nil, errNoContent
+	}
+
+	videos.sortByNewest()
+
+	if len(failed) > 0 {
+		return videos, &videoSourceFailures{err: errPartialContent, failed: failed, total: len(sources)}
+	}
+
+	return videos, nil
+}
+
+func videosFromGenericFeed(feed *gofeed.Feed, source *videoSourceField) videoList {
+	videos := make(videoList, 0, len(feed.Items))
+
+	for _, item := range feed.Items {
+		title := html.UnescapeString(item.Title)
+		url := item.Link
+		if url == "" {
+			url = findVideoEnclosureUrl(item)
+		}
+
+		if title == "" || url == "" || source.excludesTitle(title) {
+			continue
+		}
+
+		v := video{
+			ThumbnailUrl: findThumbnailInFeedItem(item),
+			Title:        title,
+			Url:          url,
+			Author:       feed.Title,
+			AuthorUrl:    feed.Link,
+			Source:       videoSourceFeed,
+			sourceID:     source.ID,
+			Duration:     findDurationInFeedItem(item),
+			Categories:   videoCategories(item.Categories...),
+		}
+
+		if item.Author != nil && item.Author.Name != "" {
+			v.Author = item.Author.Name
+		}
+
+		if item.PublishedParsed != nil {
+			v.TimePosted = *item.PublishedParsed
+		} else if item.UpdatedParsed != nil {
+			v.TimePosted = *item.UpdatedParsed
+		}
+
+		if v.ThumbnailUrl != "" {
+			v.ThumbnailWidth, v.ThumbnailHeight = findThumbnailSizeInFeedItem(item, v.ThumbnailUrl)
+		} else {
+			v.ThumbnailUrl = findThumbnailInFeed(feed)
+		}
+
+		if v.ThumbnailUrl == "" {
+			v.ThumbnailUrl = videoThumbnailPlaceholder
+		}
+
+		videos = append(videos, v)
+	}
+
+	return videos
+}
+
+// findThumbnailInFeedItem looks for the item's image, in order, in its media:thumbnail,
+// itunes:image and image enclosures
+func findThumbnailInFeedItem(item *gofeed.Item) string {
+	if item.Image != nil && item.Image.URL != "" {
+		return item.Image.URL
+	}
+
+	if url := findThumbnailInItemExtensions(item); url != "" {
+		return url
+	}
+
+	if item.ITunesExt != nil && item.ITunesExt.Image != "" {
+		return item.ITunesExt.Image
+	}
+
+	for _, enclosure := range item.Enclosures {
+		if strings.HasPrefix(enclosure.Type, "image/") {
+			return enclosure.URL
+		}
+	}
+
+	return ""
+}
+
+// findThumbnailSizeInFeedItem returns the width and height of the item's thumbnail from
+// the attributes of its media:thumbnail, or zeros when they aren't known
+func findThumbnailSizeInFeedItem(item *gofeed.Item, thumbnailUrl string) (int, int) {
+	media, ok := item.Extensions["media"]
+	if !ok {
+		return 0, 0
+	}
+
+	return recursiveFindThumbnailSizeInExtensions(media, thumbnailUrl)
+}
+
+func recursiveFindThumbnailSizeInExtensions(extensions map[string][]gofeedext.Extension, thumbnailUrl string) (int, int) {
+	for _, exts := range extensions {
+		for _, ext := range exts {
+			if (ext.Name == "thumbnail" || ext.Name == "image") && ext.Attrs["url"] == thumbnailUrl {
+				width, _ := strconv.Atoi(ext.Attrs["width"])
+				height, _ := strconv.Atoi(ext.Attrs["height"])
+				if width > 0 && height > 0 {
+					return width, height
+				}
+			}
+
+			if ext.Children != nil {
+				if width, height := recursiveFindThumbnailSizeInExtensions(ext.Children, thumbnailUrl); width > 0 {
+					return width, height
+				}
+			}
+		}
+	}
+
+	return 0, 0
+}
+
+// findThumbnailInFeed returns the image of the feed itself, which podcasts use
+// for all of their episodes
+func findThumbnailInFeed(feed *gofeed.Feed) string {
+	if feed.Image != nil && feed.Image.URL != "" {
+		return feed.Image.URL
+	}
+
+	if feed.ITunesExt != nil && feed.ITunesExt.Image != "" {
+		return feed.ITunesExt.Image
+	}
+
+	return ""
+}
+
+func findVideoEnclosureUrl(item *gofeed.Item) string {
+	for _, enclosure := range item.Enclosures {
+		if strings.HasPrefix(enclosure.Type, "video/") {
+			return enclosure.URL
+		}
+	}
+
+	return ""
+}
+
+// findDurationInFeedItem returns the duration from the item's itunes:duration or
+// from the duration attribute of its media:content
+func findDurationInFeedItem(item *gofeed.Item) time.Duration {
+	if item.ITunesExt != nil {
+		if duration := parseFeedDuration(item.ITunesExt.Duration); duration > 0 {
+			return duration
+		}
+	}
+
+	if media, ok := item.Extensions["media"]; ok {
+		return recursiveFindDurationInExtensions(media)
+	}
+
+	return 0
+}
+
+func recursiveFindDurationInExtensions(extensions map[string][]gofeedext.Extension) time.Duration {
+	for _, exts := range extensions {
+		for _, ext := range exts {
+			if ext.Name == "content" {
+				if duration := parseFeedDuration(ext.Attrs["duration"]); duration > 0 {
+					return duration
+				}
+			}
+
+			if ext.Children != nil {
+				if duration := recursiveFindDurationInExtensions(ext.Children); duration > 0 {
+					return duration
+				}
+			}
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,74 @@
+package glance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFetchGenericVideoFeeds(t *testing.T) {
+	client := mapResponseDoer{
+		"https://peertube.example/feeds/videos.atom": `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+  <title>PeerTube channel</title>
+  <link href="https://peertube.example/c/channel"/>
+  <entry>
+    <title>Atom video</title>
+    <link href="https://peertube.example/w/1"/>
+    <published>2025-01-14T14:00:00Z</published>
+    <media:group>
+      <media:content url="https://peertube.example/1.mp4" duration="245"/>
+      <media:thumbnail url="https://peertube.example/1.jpg"/>
+    </media:group>
+  </entry>
+</feed>`,
+		"https://podcast.example/feed.xml": `<?xml version="1.0" encoding="utf-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Video podcast</title>
+    <link>https://podcast.example</link>
+    <itunes:image href="https://podcast.example/cover.jpg"/>
+    <item>
+      <title>Episode with its own image</title>
+      <pubDate>Mon, 13 Jan 2025 14:00:00 +0000</pubDate>
+      <enclosure url="https://podcast.example/1.mp4" type="video/mp4" length="1"/>
+      <itunes:image href="https://podcast.example/1.jpg"/>
+      <itunes:duration>1:02:03</itunes:duration>
+    </item>
+    <item>
+      <title>Episode without an image</title>
+      <pubDate>Sun, 12 Jan 2025 14:00:00 +0000</pubDate>
+      <link>https://podcast.example/2</link>
+    </item>
+  </channel>
+</rss>`,
+	}
+
+	sources := []videoSourceField{{ID: "https://peertube.example/feeds/videos.atom"}, {ID: "https://podcast.example/feed.xml"}, {ID: "https://missing.example/feed"}}
+	videos, err := fetchGenericVideoFeeds(context.Background(), sources, client, videoRetryOptions{}, videosDefaultConcurrency)
+	if !errors.Is(err, errPartialContent) {
+		t.Fatalf("Expected partial content error, got %v", err)
+	}
+
+	if len(videos) != 3 {
+		t.Fatalf("Expected 3 videos, got %+v", videos)
+	}
+
+	expected := []video{
+		{Title: "Atom video", Url: "https://peertube.example/w/1", ThumbnailUrl: "https://peertube.example/1.jpg", Author: "PeerTube channel", Duration: 245 * time.Second},
+		{Title: "Episode with its own image", Url: "https://podcast.example/1.mp4", ThumbnailUrl: "https://podcast.example/1.jpg", Author: "Video podcast", Duration: time.Hour + 2*time.Minute + 3*time.Second},
+		{Title: "Episode without an image", Url: "https://podcast.example/2", ThumbnailUrl: "https://podcast.example/cover.jpg", Author: "Video podcast"},
+	}
+
+	for i := range expected {
+		v := videos[i]
+		if v.Title != expected[i].Title || v.Url != expected[i].Url || v.ThumbnailUrl != expected[i].ThumbnailUrl || v.Author != expected[i].Author || v.Duration != expected[i].Duration {
+			t.Errorf("Expected %+v, got %+v", expected[i], v)
+		}
+
+		if v.Source != videoSourceFeed || v.TimePosted.IsZero() {
+			t.Errorf("Unexpected video details: %+v", v)
+		}
+	}
+}
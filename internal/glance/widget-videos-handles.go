@@ -0,0 +1,194 @@
+package glance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// YouTube's feeds only accept channel IDs, so channels specified through their
+// @handle or their legacy custom URL have to be resolved to an ID first by looking
+// at the channel's page.
+
+// The page can get quite large, but the ID appears well before this
+const youtubeChannelPageMaxSize = 4 * 1024 * 1024
+
+var (
+	youtubeCanonicalChannelPattern = regexp.MustCompile(`<link rel="canonical" href="https://www\.youtube\.com/channel/(UC[\w-]{22})"`)
+	youtubeExternalIDPattern       = regexp.MustCompile(`"externalId":"(UC[\w-]{22})"`)
+)
+
+// normalizeYoutubeChannelEntry turns links to a channel or playlist into what the
+// widget expects:
+//
+//	https://www.youtube.com/@handle/videos            -> @handle
+//	https://www.youtube.com/channel/UC.../videos      -> UC...
+//	https://www.youtube.com/c/Name, .../user/Name     -> c/Name, user/Name
+//	https://www.youtube.com/playlist?list=PL...       -> playlist:PL...
+//	https://www.youtube.com/feeds/videos.xml?...      -> the channel or playlist of the feed
+//
+// Legacy custom URLs get resolved like handles. Other entries are returned as they are.
+func normalizeYoutubeChannelEntry(entry string) string {
+	if id, ok := youtubeSourceFromFeedUrl(entry); ok {
+		return id
+	}
+
+	rest := strings.TrimPrefix(entry, "https://")
+	rest = strings.TrimPrefix(rest, "http://")
+	rest = strings.TrimPrefix(rest, "www.")
+	rest = strings.TrimPrefix(rest, "m.")
+
+	rest, ok := strings.CutPrefix(rest, "youtube.com/")
+	if !ok {
+		return entry
+	}
+
+	path, query, _ := strings.Cut(rest, "?")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case strings.HasPrefix(segments[0], "@"):
+		return segments[0]
+	case len(segments) > 1 && segments[0] == "channel":
+		return segments[1]
+	case len(segments) > 1 && (segments[0] == "c" || segments[0] == "user"):
+		return segments[0] + "/" + segments[1]
+	case segments[0] == "playlist":
+		if values, err := url.ParseQuery(query); err == nil && values.Get("list") != "" {
+			return videosWidgetPlaylistPrefix + values.Get("list")
+		}
+	}
+
+	return entry
+}
+
+// isUnresolvedYoutubeChannel reports whether the entry is a handle or a legacy custom
+// URL, which have to be resolved to the ID of their channel
+func isUnresolvedYoutubeChannel(entry string) bool {
+	return strings.HasPrefix(entry, "@") || strings.HasPrefix(entry, "c/") || strings.HasPrefix(entry, "user/")
+}
+
+// resolveYoutubeHandle returns the ID of the channel with the given handle
+func resolveYoutubeHandle(ctx context.Context, handle string, client requestDoer) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", "https://www.youtube.com/"+handle, nil)
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("User-Agent", glanceUserAgentString)
+	// Skips the cookie consent page shown to visitors from some regions
+	request.Header.Set("Cookie", "SOCS=CAI")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d for %s", response.StatusCode, request.URL)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, youtubeChannelPageMaxSize))
+	if err != nil {
+		return "", err
+	}
+
+	return extractYoutubeChannelID(body)
+}
+
+func extractYoutubeChannelID(page []byte) (string, error) {
+	for _, pattern := range []*regexp.Regexp{youtubeCanonicalChannelPattern, youtubeExternalIDPattern} {
+		if match := pattern.FindSubmatch(page); match != nil {
+			return string(match[1]), nil
+		}
+	}
+
+	return "", errors.New("could not find the channel ID in the channel's page")
+}
+
+type youtubeHandleRequest struct {
+	ctx    context.Context
+	handle string
+	client requestDoer
+}
+
+func resolveYoutubeHandleTask(r youtubeHandleRequest) (string, error) {
+	return resolveYoutubeHandle(r.ctx, r.handle, r.client)
+}
+
+// resolveHandles returns the sources with handles replaced by the IDs of their
+// channels. Resolved handles are cached for the lifetime of the widget, while
+// handles that can't be resolved are left out and tried again on the next update.
+// Handles of channels that are also specified through their ID are left out as well.
+func (widget *videosWidget) resolveHandles(ctx context.Context, sources []videoSourceField) []videoSourceField {
+	if widget.resolvedHandles == nil {
+		widget.resolvedHandles = make(map[string]string)
+	}
+
+	requests := make([]youtubeHandleRequest, 0)
+
+	for i := range sources {
+		if !isUnresolvedYoutubeChannel(sources[i].ID) {
+			continue
+		}
+
+		if _, ok := widget.resolvedHandles[strings.ToLower(sources[i].ID)]; ok {
+			continue
+		}
+
+		requests = append(requests, youtubeHandleRequest{
+			ctx:    ctx,
+			handle: sources[i].ID,
+			client: widget.retryOptions().wrap(sources[i].clientFor(widget.httpClient())),
+		})
+	}
+
+	if len(requests) > 0 {
+		job := newJob(resolveYoutubeHandleTask, requests).withWorkers(min(10, widget.Concurrency))
+		ids, errs, err := workerPoolDo(job)
+		if err != nil {
+			slog.Error("Failed to resolve YouTube handles", "error", err)
+		} else {
+			for i := range ids {
+				if errs[i] != nil {
+					slog.Error("Failed to resolve YouTube handle, skipping it", "handle", requests[i].handle, "error", errs[i])
+					continue
+				}
+
+				widget.resolvedHandles[strings.ToLower(requests[i].handle)] = ids[i]
+			}
+		}
+	}
+
+	resolved := make([]videoSourceField, 0, len(sources))
+	seen := make(map[string]struct{}, len(sources))
+
+	for i := range sources {
+		source := sources[i]
+
+		if isUnresolvedYoutubeChannel(source.ID) {
+			id, ok := widget.resolvedHandles[strings.ToLower(source.ID)]
+			if !ok {
+				continue
+			}
+
+			source.ID = id
+		}
+
+		if _, exists := seen[source.ID]; exists {
+			continue
+		}
+
+		seen[source.ID] = struct{}{}
+		resolved = append(resolved, source)
+	}
+
+	return resolved
+}
@@ -0,0 +1,111 @@
+package glance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestVideosWidgetResolvesHandles(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+channels:
+  - https://www.youtube.com/@SomeHandle/videos
+  - youtube.com/@OtherHandle
+  - "@missing"
+  - UCBJycsmduvYEL83R_U4JriQ
+`)
+
+	if ids := videoSourceIDs(widget.Channels); !slices.Equal(ids, []string{"@SomeHandle", "@OtherHandle", "@missing", "UCBJycsmduvYEL83R_U4JriQ"}) {
+		t.Fatalf("Expected links to be turned into handles, got %v", ids)
+	}
+
+	var mu sync.Mutex
+	requested := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path]++
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/@SomeHandle":
+			w.Write([]byte(`<html><head><link rel="canonical" href="https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw"></head></html>`))
+		case "/@OtherHandle":
+			// Resolves to a channel that's also specified through its ID
+			w.Write([]byte(`<script>var ytInitialData = {"metadata":{"channelMetadataRenderer":{"externalId":"UCBJycsmduvYEL83R_U4JriQ"}}};</script>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+
+	for range 2 {
+		resolved := widget.resolveHandles(context.Background(), widget.Channels)
+		if ids := videoSourceIDs(resolved); !slices.Equal(ids, []string{"UCXuqSBlHAE6Xw-yeJA0Tunw", "UCBJycsmduvYEL83R_U4JriQ"}) {
+			t.Fatalf("Unexpected resolved channels %v", ids)
+		}
+	}
+
+	if requested["/@SomeHandle"] != 1 || requested["/@missing"] != 2 {
+		t.Fatalf("Expected resolved handles to be cached and failed ones retried, got %v", requested)
+	}
+}
+
+func TestNormalizeYoutubeChannelEntry(t *testing.T) {
+	tests := map[string]string{
+		"UCXuqSBlHAE6Xw-yeJA0Tunw":                                                     "UCXuqSBlHAE6Xw-yeJA0Tunw",
+		"https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw":                     "UCXuqSBlHAE6Xw-yeJA0Tunw",
+		"https://m.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/videos?view=0":         "UCXuqSBlHAE6Xw-yeJA0Tunw",
+		"youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/":                                "UCXuqSBlHAE6Xw-yeJA0Tunw",
+		"https://www.youtube.com/@SomeHandle/featured":                                 "@SomeHandle",
+		"https://www.youtube.com/c/CustomName":                                         "c/CustomName",
+		"http://youtube.com/user/LegacyUser/videos":                                    "user/LegacyUser",
+		"https://www.youtube.com/playlist?list=PL8mG-RkN2uTyZZ00ObwZxxoG_nJbs3qec":     videosWidgetPlaylistPrefix + "PL8mG-RkN2uTyZZ00ObwZxxoG_nJbs3qec",
+		"https://www.youtube.com/feeds/videos.xml?channel_id=UCBJycsmduvYEL83R_U4JriQ": "UCBJycsmduvYEL83R_U4JriQ",
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ":                                  "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+	}
+
+	for entry, expected := range tests {
+		if result := normalizeYoutubeChannelEntry(entry); result != expected {
+			t.Errorf("Expected %q to be normalized to %q, got %q", entry, expected, result)
+		}
+	}
+
+	widget := newTestVideosWidget(t, `
+channels:
+  - https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/videos
+  - https://www.youtube.com/c/CustomName
+  - https://www.youtube.com/c/customname/videos
+playlists:
+  - https://www.youtube.com/playlist?list=PL8mG-RkN2uTyZZ00ObwZxxoG_nJbs3qec
+`)
+
+	expected := []string{"UCXuqSBlHAE6Xw-yeJA0Tunw", "c/CustomName", videosWidgetPlaylistPrefix + "PL8mG-RkN2uTyZZ00ObwZxxoG_nJbs3qec"}
+	if ids := videoSourceIDs(widget.Channels); !slices.Equal(ids, expected) {
+		t.Fatalf("Expected %v, got %v", expected, ids)
+	}
+
+	// Legacy custom URLs are resolved through the channel's page like handles
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/c/CustomName" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(`<link rel="canonical" href="https://www.youtube.com/channel/UCBJycsmduvYEL83R_U4JriQ">`))
+	}))
+	defer server.Close()
+
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+
+	resolved := widget.resolveHandles(context.Background(), widget.Channels)
+	expected = []string{"UCXuqSBlHAE6Xw-yeJA0Tunw", "UCBJycsmduvYEL83R_U4JriQ", videosWidgetPlaylistPrefix + "PL8mG-RkN2uTyZZ00ObwZxxoG_nJbs3qec"}
+	if ids := videoSourceIDs(resolved); !slices.Equal(ids, expected) {
+		t.Fatalf("Expected %v, got %v", expected, ids)
+	}
+}
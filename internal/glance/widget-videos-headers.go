@@ -0,0 +1,98 @@
+package glance
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"slices"
+	"strings"
+)
+
+// Headers that are set by the client itself and would break requests when overridden
+var videoReservedRequestHeaders = []string{"Host", "Content-Length", "Transfer-Encoding", "Connection"}
+
+// parseVideoRequestHeaders validates the headers from the config and returns them in
+// their canonical form. Errors only ever mention the name of a header since the values
+// are usually cookies or tokens.
+func parseVideoRequestHeaders(headers map[string]string) (http.Header, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	parsed := make(http.Header, len(headers))
+
+	for name, value := range headers {
+		if !isValidHeaderName(name) {
+			return nil, fmt.Errorf("invalid header name %q", name)
+		}
+
+		name = textproto.CanonicalMIMEHeaderKey(name)
+		if slices.Contains(videoReservedRequestHeaders, name) {
+			return nil, fmt.Errorf("header %s can't be set", name)
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, fmt.Errorf("header %s has an empty value", name)
+		}
+
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return nil, fmt.Errorf("header %s contains a line break", name)
+		}
+
+		parsed.Set(name, value)
+	}
+
+	return parsed, nil
+}
+
+// isValidHeaderName reports whether the name only consists of the characters allowed
+// in an HTTP token
+func isValidHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, r := range name {
+		if r > 0x7e || r <= 0x20 || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// redactedHeaderNames returns the names of the headers for logging, without their values
+func redactedHeaderNames(headers http.Header) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// setRequestHeaders adds the headers to the request, replacing any it already has
+func setRequestHeaders(request *http.Request, headers http.Header) {
+	for name, values := range headers {
+		request.Header[name] = slices.Clone(values)
+	}
+}
+
+// videoUserAgentDoer sets the User-Agent of requests that don't have one, since some
+// sites reject requests with Go's default user agent
+type videoUserAgentDoer struct {
+	client    requestDoer
+	userAgent string
+}
+
+func (d *videoUserAgentDoer) Do(request *http.Request) (*http.Response, error) {
+	if request.Header.Get("User-Agent") == "" {
+		request.Header.Set("User-Agent", cmp.Or(d.userAgent, getBrowserUserAgentHeader()))
+	}
+
+	return d.client.Do(request)
+}
@@ -0,0 +1,96 @@
+package glance
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestVideoFeedRequestsUserAgent(t *testing.T) {
+	var mu sync.Mutex
+	userAgents := make(map[string]string)
+
+	client := requestDoerFunc(func(request *http.Request) (*http.Response, error) {
+		mu.Lock()
+		userAgents[request.URL.Host] = request.Header.Get("User-Agent")
+		mu.Unlock()
+
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("not found"))}, nil
+	})
+
+	sources := []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}}
+	fetchYoutubeChannelUploads(context.Background(), sources, "", "", true, "", nil, client, videoRetryOptions{}, videosDefaultConcurrency)
+	if userAgent := userAgents["www.youtube.com"]; !strings.HasPrefix(userAgent, "Mozilla/5.0") {
+		t.Errorf("Expected a browser user agent by default, got %q", userAgent)
+	}
+
+	retry := videoRetryOptions{userAgent: "CustomAgent/1.0"}
+	fetchYoutubeChannelUploads(context.Background(), sources, "", "", true, "", nil, client, retry, videosDefaultConcurrency)
+	fetchRumbleChannelUploads(context.Background(), []videoSourceField{{ID: "Channel"}}, "", nil, client, retry, videosDefaultConcurrency)
+
+	for _, host := range []string{"www.youtube.com", "rumble.com"} {
+		if userAgent := userAgents[host]; userAgent != "CustomAgent/1.0" {
+			t.Errorf("Expected the configured user agent for %s, got %q", host, userAgent)
+		}
+	}
+
+	headers := http.Header{"User-Agent": {"HeaderAgent/1.0"}}
+	fetchYoutubeChannelUploads(context.Background(), sources, "", "", true, "", headers, client, retry, videosDefaultConcurrency)
+	if userAgent := userAgents["www.youtube.com"]; userAgent != "HeaderAgent/1.0" {
+		t.Errorf("Expected youtube-headers to take precedence, got %q", userAgent)
+	}
+}
+
+func TestVideosWidgetYoutubeHeaders(t *testing.T) {
+	var cookie atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie.Store(r.Header.Get("Cookie"))
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:yt="http://www.youtube.com/xml/schemas/2015">
+  <author><name>Channel</name><uri>https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw</uri></author>
+  <entry>
+    <title>Unlisted video</title>
+    <yt:videoId>unlisted</yt:videoId>
+    <link href="https://www.youtube.com/watch?v=unlisted"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+playlists: [PLunlisted]
+youtube-headers:
+  cookie: SID=secret
+`)
+
+	client := &http.Client{Transport: redirectTransport{server: server}}
+	if _, err := fetchYoutubeChannelUploads(context.Background(), widget.Playlists, "", "", true, youtubeThumbnailDefault, widget.youtubeHeaders, client, videoRetryOptions{}, videosDefaultConcurrency); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cookie.Load() != "SID=secret" {
+		t.Errorf("Expected the configured cookie to be sent, got %v", cookie.Load())
+	}
+
+	for _, headers := range []string{"{'bad header': value}", "{cookie: \"a\\r\\nX-Injected: b\"}", "{host: example.com}", "{cookie: ''}"} {
+		invalid := &videosWidget{}
+		if err := yaml.Unmarshal([]byte("channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\nyoutube-headers: "+headers), invalid); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		err := invalid.initialize()
+		if err == nil {
+			t.Errorf("Expected %s to be rejected", headers)
+		} else if strings.Contains(err.Error(), "X-Injected") {
+			t.Errorf("Expected the error to leave out the header's value, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+package glance
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Instead of YouTube's feeds, the uploads of channels can be listed through the API of
+// an Invidious or Piped instance, which keeps the requests away from YouTube and isn't
+// limited to the latest 15 videos. Playlists are always fetched from their feeds.
+
+// Values of videosWidget.YoutubeBackend
+const (
+	youtubeBackendRss       = "rss"
+	youtubeBackendInvidious = "invidious"
+	youtubeBackendPiped     = "piped"
+)
+
+type invidiousVideoJson struct {
+	Type            string `json:"type"`
+	Title           string `json:"title"`
+	VideoID         string `json:"videoId"`
+	Author          string `json:"author"`
+	AuthorID        string `json:"authorId"`
+	VideoThumbnails []struct {
+		Quality string `json:"quality"`
+		Url     string `json:"url"`
+	} `json:"videoThumbnails"`
+	Published         int64 `json:"published"`
+	LengthSeconds     int   `json:"lengthSeconds"`
+	ViewCount         int   `json:"viewCount"`
+	LiveNow           bool  `json:"liveNow"`
+	IsUpcoming        bool  `json:"isUpcoming"`
+	PremiereTimestamp int64 `json:"premiereTimestamp"`
+}
+
+type invidiousChannelVideosJson struct {
+	Videos []invidiousVideoJson `json:"videos"`
+}
+
+func (r *invidiousChannelVideosJson) UnmarshalJSON(data []byte) error {
+	// Older instances respond with the list of videos itself
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, &r.Videos)
+	}
+
+	type invidiousChannelVideosAlias invidiousChannelVideosJson
+	return json.Unmarshal(data, (*invidiousChannelVideosAlias)(r))
+}
+
+type pipedChannelJson struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	RelatedStreams []struct {
+		Url          string `json:"url"`
+		Type         string `json:"type"`
+		Title        string `json:"title"`
+		Thumbnail    string `json:"thumbnail"`
+		UploaderName string `json:"uploaderName"`
+		UploaderUrl  string `json:"uploaderUrl"`
+		// Milliseconds since the epoch
+		Uploaded int64 `json:"uploaded"`
+		// In seconds, -1 for ongoing livestreams
+		Duration int  `json:"duration"`
+		Views    int  `json:"views"`
+		IsShort  bool `json:"isShort"`
+	} `json:"relatedStreams"`
+}
+
+// youtubeBackendVideo is a video as listed by either kind of instance
+type youtubeBackendVideo struct {
+	videoID      string
+	title        string
+	author       string
+	channelID    string
+	thumbnailUrl string
+	timePosted   time.Time
+	duration     time.Duration
+	views        int
+	isLive       bool
+	isShort      bool
+}
+
+// youtubeBackendResponse is implemented by the responses of the instances' APIs
+type youtubeBackendResponse interface {
+	backendVideos(instanceUrl string) []youtubeBackendVideo
+}
+
+func (r invidiousChannelVideosJson) backendVideos(instanceUrl string) []youtubeBackendVideo {
+	videos := make([]youtubeBackendVideo, 0, len(r.Videos))
+
+	for i := range r.Videos {
+		v := &r.Videos[i]
+		if v.Type != "" && v.Type != "video" {
+			continue
+		}
+
+		published := v.Published
+		if v.IsUpcoming && v.PremiereTimestamp > 0 {
+			published = v.PremiereTimestamp
+		}
+
+		var timePosted time.Time
+		if published > 0 {
+			timePosted = time.Unix(published, 0)
+		}
+
+		var thumbnailUrl string
+		for _, thumbnail := range v.VideoThumbnails {
+			if thumbnail.Quality == "high" || thumbnailUrl == "" {
+				thumbnailUrl = thumbnail.Url
+			}
+		}
+
+		videos = append(videos, youtubeBackendVideo{
+			videoID:      v.VideoID,
+			title:        v.Title,
+			author:       v.Author,
+			channelID:    v.AuthorID,
+			thumbnailUrl: resolveYoutubeBackendUrl(instanceUrl, thumbnailUrl),
+			timePosted:   timePosted,
+			duration:     time.Duration(v.LengthSeconds) * time.Second,
+			views:        v.ViewCount,
+			isLive:       v.LiveNow,
+		})
+	}
+
+	return videos
+}
+
+func (r pipedChannelJson) backendVideos(instanceUrl string) []youtubeBackendVideo {
+	videos := make([]youtubeBackendVideo, 0, len(r.RelatedStreams))
+
+	for i := range r.RelatedStreams {
+		v := &r.RelatedStreams[i]
+		if v.Type != "" && v.Type != "stream" {
+			continue
+		}
+
+		var timePosted time.Time
+		if v.Uploaded > 0 {
+			timePosted = time.UnixMilli(v.Uploaded)
+		}
+
+		channelID := cmp.Or(extractYoutubeChannelIDFromUrl(v.UploaderUrl), r.ID)
+
+		videos = append(videos, youtubeBackendVideo{
+			videoID:      extractYoutubeVideoID(v.Url),
+			title:        v.Title,
+			author:       cmp.Or(v.UploaderName, r.Name),
+			channelID:    channelID,
+			thumbnailUrl: resolveYoutubeBackendUrl(instanceUrl, v.Thumbnail),
+			timePosted:   timePosted,
+			duration:     time.Duration(max(v.Duration, 0)) * time.Second,
+			views:        max(v.Views, 0),
+			isLive:       v.Duration < 0,
+			isShort:      v.IsShort,
+		})
+	}
+
+	return videos
+}
+
+// resolveYoutubeBackendUrl turns the links the instances give relative to themselves
+// into absolute ones
+func resolveYoutubeBackendUrl(instanceUrl string, link string) string {
+	switch {
+	case strings.HasPrefix(link, "//"):
+		return "https:" + link
+	case strings.HasPrefix(link, "/"):
+		return instanceUrl + link
+	}
+
+	return link
+}
+
+func invidiousChannelVideosUrl(instanceUrl string, channelID string) string {
+	return instanceUrl + "/api/v1/channels/" + url.PathEscape(channelID) + "/videos"
+}
+
+func pipedChannelUrl(instanceUrl string, channelID string) string {
+	return instanceUrl + "/channel/" + url.PathEscape(channelID)
+}
+
+type youtubeBackendRequest struct {
+	request *http.Request
+	client  requestDoer
+}
+
+func decodeYoutubeBackendTask[T youtubeBackendResponse](r youtubeBackendRequest) (T, error) {
+	var result T
+
+	response, err := r.client.Do(r.request)
+	if err != nil {
+		return result, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return result, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		truncatedBody, _ := limitStringLength(string(body), 256)
+		err := fmt.Errorf("unexpected status code %d from %s, response: %s", response.StatusCode, r.request.URL, truncatedBody)
+		return result, &videoFeedStatusError{status: response.StatusCode, err: err}
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// fetchYoutubeViaInvidious fetches the uploads of YouTube channels through the API of an
+// Invidious instance, which lists the channel's videos tab and so never includes shorts
+func fetchYoutubeViaInvidious(ctx context.Context, sources []videoSourceField, instanceUrl string, videoUrlTemplate string, authorUrlTemplate string, thumbnailQuality string, client requestDoer, retry videoRetryOptions, workers int) (videoList, error) {
+	return fetchYoutubeViaBackend[invidiousChannelVideosJson](ctx, sources, instanceUrl, invidiousChannelVideosUrl, videoUrlTemplate, authorUrlTemplate, true, thumbnailQuality, client, retry, workers)
+}
+
+// fetchYoutubeViaPiped fetches the uploads of YouTube channels through the API of a Piped instance
+func fetchYoutubeViaPiped(ctx context.Context, sources []videoSourceField, instanceUrl string, videoUrlTemplate string, authorUrlTemplate string, includeShorts bool, thumbnailQuality string, client requestDoer, retry videoRetryOptions, workers int) (videoList, error) {
+	return fetchYoutubeViaBackend[pipedChannelJson](ctx, sources, instanceUrl, pipedChannelUrl, videoUrlTemplate, authorUrlTemplate, includeShorts, thumbnailQuality, client, retry, workers)
+}
+
+func fetchYoutubeViaBackend[T youtubeBackendResponse](ctx context.Context, sources []videoSourceField, instanceUrl string, channelUrl func(string, string) string, videoUrlTemplate string, authorUrlTemplate string, includeShorts bool, thumbnailQuality string, client requestDoer, retry videoRetryOptions, workers int) (videoList, error) {
+	requests := make([]youtubeBackendRequest, 0, len(sources))
+	for i := range sources {
+		request, _ := http.NewRequestWithContext(ctx, "GET", channelUrl(instanceUrl, sources[i].ID), nil)
+		request.Header.Set("Accept", "application/json")
+		requests = append(requests, youtubeBackendRequest{request: request, client: retry.wrap(sources[i].clientFor(client))})
+	}
+
+	job := newJob(decodeYoutubeBackendTask[T], requests).withWorkers(workers)
+	responses, errs, err := workerPoolDo(job)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNoContent, err)
+	}
+
+	videos := make(videoList, 0, len(sources)*30)
+	var failed []videoSourceError
+
+	for i := range responses {
+		source := &sources[i]
+
+		if errs[i] != nil {
+			failed = append(failed, videoSourceError{source: source.ID, err: errs[i]})
+			slog.Error("Failed to fetch youtube channel from instance", "channel", source.ID, "instance", instanceUrl, "error", errs[i])
+			continue
+		}
+
+		for _, v := range responses[i].backendVideos(instanceUrl) {
+			if v.videoID == "" || v.title == "" || source.excludesTitle(v.title) || (v.isShort && !includeShorts) {
+				continue
+			}
+
+			if v.timePosted.IsZero() {
+				slog.Warn("Skipping YouTube video without a publish time", "channel", source.ID, "title", v.title)
+				continue
+			}
+
+			videoUrl := "https://www.youtube.com/watch?v=" + v.videoID
+			if videoUrlTemplate != "" {
+				videoUrl = strings.ReplaceAll(videoUrlTemplate, "{VIDEO-ID}", v.videoID)
+			}
+
+			thumbnailUrl := v.thumbnailUrl
+			if url := youtubeThumbnailUrl(v.videoID, thumbnailQuality); url != "" {
+				thumbnailUrl = url
+			}
+			if thumbnailUrl == "" {
+				thumbnailUrl = "https://i.ytimg.com/vi/" + v.videoID + "/hqdefault.jpg"
+			}
+
+			channelID := cmp.Or(v.channelID, source.ID)
+
+			videos = append(videos, video{
+				ThumbnailUrl: thumbnailUrl,
+				Title:        v.title,
+				Url:          videoUrl,
+				Author:       cmp.Or(source.Name, v.author),
+				AuthorUrl:    youtubeAuthorUrl(authorUrlTemplate, channelID, "https://www.youtube.com/channel/"+channelID),
+				ChannelID:    channelID,
+				VideoID:      v.videoID,
+				Source:       videoSourceYoutube,
+				sourceID:     source.ID,
+				TimePosted:   v.timePosted,
+				Views:        v.views,
+				Duration:     v.duration,
+				IsLive:       v.isLive,
+			})
+		}
+	}
+
+	if len(videos) == 0 {
+		if len(failed) > 0 {
+			return nil, &videoSourceFailures{err: errNoContent, failed: failed, total: len(sources)}
+		}
+
+		return nil, errNoContent
+	}
+
+	videos.sortByNewest()
+
+	if len(failed) > 0 {
+		return videos, &videoSourceFailures{err: errPartialContent, failed: failed, total: len(sources)}
+	}
+
+	return videos, nil
+}
+
+// fetchYoutubeBackend fetches the given channels through the configured instance
+func (widget *videosWidget) fetchYoutubeBackend(ctx context.Context, channels []videoSourceField) (videoList, error) {
+	client, retry := widget.httpClient(), widget.retryOptions()
+
+	if widget.YoutubeBackend == youtubeBackendPiped {
+		return fetchYoutubeViaPiped(ctx, channels, widget.YoutubeBackendUrl, widget.VideoUrlTemplate, widget.AuthorUrlTemplate, widget.IncludeShorts, widget.ThumbnailQuality, client, retry, widget.Concurrency)
+	}
+
+	return fetchYoutubeViaInvidious(ctx, channels, widget.YoutubeBackendUrl, widget.VideoUrlTemplate, widget.AuthorUrlTemplate, widget.ThumbnailQuality, client, retry, widget.Concurrency)
+}
+
+// fetchYoutubeChannelsViaBackend fetches the channels among the sources through the
+// configured instance. Returns the sources that are left to be fetched from their
+// feeds, which are the playlists along with the channels that failed to be fetched
+// when falling back to the feeds is enabled.
+func (widget *videosWidget) fetchYoutubeChannelsViaBackend(ctx context.Context, sources []videoSourceField) (videoList, []videoSourceField) {
+	remaining := make([]videoSourceField, 0, len(sources))
+	channels := make([]videoSourceField, 0, len(sources))
+
+	for i := range sources {
+		if strings.HasPrefix(sources[i].ID, "UC") {
+			channels = append(channels, sources[i])
+		} else {
+			remaining = append(remaining, sources[i])
+		}
+	}
+
+	if len(channels) == 0 {
+		return nil, remaining
+	}
+
+	videos, err := widget.fetchYoutubeBackend(ctx, channels)
+	if !widget.YoutubeFallback {
+		widget.recordSourceFailures(videoSourceYoutube, err, len(channels))
+		// Partial results still contain the videos of the channels that were fetched
+		if err != nil && !errors.Is(err, errPartialContent) {
+			slog.Error("Failed to fetch YouTube videos", "backend", widget.YoutubeBackend, "error", err)
+		}
+
+		return videos, remaining
+	}
+
+	// The channels that failed are fetched from their feeds instead, and only count as
+	// failed if that fails as well
+	failed := make(map[string]struct{})
+	var failures *videoSourceFailures
+
+	switch {
+	case err == nil || err == errNoContent:
+	case errors.As(err, &failures):
+		for _, sourceErr := range failures.failed {
+			failed[sourceErr.source] = struct{}{}
+		}
+	default:
+		for i := range channels {
+			failed[channels[i].ID] = struct{}{}
+		}
+	}
+
+	for i := range channels {
+		if _, ok := failed[channels[i].ID]; ok {
+			remaining = append(remaining, channels[i])
+		}
+	}
+
+	if len(failed) > 0 {
+		slog.Warn("Failed to fetch YouTube channels through the instance, falling back to their feeds", "backend", widget.YoutubeBackend, "channels", len(failed))
+	}
+
+	widget.recordSourceFailures(videoSourceYoutube, nil, len(channels)-len(failed))
+
+	return videos, remaining
+}
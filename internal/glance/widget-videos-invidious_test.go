@@ -0,0 +1,129 @@
+package glance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFetchYoutubeViaBackends(t *testing.T) {
+	invidiousVideo := `{"type":"video","title":"Invidious video","videoId":"inv1","author":"Channel","authorId":"UCXuqSBlHAE6Xw-yeJA0Tunw",
+		"videoThumbnails":[{"quality":"maxres","url":"/vi/inv1/maxres.jpg"},{"quality":"high","url":"/vi/inv1/hqdefault.jpg"}],
+		"published":1735830245,"lengthSeconds":65,"viewCount":1200,"liveNow":false}`
+
+	// Newer instances wrap the videos in an object while older ones respond with the list itself
+	for _, body := range []string{`{"videos":[` + invidiousVideo + `]}`, `[` + invidiousVideo + `]`} {
+		client := mapResponseDoer{
+			"https://invidious.example/api/v1/channels/UCXuqSBlHAE6Xw-yeJA0Tunw/videos": body,
+		}
+
+		videos, err := fetchYoutubeViaInvidious(context.Background(), []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}}, "https://invidious.example", "", "", youtubeThumbnailDefault, client, videoRetryOptions{}, 1)
+		if err != nil || len(videos) != 1 {
+			t.Fatalf("Expected a single video from Invidious, got %+v, %v", videos, err)
+		}
+
+		v := videos[0]
+		if v.Url != "https://www.youtube.com/watch?v=inv1" || v.ThumbnailUrl != "https://invidious.example/vi/inv1/hqdefault.jpg" ||
+			v.ChannelID != "UCXuqSBlHAE6Xw-yeJA0Tunw" || v.Duration != 65*time.Second || v.Views != 1200 || v.Source != videoSourceYoutube ||
+			!v.TimePosted.Equal(time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)) {
+			t.Errorf("Unexpected video from Invidious: %+v", v)
+		}
+	}
+
+	client := mapResponseDoer{
+		"https://piped.example/channel/UCXuqSBlHAE6Xw-yeJA0Tunw": `{"id":"UCXuqSBlHAE6Xw-yeJA0Tunw","name":"Channel","relatedStreams":[
+			{"url":"/watch?v=pip1","type":"stream","title":"Piped video","thumbnail":"https://proxy.example/vi/pip1.jpg","uploaderName":"Channel",
+				"uploaderUrl":"/channel/UCXuqSBlHAE6Xw-yeJA0Tunw","uploaded":1735830245000,"duration":300,"views":10,"isShort":false},
+			{"url":"/watch?v=pip2","type":"stream","title":"Piped short","uploaded":1735830245000,"duration":30,"isShort":true},
+			{"url":"/watch?v=pip3","type":"stream","title":"Piped live","uploaded":1735830245000,"duration":-1}]}`,
+	}
+
+	videos, err := fetchYoutubeViaPiped(context.Background(), []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw", Name: "Renamed"}}, "https://piped.example", "https://yt.example/{VIDEO-ID}", "", false, youtubeThumbnailDefault, client, videoRetryOptions{}, 1)
+	if err != nil || len(videos) != 2 {
+		t.Fatalf("Expected the short to be left out of the Piped videos, got %+v, %v", videos, err)
+	}
+
+	for _, v := range videos {
+		if v.Author != "Renamed" || v.Url != "https://yt.example/"+v.VideoID {
+			t.Errorf("Unexpected video from Piped: %+v", v)
+		}
+	}
+
+	if live := videos[slices.IndexFunc(videos, func(v video) bool { return v.VideoID == "pip3" })]; !live.IsLive || live.Duration != 0 {
+		t.Errorf("Expected the stream without a duration to be live, got %+v", live)
+	}
+
+	_, err = fetchYoutubeViaPiped(context.Background(), []videoSourceField{{ID: "UCmissing"}}, "https://piped.example", "", "", false, youtubeThumbnailDefault, client, videoRetryOptions{}, 1)
+	var failures *videoSourceFailures
+	if !errors.As(err, &failures) || len(failures.failed) != 1 {
+		t.Fatalf("Expected the missing channel to fail, got %v", err)
+	}
+
+	if reason, status := classifyVideoSourceError(failures.failed[0].err); reason != videoSourceFailureNotFound || status != http.StatusNotFound {
+		t.Errorf("Expected a channel missing from the instance to be reported as not found, got %v", failures.failed[0].err)
+	}
+}
+
+func TestVideosWidgetYoutubeBackendFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/channels/UCworking/videos":
+			w.Write([]byte(`{"videos":[{"title":"Instance video","videoId":"instance","author":"Working","authorId":"UCworking","published":1735830245}]}`))
+		case strings.HasPrefix(r.URL.Path, "/api/"):
+			http.Error(w, "unavailable", http.StatusBadGateway)
+		default:
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:yt="http://www.youtube.com/xml/schemas/2015">
+  <author><name>Broken</name><uri>https://www.youtube.com/channel/UCbroken</uri></author>
+  <entry>
+    <title>Feed video</title>
+    <yt:videoId>feed</yt:videoId>
+    <link href="https://www.youtube.com/watch?v=feed"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+		}
+	}))
+	defer server.Close()
+
+	for _, fallback := range []bool{false, true} {
+		widget := newTestVideosWidget(t, fmt.Sprintf(`
+youtube-backend: invidious
+youtube-backend-url: https://invidious.example/
+youtube-backend-fallback: %t
+max-retries: -1
+include-shorts: true
+channels: [UCworking, UCbroken]
+`, fallback))
+		widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+		widget.update(context.Background())
+
+		titles := make([]string, 0, len(widget.Videos))
+		for _, v := range widget.Videos {
+			titles = append(titles, v.Title)
+		}
+		slices.Sort(titles)
+
+		if fallback {
+			if !slices.Equal(titles, []string{"Feed video", "Instance video"}) || widget.failedSources != 0 {
+				t.Errorf("Expected the failed channel to be fetched from its feed, got %v with %d failed sources", titles, widget.failedSources)
+			}
+		} else if !slices.Equal(titles, []string{"Instance video"}) || widget.failedSources != 1 {
+			t.Errorf("Expected the failed channel to be left out without the fallback, got %v with %d failed sources", titles, widget.failedSources)
+		}
+	}
+
+	widget := &videosWidget{}
+	yaml.Unmarshal([]byte("youtube-backend: piped"), widget)
+	if err := widget.initialize(); err == nil {
+		t.Error("Expected an error when the instance URL is missing")
+	}
+}
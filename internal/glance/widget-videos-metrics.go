@@ -0,0 +1,138 @@
+package glance
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Statistics about the widget's fetches for monitoring how often sources fail and how
+// long they take. With metrics enabled they're kept per kind of source and served in
+// the Prometheus text format, while the widget itself stays the same either way.
+
+// The label of the community posts fetched alongside the YouTube videos
+const videosMetricsCommunitySource = "community"
+
+// Upper bounds of the fetch duration histogram, in seconds
+var videoFetchDurationBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// videosMetrics receives the statistics of every update, implementations have to be
+// safe to call from the fetchers of different kinds of sources at the same time
+type videosMetrics interface {
+	// observeFetch is called once all sources of a kind have been fetched
+	observeFetch(source string, duration time.Duration, videos int)
+	// observeSources is called with how many of the sources of a kind were fetched or failed
+	observeSources(source string, succeeded int, failed int)
+}
+
+type videoFetchHistogram struct {
+	// Cumulative like in the exposition format, each bucket also counts the ones below it
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// videoFetchMetrics keeps the statistics in memory for the widget's metrics endpoint
+type videoFetchMetrics struct {
+	mu        sync.Mutex
+	succeeded map[string]uint64
+	failed    map[string]uint64
+	videos    map[string]uint64
+	durations map[string]*videoFetchHistogram
+}
+
+func newVideoFetchMetrics() *videoFetchMetrics {
+	return &videoFetchMetrics{
+		succeeded: make(map[string]uint64),
+		failed:    make(map[string]uint64),
+		videos:    make(map[string]uint64),
+		durations: make(map[string]*videoFetchHistogram),
+	}
+}
+
+func (m *videoFetchMetrics) observeFetch(source string, duration time.Duration, videos int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	histogram := m.durations[source]
+	if histogram == nil {
+		histogram = &videoFetchHistogram{buckets: make([]uint64, len(videoFetchDurationBuckets))}
+		m.durations[source] = histogram
+	}
+
+	seconds := duration.Seconds()
+	for i, bound := range videoFetchDurationBuckets {
+		if seconds <= bound {
+			histogram.buckets[i]++
+		}
+	}
+
+	histogram.count++
+	histogram.sum += seconds
+	m.videos[source] += uint64(videos)
+}
+
+func (m *videoFetchMetrics) observeSources(source string, succeeded int, failed int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.succeeded[source] += uint64(succeeded)
+	m.failed[source] += uint64(failed)
+}
+
+// writeTo writes the metrics in the Prometheus text format, sorted by source so that
+// the output stays the same between scrapes
+func (m *videoFetchMetrics) writeTo(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP glance_videos_source_fetches_total Sources fetched by the videos widget, by whether they could be fetched.")
+	fmt.Fprintln(w, "# TYPE glance_videos_source_fetches_total counter")
+	for _, source := range slices.Sorted(maps.Keys(m.succeeded)) {
+		fmt.Fprintf(w, "glance_videos_source_fetches_total{source=%q,result=\"success\"} %d\n", source, m.succeeded[source])
+		fmt.Fprintf(w, "glance_videos_source_fetches_total{source=%q,result=\"failure\"} %d\n", source, m.failed[source])
+	}
+
+	fmt.Fprintln(w, "# HELP glance_videos_returned_total Videos returned by the sources before any filtering.")
+	fmt.Fprintln(w, "# TYPE glance_videos_returned_total counter")
+	for _, source := range slices.Sorted(maps.Keys(m.videos)) {
+		fmt.Fprintf(w, "glance_videos_returned_total{source=%q} %d\n", source, m.videos[source])
+	}
+
+	fmt.Fprintln(w, "# HELP glance_videos_fetch_duration_seconds How long fetching all sources of a kind took.")
+	fmt.Fprintln(w, "# TYPE glance_videos_fetch_duration_seconds histogram")
+	for _, source := range slices.Sorted(maps.Keys(m.durations)) {
+		histogram := m.durations[source]
+
+		for i, bound := range videoFetchDurationBuckets {
+			le := strconv.FormatFloat(bound, 'f', -1, 64)
+			fmt.Fprintf(w, "glance_videos_fetch_duration_seconds_bucket{source=%q,le=%q} %d\n", source, le, histogram.buckets[i])
+		}
+
+		fmt.Fprintf(w, "glance_videos_fetch_duration_seconds_bucket{source=%q,le=\"+Inf\"} %d\n", source, histogram.count)
+		fmt.Fprintf(w, "glance_videos_fetch_duration_seconds_sum{source=%q} %s\n", source, strconv.FormatFloat(histogram.sum, 'f', -1, 64))
+		fmt.Fprintf(w, "glance_videos_fetch_duration_seconds_count{source=%q} %d\n", source, histogram.count)
+	}
+}
+
+// handleMetricsRequest serves the metrics of the widget when they're enabled
+func (widget *videosWidget) handleMetricsRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	metrics, ok := widget.metrics.(*videoFetchMetrics)
+	if !ok {
+		http.Error(w, "metrics are not enabled for this widget", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	metrics.writeTo(w)
+}
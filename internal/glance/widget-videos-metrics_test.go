@@ -0,0 +1,68 @@
+package glance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVideosWidgetMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.RawQuery, "UCXuqSBlHAE6Xw-yeJA0Tunw") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	request := httptest.NewRequest("GET", "/api/widgets/1/metrics", nil)
+	request.SetPathValue("path", "metrics")
+
+	disabled := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	recorder := httptest.NewRecorder()
+	disabled.handleRequest(recorder, request)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404 without metrics enabled, got %d", recorder.Code)
+	}
+
+	widget := newTestVideosWidget(t, `
+metrics: true
+max-retries: -1
+include-shorts: true
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw, UCBR8-60-B28hp2BmDPdntcQ]
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+	widget.update(context.Background())
+	widget.update(context.Background())
+
+	recorder = httptest.NewRecorder()
+	widget.handleRequest(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+
+	body := recorder.Body.String()
+	for _, expected := range []string{
+		`glance_videos_source_fetches_total{source="youtube",result="success"} 2`,
+		`glance_videos_source_fetches_total{source="youtube",result="failure"} 2`,
+		`glance_videos_returned_total{source="youtube"} 2`,
+		`glance_videos_fetch_duration_seconds_bucket{source="youtube",le="+Inf"} 2`,
+		`glance_videos_fetch_duration_seconds_count{source="youtube"} 2`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected the metrics to contain %s, got:\n%s", expected, body)
+		}
+	}
+}
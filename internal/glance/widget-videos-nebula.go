@@ -0,0 +1,197 @@
+package glance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Nebula has no public feeds, so the latest episodes of creators are listed through
+// its content API, which requires the token of a subscribed account.
+
+const nebulaContentApiBaseUrl = "https://content.api.nebula.app"
+
+// Tokens expire after a while, at which point they have to be replaced in the config
+var errNebulaUnauthorized = errors.New("nebula token is invalid or has expired")
+
+type nebulaEpisodesResponseJson struct {
+	Results []struct {
+		ID           string `json:"id"`
+		Slug         string `json:"slug"`
+		Title        string `json:"title"`
+		ShareUrl     string `json:"share_url"`
+		PublishedAt  string `json:"published_at"`
+		Duration     int    `json:"duration"`
+		ChannelTitle string `json:"channel_title"`
+		ChannelSlug  string `json:"channel_slug"`
+		Images       struct {
+			Thumbnail struct {
+				Src string `json:"src"`
+			} `json:"thumbnail"`
+		} `json:"images"`
+	} `json:"results"`
+}
+
+type nebulaRequest struct {
+	request *http.Request
+	client  requestDoer
+}
+
+// nebulaChannelSlug returns the slug of a creator, such as "someone", from either the
+// slug itself or a link to the creator's page
+func nebulaChannelSlug(id string) string {
+	id = strings.TrimPrefix(id, "https://")
+	id = strings.TrimPrefix(id, "www.")
+	id = strings.TrimPrefix(id, "nebula.tv/")
+	id = strings.TrimPrefix(id, "nebula.app/")
+
+	return strings.ToLower(strings.Trim(id, "/"))
+}
+
+// nebulaEpisodesUrl returns the URL of the API endpoint listing the creator's latest episodes
+func nebulaEpisodesUrl(slug string, limit int) string {
+	query := url.Values{"ordering": {"-published_at"}}
+	if limit > 0 {
+		query.Set("page_size", fmt.Sprint(min(limit, 100)))
+	}
+
+	return nebulaContentApiBaseUrl + "/video_channels/" + url.PathEscape(slug) + "/video_episodes/?" + query.Encode()
+}
+
+func decodeNebulaTask(r nebulaRequest) (nebulaEpisodesResponseJson, error) {
+	var result nebulaEpisodesResponseJson
+
+	response, err := r.client.Do(r.request)
+	if err != nil {
+		return result, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden {
+		return result, errNebulaUnauthorized
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return result, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		truncatedBody, _ := limitStringLength(string(body), 256)
+		err := fmt.Errorf("unexpected status code %d from %s, response: %s", response.StatusCode, r.request.URL, truncatedBody)
+		return result, &videoFeedStatusError{status: response.StatusCode, err: err}
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// fetchNebulaUploads fetches the latest episodes of Nebula creators through the content
+// API. An expired or invalid token fails every creator with errNebulaUnauthorized.
+func fetchNebulaUploads(ctx context.Context, sources []videoSourceField, token string, limit int, client requestDoer, retry videoRetryOptions, workers int) (videoList, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: nebula-token is required for nebula-channels", errNoContent)
+	}
+
+	requests := make([]nebulaRequest, 0, len(sources))
+	for i := range sources {
+		request, _ := http.NewRequestWithContext(ctx, "GET", nebulaEpisodesUrl(nebulaChannelSlug(sources[i].ID), limit), nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		requests = append(requests, nebulaRequest{request: request, client: retry.wrap(sources[i].clientFor(client))})
+	}
+
+	job := newJob(decodeNebulaTask, requests).withWorkers(workers)
+	responses, errs, err := workerPoolDo(job)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNoContent, err)
+	}
+
+	videos := make(videoList, 0, len(sources)*15)
+	var failed []videoSourceError
+	var unauthorized bool
+
+	for i := range responses {
+		source := &sources[i]
+
+		if errs[i] != nil {
+			failed = append(failed, videoSourceError{source: source.ID, err: errs[i]})
+			unauthorized = unauthorized || errors.Is(errs[i], errNebulaUnauthorized)
+			slog.Error("Failed to fetch nebula videos", "channel", source.ID, "error", errs[i])
+			continue
+		}
+
+		for j := range responses[i].Results {
+			v := &responses[i].Results[j]
+
+			if v.Title == "" || v.Slug == "" || source.excludesTitle(v.Title) {
+				continue
+			}
+
+			timePosted, err := time.Parse(time.RFC3339, v.PublishedAt)
+			if err != nil {
+				slog.Warn("Skipping Nebula video with invalid publish time", "channel", source.ID, "title", v.Title, "published", v.PublishedAt)
+				continue
+			}
+
+			videoUrl := v.ShareUrl
+			if videoUrl == "" {
+				videoUrl = "https://nebula.tv/videos/" + v.Slug
+			}
+
+			thumbnailUrl := v.Images.Thumbnail.Src
+			if thumbnailUrl == "" {
+				thumbnailUrl = videoThumbnailPlaceholder
+			}
+
+			channelSlug := v.ChannelSlug
+			if channelSlug == "" {
+				channelSlug = nebulaChannelSlug(source.ID)
+			}
+
+			videos = append(videos, video{
+				ThumbnailUrl: thumbnailUrl,
+				Title:        v.Title,
+				Url:          videoUrl,
+				Author:       v.ChannelTitle,
+				AuthorUrl:    "https://nebula.tv/" + channelSlug,
+				VideoID:      v.ID,
+				Source:       videoSourceNebula,
+				sourceID:     source.ID,
+				TimePosted:   timePosted,
+				Duration:     time.Duration(v.Duration) * time.Second,
+			})
+		}
+	}
+
+	noContent, partialContent := errNoContent, errPartialContent
+	if unauthorized {
+		noContent = fmt.Errorf("%w: %w", errNoContent, errNebulaUnauthorized)
+		partialContent = fmt.Errorf("%w: %w", errPartialContent, errNebulaUnauthorized)
+	}
+
+	if len(videos) == 0 {
+		if len(failed) > 0 {
+			return nil, &videoSourceFailures{err: noContent, failed: failed, total: len(sources)}
+		}
+
+		return nil, errNoContent
+	}
+
+	videos.sortByNewest()
+
+	if len(failed) > 0 {
+		return videos, &videoSourceFailures{err: partialContent, failed: failed, total: len(sources)}
+	}
+
+	return videos, nil
+}
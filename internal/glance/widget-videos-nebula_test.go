@@ -0,0 +1,79 @@
+package glance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFetchNebulaUploads(t *testing.T) {
+	var expired atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expired.Load() || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/video_channels/someone/video_episodes/":
+			fmt.Fprint(w, `{"results": [{
+				"id": "video_episode:1", "slug": "someone-first", "title": "First episode",
+				"share_url": "https://nebula.tv/videos/someone-first", "published_at": "2025-01-14T14:00:00Z",
+				"duration": 245, "channel_title": "Someone", "channel_slug": "someone",
+				"images": {"thumbnail": {"src": "https://images.nebula.tv/first.jpg"}}
+			}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: redirectTransport{server: server}}
+	sources := []videoSourceField{{ID: "https://nebula.tv/someone"}, {ID: "missing"}}
+
+	videos, err := fetchNebulaUploads(context.Background(), sources, "token", 0, client, videoRetryOptions{}, videosDefaultConcurrency)
+	if !errors.Is(err, errPartialContent) || len(videos) != 1 {
+		t.Fatalf("Expected a single video and a partial content error, got %+v, %v", videos, err)
+	}
+
+	v := videos[0]
+	if v.Author != "Someone" || v.AuthorUrl != "https://nebula.tv/someone" || v.Source != videoSourceNebula || v.Url != "https://nebula.tv/videos/someone-first" {
+		t.Errorf("Unexpected video details: %+v", v)
+	}
+
+	if v.ThumbnailUrl != "https://images.nebula.tv/first.jpg" || v.Duration != 245*time.Second || !v.TimePosted.Equal(time.Date(2025, 1, 14, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected video details: %+v", v)
+	}
+
+	expired.Store(true)
+
+	widget := newTestVideosWidget(t, `
+nebula-channels: [someone]
+nebula-token: token
+max-retries: -1
+`)
+	widget.Proxy.client = client
+	widget.update(context.Background())
+
+	if widget.Error == nil || !strings.Contains(widget.Error.Error(), errNebulaUnauthorized.Error()) {
+		t.Errorf("Expected the expired token to be shown as the widget's error, got %v", widget.Error)
+	}
+
+	invalid := &videosWidget{}
+	if err := yaml.Unmarshal([]byte("nebula-channels: [someone]\n"), invalid); err != nil {
+		t.Fatalf("Failed to decode widget config: %v", err)
+	}
+
+	if err := invalid.initialize(); err == nil {
+		t.Error("Expected an error for nebula-channels without a token")
+	}
+}
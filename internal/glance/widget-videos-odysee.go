@@ -0,0 +1,144 @@
+package glance
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Odysee provides an RSS feed of the uploads of every channel, with the thumbnail
+// of each video either as an iTunes image or within its description.
+
+type odyseeFeedResponseXml struct {
+	Channel     string `xml:"channel>title"`
+	ChannelLink string `xml:"channel>link"`
+	Videos      []struct {
+		Title       string `xml:"title"`
+		Published   string `xml:"pubDate"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		Image       struct {
+			Href string `xml:"href,attr"`
+		} `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		Duration   string   `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+		Categories []string `xml:"category"`
+		Thumbnail  struct {
+			Url string `xml:"url,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	} `xml:"channel>item"`
+}
+
+var odyseeDescriptionImagePattern = regexp.MustCompile(`<img[^>]+src="([^"]+)"`)
+
+// odyseeChannelName returns the name of a channel in the form of "@channel" or
+// "@channel:claimid" from either of those or a link to the channel. LBRY URLs
+// separate the claim ID with a "#" rather than a ":".
+func odyseeChannelName(channel string) string {
+	channel = strings.TrimPrefix(channel, "https://")
+	channel = strings.TrimPrefix(channel, "www.")
+	channel = strings.TrimPrefix(channel, "odysee.com/")
+	channel = strings.TrimPrefix(channel, "lbry://")
+	channel = strings.ReplaceAll(strings.Trim(channel, "/"), "#", ":")
+
+	if !strings.HasPrefix(channel, "@") {
+		channel = "@" + channel
+	}
+
+	return channel
+}
+
+func odyseeFeedUrl(channel string) string {
+	return "https://odysee.com/$/rss/" + odyseeChannelName(channel)
+}
+
+// fetchOdyseeChannelUploads fetches videos from Odysee channels
+func fetchOdyseeChannelUploads(ctx context.Context, sources []videoSourceField, client requestDoer, retry videoRetryOptions, workers int) (videoList, error) {
+	channels := videoSourceIDs(sources)
+	requests := make([]videoFeedRequest, 0, len(channels))
+
+	for i := range channels {
+		request, _ := http.NewRequestWithContext(ctx, "GET", odyseeFeedUrl(channels[i]), nil)
+		requests = append(requests, videoFeedRequest{request: request, client: retry.wrap(sources[i].clientFor(client))})
+	}
+
+	job := newJob(decodeVideoFeedTask[odyseeFeedResponseXml], requests).withWorkers(workers)
+	responses, errs, err := workerPoolDo(job)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNoContent, err)
+	}
+
+	videos := make(videoList, 0, len(channels)*15)
+	var failed []videoSourceError
+
+	for i := range responses {
+		if errs[i] != nil {
+			failed = append(failed, videoSourceError{source: channels[i], err: errs[i]})
+			slog.Error("Failed to fetch odysee feed", "channel", channels[i], "error", errs[i])
+			continue
+		}
+
+		response := responses[i]
+
+		for j := range response.Videos {
+			v := &response.Videos[j]
+
+			if v.Title == "" || v.Link == "" {
+				continue
+			}
+
+			if sources[i].excludesTitle(v.Title) {
+				continue
+			}
+
+			timePosted, err := parseRumbleFeedTime(v.Published)
+			if err != nil {
+				slog.Warn("Skipping Odysee video with invalid publish time", "channel", channels[i], "title", v.Title, "published", v.Published)
+				continue
+			}
+
+			thumbnailUrl := v.Image.Href
+			if thumbnailUrl == "" {
+				thumbnailUrl = v.Thumbnail.Url
+			}
+			if match := odyseeDescriptionImagePattern.FindStringSubmatch(v.Description); thumbnailUrl == "" && match != nil {
+				thumbnailUrl = match[1]
+			}
+			if thumbnailUrl == "" {
+				thumbnailUrl = videoThumbnailPlaceholder
+			}
+
+			videos = append(videos, video{
+				ThumbnailUrl: thumbnailUrl,
+				Title:        v.Title,
+				Url:          v.Link,
+				Author:       response.Channel,
+				AuthorUrl:    cmp.Or(response.ChannelLink, "https://odysee.com/"+odyseeChannelName(channels[i])),
+				Source:       videoSourceOdysee,
+				sourceID:     sources[i].ID,
+				Duration:     parseFeedDuration(v.Duration),
+				TimePosted:   timePosted,
+				Categories:   videoCategories(v.Categories...),
+			})
+		}
+	}
+
+	if len(videos) == 0 {
+		if len(failed) > 0 {
+			return nil, &videoSourceFailures{err: errNoContent, failed: failed, total: len(sources)}
+		}
+
+		return nil, errNoContent
+	}
+
+	videos.sortByNewest()
+
+	if len(failed) > 0 {
+		return videos, &videoSourceFailures{err: errPartialContent, failed: failed, total: len(sources)}
+	}
+
+	return videos, nil
+}
@@ -0,0 +1,53 @@
+package glance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFetchOdyseeChannelUploads(t *testing.T) {
+	client := mapResponseDoer{
+		"https://odysee.com/$/rss/@someone:a": `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Someone</title>
+    <link>https://odysee.com/@someone:a</link>
+    <item>
+      <title>With image</title>
+      <link>https://odysee.com/@someone:a/first:1</link>
+      <pubDate>Tue, 14 Jan 2025 14:00:00 GMT</pubDate>
+      <itunes:image href="https://thumbnails.lbry.com/first.jpg"/>
+      <itunes:duration>04:05</itunes:duration>
+    </item>
+  </channel>
+</rss>`,
+		"https://odysee.com/$/rss/@other:b": `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Other</title>
+    <item>
+      <title>Image in description</title>
+      <link>https://odysee.com/@other:b/second:2</link>
+      <pubDate>Mon, 13 Jan 2025 14:00:00 GMT</pubDate>
+      <description><![CDATA[<p><img src="https://thumbnails.lbry.com/second.jpg" /></p>]]></description>
+    </item>
+  </channel>
+</rss>`,
+	}
+
+	sources := []videoSourceField{{ID: "@someone:a"}, {ID: "https://odysee.com/@other#b"}, {ID: "missing"}}
+	videos, err := fetchOdyseeChannelUploads(context.Background(), sources, client, videoRetryOptions{}, videosDefaultConcurrency)
+	if !errors.Is(err, errPartialContent) || len(videos) != 2 {
+		t.Fatalf("Expected two videos and a partial content error, got %+v, %v", videos, err)
+	}
+
+	if v := videos[0]; v.ThumbnailUrl != "https://thumbnails.lbry.com/first.jpg" || v.Duration != 245*time.Second || v.Source != videoSourceOdysee || v.Author != "Someone" {
+		t.Errorf("Unexpected video details: %+v", v)
+	}
+
+	if v := videos[1]; v.ThumbnailUrl != "https://thumbnails.lbry.com/second.jpg" || v.AuthorUrl != "https://odysee.com/@other:b" {
+		t.Errorf("Unexpected video details: %+v", v)
+	}
+}
@@ -0,0 +1,123 @@
+package glance
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// RSS readers can export their subscriptions as an OPML file, so the YouTube channels
+// and playlists in one can be added to the widget without listing each of them.
+
+const channelsOPMLMaxSize = 5 * 1024 * 1024
+
+const channelsOPMLTimeout = 10 * time.Second
+
+type opmlOutline struct {
+	Title    string        `xml:"title,attr"`
+	Text     string        `xml:"text,attr"`
+	XMLUrl   string        `xml:"xmlUrl,attr"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlDocument struct {
+	Body struct {
+		Outlines []opmlOutline `xml:"outline"`
+	} `xml:"body"`
+}
+
+// loadChannelsOPML reads the OPML file from the given path or URL
+func loadChannelsOPML(location string, client requestDoer) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return os.ReadFile(location)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), channelsOPMLTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, "GET", location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", glanceUserAgentString)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", response.StatusCode, location)
+	}
+
+	return io.ReadAll(io.LimitReader(response.Body, channelsOPMLMaxSize))
+}
+
+// parseChannelsOPML returns the YouTube channels and playlists of the feeds in the
+// OPML file. Feeds that aren't from YouTube are skipped with a warning.
+func parseChannelsOPML(contents []byte) ([]videoSourceField, error) {
+	var document opmlDocument
+	if err := xml.Unmarshal(contents, &document); err != nil {
+		return nil, err
+	}
+
+	sources := make([]videoSourceField, 0)
+
+	var walk func(outlines []opmlOutline)
+	walk = func(outlines []opmlOutline) {
+		for i := range outlines {
+			outline := &outlines[i]
+
+			// Outlines without a feed are categories of other outlines
+			if outline.XMLUrl != "" {
+				if id, ok := youtubeSourceFromFeedUrl(outline.XMLUrl); ok {
+					sources = append(sources, videoSourceField{ID: id})
+				} else {
+					slog.Warn("Skipping OPML entry that isn't a YouTube feed", "title", cmp.Or(outline.Title, outline.Text), "url", outline.XMLUrl)
+				}
+			}
+
+			walk(outline.Outlines)
+		}
+	}
+
+	walk(document.Body.Outlines)
+
+	return sources, nil
+}
+
+// youtubeSourceFromFeedUrl returns the ID of the channel or playlist of a YouTube feed
+// URL such as https://www.youtube.com/feeds/videos.xml?channel_id=..., with playlists
+// having the playlist prefix
+func youtubeSourceFromFeedUrl(feedUrl string) (string, bool) {
+	parsed, err := url.Parse(strings.TrimSpace(feedUrl))
+	if err != nil {
+		return "", false
+	}
+
+	host := strings.TrimPrefix(strings.TrimPrefix(parsed.Hostname(), "www."), "m.")
+	if host != "youtube.com" || parsed.Path != "/feeds/videos.xml" {
+		return "", false
+	}
+
+	query := parsed.Query()
+	if id := query.Get("channel_id"); id != "" {
+		return id, true
+	}
+
+	if id := query.Get("playlist_id"); id != "" {
+		return videosWidgetPlaylistPrefix + id, true
+	}
+
+	return "", false
+}
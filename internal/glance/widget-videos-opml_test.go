@@ -0,0 +1,44 @@
+package glance
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestVideosWidgetChannelsOPML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "subscriptions.opml")
+	contents := `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+  <head><title>Subscriptions</title></head>
+  <body>
+    <outline text="YouTube" title="YouTube">
+      <outline text="Channel" type="rss" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=UCsBjURrPoezykLs9EqgamOA"/>
+      <outline text="Playlist" type="rss" xmlUrl="https://www.youtube.com/feeds/videos.xml?playlist_id=PLFgquLnL59alCl_2TQvOiD5Vgm1hCaGSI"/>
+      <outline text="Duplicate" type="rss" xmlUrl="https://youtube.com/feeds/videos.xml?channel_id=UCXuqSBlHAE6Xw-yeJA0Tunw"/>
+    </outline>
+    <outline text="Blog" type="rss" xmlUrl="https://example.com/feed.xml"/>
+  </body>
+</opml>`
+
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	widget := newTestVideosWidget(t, fmt.Sprintf(`
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+channels-opml: %s
+`, path))
+
+	expected := []string{
+		"UCXuqSBlHAE6Xw-yeJA0Tunw",
+		"UCsBjURrPoezykLs9EqgamOA",
+		videosWidgetPlaylistPrefix + "PLFgquLnL59alCl_2TQvOiD5Vgm1hCaGSI",
+	}
+
+	if ids := videoSourceIDs(widget.Channels); !slices.Equal(ids, expected) {
+		t.Errorf("Expected the YouTube feeds of the OPML file to be added once, got %v", ids)
+	}
+}
@@ -0,0 +1,107 @@
+package glance
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// With page-size set, only the first page of each section is rendered along with a
+// button which requests the next one, whose videos get added to the section. The widget
+// keeps all of its videos, so every page is sliced from the same list as the first one.
+
+// videosPage is a page of a single section, starting at the offset into its videos
+type videosPage struct {
+	section int
+	offset  int
+}
+
+// paginate returns the page of videos starting at the offset along with the query of
+// the request for the page after it, which is empty when it's the last page. The query
+// carries the style and hidden channels so that the next page is rendered the same way.
+func (view *videosWidgetView) paginate(section int, videos videoList, offset int) (videoList, string) {
+	if offset >= len(videos) {
+		return nil, ""
+	}
+
+	end := min(offset+view.PageSize, len(videos))
+	if end == len(videos) {
+		return videos[offset:end], ""
+	}
+
+	query := url.Values{
+		"section": {strconv.Itoa(section)},
+		"offset":  {strconv.Itoa(end)},
+	}
+	if view.style != "" {
+		query.Set("style", view.style)
+	}
+	for _, channel := range view.hiddenChannels {
+		query.Add("hide", channel)
+	}
+
+	return videos[offset:end], query.Encode()
+}
+
+// handlePageRequest renders the widget with only the requested page of one of its
+// sections, which the page takes the videos and the button for the next page from
+func (widget *videosWidget) handlePageRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if widget.PageSize <= 0 {
+		http.Error(w, "pagination is not enabled for this widget", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+
+	section, err := strconv.Atoi(query.Get("section"))
+	if err != nil || section < 0 {
+		http.Error(w, "invalid section", http.StatusBadRequest)
+		return
+	}
+
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		http.Error(w, "invalid offset", http.StatusBadRequest)
+		return
+	}
+
+	prefs := widgetPreferences{Style: query.Get("style"), HiddenChannels: query["hide"]}
+	if err := widget.validatePreferences(&prefs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	widget.mu.RLock()
+	defer widget.mu.RUnlock()
+
+	if !widget.ContentAvailable {
+		http.Error(w, "videos are not available yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	if section >= len(widget.Sections()) {
+		http.Error(w, "section not found", http.StatusNotFound)
+		return
+	}
+
+	// Rendered into a buffer of its own rather than through renderTemplate, since this
+	// only holds the read lock and renderTemplate changes the widget
+	view, tmpl := widget.viewFor(prefs, &videosPage{section: section, offset: offset})
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, view); err != nil {
+		slog.Error("Failed to render page of videos", "error", err)
+		http.Error(w, "could not render videos", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buffer.Bytes())
+}
@@ -0,0 +1,70 @@
+package glance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVideosWidgetPagination(t *testing.T) {
+	widget := newTestVideosWidget(t, "page-size: 2\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	now := time.Now()
+	for i := range 5 {
+		widget.Videos = append(widget.Videos, video{Title: "video-" + strconv.Itoa(i), Url: "https://example.com/" + strconv.Itoa(i), Author: "Channel", TimePosted: now})
+	}
+	widget.ContentAvailable = true
+
+	html := string(widget.renderWithPreferences(widgetPreferences{Style: "vertical-list"}))
+	if !strings.Contains(html, "video-1") || strings.Contains(html, "video-2") {
+		t.Fatal("Expected only the first page to be rendered")
+	}
+
+	if !strings.Contains(html, `data-videos-next-page="offset=2&amp;section=0&amp;style=vertical-list"`) {
+		t.Fatalf("Expected a button for the next page, got %s", html)
+	}
+
+	requestPage := func(query string) (int, string) {
+		request := httptest.NewRequest("GET", "/api/widgets/1/page?"+query, nil)
+		request.SetPathValue("path", "page")
+		recorder := httptest.NewRecorder()
+		widget.handleRequest(recorder, request)
+
+		return recorder.Code, recorder.Body.String()
+	}
+
+	code, body := requestPage("section=0&offset=2&style=vertical-list")
+	if code != http.StatusOK || !strings.Contains(body, "video-2") || !strings.Contains(body, "video-3") || strings.Contains(body, "video-1") || !strings.Contains(body, "offset=4") {
+		t.Fatalf("Expected the second page with a button for the third, got %d: %s", code, body)
+	}
+
+	code, body = requestPage("section=0&offset=4")
+	if code != http.StatusOK || !strings.Contains(body, "video-4") || strings.Contains(body, "data-videos-next-page") {
+		t.Fatalf("Expected the last page without a button for the next one, got %d: %s", code, body)
+	}
+
+	for _, query := range []string{"section=1&offset=0", "section=0&offset=-1", "section=0&offset=2&style=cards"} {
+		if code, _ := requestPage(query); code == http.StatusOK {
+			t.Errorf("Expected %q to fail", query)
+		}
+	}
+
+	// Pages only hold the read lock, so they have to be rendered without changing the
+	// widget while it's being rendered by other requests
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if code, body := requestPage("section=0&offset=2"); code != http.StatusOK || !strings.Contains(body, "video-2") {
+				t.Errorf("Expected concurrent requests to get the second page, got %d: %s", code, body)
+			}
+		}()
+	}
+	widget.Render()
+	wg.Wait()
+}
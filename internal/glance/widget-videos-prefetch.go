@@ -0,0 +1,55 @@
+package glance
+
+import (
+	"context"
+	"time"
+)
+
+// Without a prefetch the videos are only fetched once their page is first requested,
+// which then waits for all of the feeds. With it, the widget starts fetching them as
+// soon as the server starts, so they're usually ready by the time the page is opened.
+
+// prefetch starts the widget's first update in the background, unless it's disabled or
+// the videos from the cache-file are still fresh
+func (widget *videosWidget) prefetch() {
+	if !widget.Prefetch {
+		return
+	}
+
+	// Videos loaded from the cache-file that haven't expired yet are shown until then
+	if widget.nextUpdate.After(time.Now()) {
+		return
+	}
+
+	done := make(chan struct{})
+	widget.prefetchDone = done
+
+	go func() {
+		defer close(done)
+		widget.updateVideos(context.Background())
+	}()
+}
+
+// requiresUpdate waits for the prefetch to finish before checking whether the widget is
+// due for an update, since the prefetch is what sets when its next update is
+func (widget *videosWidget) requiresUpdate(now *time.Time) bool {
+	widget.waitForPrefetch()
+	return widget.widgetBase.requiresUpdate(now)
+}
+
+// waitForPrefetch waits for the prefetch to finish when it's still running, reporting
+// whether it did so that the caller can use its videos rather than fetching them again
+func (widget *videosWidget) waitForPrefetch() bool {
+	done := widget.prefetchDone
+	if done == nil {
+		return false
+	}
+
+	select {
+	case <-done:
+		return false
+	default:
+		<-done
+		return true
+	}
+}
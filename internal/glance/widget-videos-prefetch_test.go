@@ -0,0 +1,121 @@
+package glance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVideosWidgetPrefetch(t *testing.T) {
+	var requests atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	disabled := newTestVideosWidget(t, "prefetch: false\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	disabled.prefetch()
+	if disabled.prefetchDone != nil || disabled.waitForPrefetch() {
+		t.Fatal("Expected nothing to be prefetched when disabled")
+	}
+
+	widget := newTestVideosWidget(t, "include-shorts: true\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+	widget.prefetch()
+	<-started
+
+	updated := make(chan struct{})
+	go func() {
+		widget.update(context.Background())
+		close(updated)
+	}()
+
+	// Gives the update the time to start waiting for the prefetch
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	<-updated
+
+	if !widget.ContentAvailable || len(widget.Videos) != 1 {
+		t.Fatalf("Expected the prefetched videos to be available, got %d", len(widget.Videos))
+	}
+
+	if count := requests.Load(); count != 1 {
+		t.Errorf("Expected the update to use the prefetched videos, got %d requests", count)
+	}
+
+	widget.update(context.Background())
+	if count := requests.Load(); count != 2 {
+		t.Errorf("Expected updates after the prefetch to fetch the videos, got %d requests", count)
+	}
+}
+
+func TestVideosWidgetPageRequestDuringPrefetch(t *testing.T) {
+	var requests atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, "include-shorts: true\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+	widget.prefetch()
+	<-started
+
+	// Same as what a request for the widget's page does while the prefetch is running
+	p := &page{HeadWidgets: widgets{widget}}
+	requested := make(chan struct{})
+	go func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.updateOutdatedWidgets()
+		close(requested)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	<-requested
+
+	if !widget.IsContentAvailable() || len(widget.Videos) != 1 {
+		t.Fatalf("Expected the page to get the prefetched videos, got %d", len(widget.Videos))
+	}
+
+	if count := requests.Load(); count != 1 {
+		t.Errorf("Expected the page to not fetch the videos again, got %d requests", count)
+	}
+}
@@ -0,0 +1,116 @@
+package glance
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// With many sources the feeds get requested in a quick burst, which sites such as
+// YouTube sometimes answer with 429. rate-limit spreads the requests of a widget out
+// through a token bucket, and once a site asks for a pause through Retry-After, the
+// widget's other requests wait for it as well.
+
+// Retry-After values longer than this aren't waited for, the request fails instead
+const videosMaxRetryAfter = time.Minute
+
+// videoRateLimiter is a token bucket shared by all requests of a widget, it allows
+// bursts of up to a second's worth of requests
+type videoRateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	// Set from the Retry-After of a 429 response, no requests are made before it
+	pausedUntil time.Time
+}
+
+func newVideoRateLimiter(requestsPerSecond float64) *videoRateLimiter {
+	burst := max(1, math.Floor(requestsPerSecond))
+
+	return &videoRateLimiter{rate: requestsPerSecond, burst: burst, tokens: burst}
+}
+
+// reserve takes a token and returns how long to wait before making the request
+func (l *videoRateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+
+	l.last = now
+	l.tokens--
+
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+
+	return max(wait, l.pausedUntil.Sub(now))
+}
+
+// wait blocks until a request can be made or the context is done
+func (l *videoRateLimiter) wait(ctx context.Context) error {
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pauseUntil holds off all further requests until the given time
+func (l *videoRateLimiter) pauseUntil(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+type rateLimitedRequestDoer struct {
+	client  requestDoer
+	limiter *videoRateLimiter
+}
+
+func (d *rateLimitedRequestDoer) Do(request *http.Request) (*http.Response, error) {
+	if err := d.limiter.wait(request.Context()); err != nil {
+		return nil, err
+	}
+
+	return d.client.Do(request)
+}
+
+// parseRetryAfter returns how long the Retry-After header of the response asks to
+// wait, which is either a number of seconds or a date
+func parseRetryAfter(response *http.Response, now time.Time) (time.Duration, bool) {
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,41 @@
+package glance
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestVideoRateLimiter(t *testing.T) {
+	limiter := newVideoRateLimiter(2)
+	now := time.Now()
+
+	waits := make([]time.Duration, 0, 3)
+	for range 3 {
+		waits = append(waits, limiter.reserve(now))
+	}
+
+	if expected := []time.Duration{0, 0, 500 * time.Millisecond}; !slices.Equal(waits, expected) {
+		t.Errorf("Expected a burst of 2 followed by a wait, got %v", waits)
+	}
+
+	if wait := limiter.reserve(now.Add(2 * time.Second)); wait != 0 {
+		t.Errorf("Expected the bucket to refill, got a wait of %v", wait)
+	}
+
+	limiter.pauseUntil(now.Add(10 * time.Second))
+	if wait := limiter.reserve(now.Add(3 * time.Second)); wait != 7*time.Second {
+		t.Errorf("Expected requests to wait for the pause, got %v", wait)
+	}
+
+	widget := &videosWidget{}
+	if err := yaml.Unmarshal([]byte("rate-limit: -1\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]"), widget); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := widget.initialize(); err == nil {
+		t.Error("Expected a negative rate-limit to fail")
+	}
+}
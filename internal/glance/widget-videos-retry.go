@@ -0,0 +1,163 @@
+package glance
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Retrying of the requests made for the feeds of the videos widget, so that a single
+// slow or briefly unavailable source doesn't go missing until the next update.
+
+// Same as the default client but without its overall timeout, since the requests of
+// the videos widget are limited through request-timeout instead
+var videosHTTPClient = &http.Client{
+	Transport: defaultHTTPClient.Transport,
+}
+
+const (
+	videosDefaultMaxRetries     = 2
+	videosDefaultRequestTimeout = 10 * time.Second
+	videosRetryBaseDelay        = time.Second
+	videosDefaultFetchDeadline  = 30 * time.Second
+	// How long to wait after the first update in a row that failed to fetch any videos,
+	// doubled after every further one up to videosMaxFailureBackoff
+	videosFailureBackoffBase = time.Minute
+	videosMaxFailureBackoff  = 15 * time.Minute
+)
+
+type videoRetryOptions struct {
+	retries int
+	// Applies to each attempt rather than to all of them together
+	timeout time.Duration
+	// Doubled after every failed attempt, defaults to videosRetryBaseDelay
+	baseDelay time.Duration
+	// Makes conditional requests when set, see widget-videos-conditional.go
+	conditional *videoConditionalCache
+	// Reuses the YouTube feeds fetched by other widgets when set, see widget-videos-shared-cache.go
+	shared *youtubeSharedFeedCache
+	// Sent with requests that don't set their own, a browser's when empty
+	userAgent string
+	// Spreads out the requests when set, see widget-videos-rate-limit.go
+	limiter *videoRateLimiter
+}
+
+// videosFailureBackoff returns how long to wait before the next update after the given
+// number of consecutive updates that failed to fetch any videos
+func videosFailureBackoff(failures int) time.Duration {
+	backoff := videosFailureBackoffBase
+	for i := 1; i < failures && backoff < videosMaxFailureBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, videosMaxFailureBackoff)
+}
+
+// wrap returns a client which makes requests through the given one according to the options
+func (o videoRetryOptions) wrap(client requestDoer) requestDoer {
+	client = o.conditional.wrap(client)
+	if o.limiter != nil {
+		client = &rateLimitedRequestDoer{client: client, limiter: o.limiter}
+	}
+
+	client = &videoUserAgentDoer{client: client, userAgent: o.userAgent}
+
+	if o.retries <= 0 && o.timeout <= 0 {
+		return client
+	}
+
+	if o.baseDelay <= 0 {
+		o.baseDelay = videosRetryBaseDelay
+	}
+
+	return &retryingRequestDoer{client: client, options: o}
+}
+
+type retryingRequestDoer struct {
+	client  requestDoer
+	options videoRetryOptions
+}
+
+func (d *retryingRequestDoer) Do(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+
+	for attempt := 0; ; attempt++ {
+		response, err := d.attempt(request)
+		retryAfter := d.retryAfter(response)
+		if attempt >= d.options.retries || ctx.Err() != nil || !shouldRetryVideoRequest(response, err) || retryAfter > videosMaxRetryAfter {
+			return response, err
+		}
+
+		if response != nil {
+			response.Body.Close()
+		}
+
+		select {
+		case <-time.After(max(d.options.baseDelay<<attempt, retryAfter)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (d *retryingRequestDoer) attempt(request *http.Request) (*http.Response, error) {
+	if d.options.timeout <= 0 {
+		return d.client.Do(request)
+	}
+
+	ctx, cancel := context.WithTimeout(request.Context(), d.options.timeout)
+
+	response, err := d.client.Do(request.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The timeout has to keep applying while the body is being read
+	response.Body = &cancelOnCloseReader{ReadCloser: response.Body, cancel: cancel}
+
+	return response, nil
+}
+
+// retryAfter returns how long a 429 response asks to wait before the next request,
+// pausing the widget's other requests for as long when they're rate limited
+func (d *retryingRequestDoer) retryAfter(response *http.Response) time.Duration {
+	if response == nil || response.StatusCode != http.StatusTooManyRequests {
+		return 0
+	}
+
+	now := time.Now()
+
+	retryAfter, ok := parseRetryAfter(response, now)
+	if !ok {
+		return 0
+	}
+
+	if d.options.limiter != nil && retryAfter <= videosMaxRetryAfter {
+		d.options.limiter.pauseUntil(now.Add(retryAfter))
+	}
+
+	return retryAfter
+}
+
+// shouldRetryVideoRequest reports whether the failure is likely to be temporary
+func shouldRetryVideoRequest(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+}
+
+type cancelOnCloseReader struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelOnCloseReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.cancel()
+
+	return err
+}
@@ -0,0 +1,106 @@
+package glance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVideoRetryOptionsRetriesTemporaryFailures(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Query().Get("channel_id") == "UCmissing":
+			w.WriteHeader(http.StatusNotFound)
+		case requests.Add(1) == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>Video</title><published>2025-01-02T15:04:05+00:00</published><link href="https://www.youtube.com/watch?v=abc"/></entry></feed>`))
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: redirectTransport{server: server}}
+	retry := videoRetryOptions{retries: 2, timeout: time.Second, baseDelay: time.Millisecond}
+
+	videos, err := fetchYoutubeChannelUploads(context.Background(), []videoSourceField{{ID: "UCtransient"}}, "", "", true, youtubeThumbnailDefault, nil, client, retry, videosDefaultConcurrency)
+	if err != nil || len(videos) != 1 {
+		t.Fatalf("Expected the video after a retry, got %v, %v", videos, err)
+	}
+
+	if _, err := fetchYoutubeChannelUploads(context.Background(), []videoSourceField{{ID: "UCmissing"}}, "", "", true, youtubeThumbnailDefault, nil, client, retry, videosDefaultConcurrency); err == nil {
+		t.Fatal("Expected an error for a missing channel")
+	}
+
+	if count := requests.Load(); count != 2 {
+		t.Fatalf("Expected 2 requests for the transient failure and none retried for the missing channel, got %d", count)
+	}
+}
+
+func TestVideoRetryOptionsStopsWhenCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := videoRetryOptions{retries: 5, baseDelay: time.Hour}.wrap(&http.Client{Transport: redirectTransport{server: server}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	request, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com", nil)
+
+	start := time.Now()
+	if _, err := client.Do(request); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the context's error, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Expected pending retries to be aborted, took %v", elapsed)
+	}
+}
+
+func TestVideoRetryOptionsHonorsRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", r.URL.Query().Get("retry-after"))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	limiter := newVideoRateLimiter(100)
+	client := videoRetryOptions{retries: 1, baseDelay: time.Millisecond, limiter: limiter}.wrap(&http.Client{Transport: redirectTransport{server: server}})
+
+	request, _ := http.NewRequest("GET", "https://www.youtube.com/feeds?retry-after=3600", nil)
+	response, err := client.Do(request)
+	if err != nil || response.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected the 429 response, got %v, %v", response, err)
+	}
+
+	if count := requests.Load(); count != 1 {
+		t.Errorf("Expected a Retry-After beyond the limit not to be waited for, got %d requests", count)
+	}
+
+	request, _ = http.NewRequest("GET", "https://www.youtube.com/feeds?retry-after=1", nil)
+	start := time.Now()
+	client.Do(request)
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected the retry to wait for Retry-After, took %v", elapsed)
+	}
+
+	if wait := limiter.reserve(time.Now()); wait <= 0 {
+		t.Error("Expected the limiter to be paused after the last 429")
+	}
+
+	if retryAfter, ok := parseRetryAfter(&http.Response{Header: http.Header{"Retry-After": {"Wed, 21 Oct 2015 07:28:00 GMT"}}}, time.Date(2015, 10, 21, 7, 27, 30, 0, time.UTC)); !ok || retryAfter != 30*time.Second {
+		t.Errorf("Expected a Retry-After date to be parsed, got %v, %v", retryAfter, ok)
+	}
+}
@@ -0,0 +1,109 @@
+package glance
+
+import (
+	"sync"
+	"time"
+)
+
+// Widgets that have channels in common would each fetch the same YouTube feeds, so
+// widgets with share-feed-cache keep the decoded feeds in a cache shared by all of them,
+// from which the other widgets take the feeds that were fetched shortly before.
+
+const (
+	// Short enough that the feeds are only shared between widgets updating around the
+	// same time, such as when the page is first loaded
+	videoSharedFeedCacheTTL = 5 * time.Minute
+	// Once reached, expired feeds are dropped and if that isn't enough, the oldest ones
+	videoSharedFeedCacheMaxEntries = 1000
+)
+
+type youtubeSharedFeed struct {
+	feed     youtubeFeedResponseXml
+	storedAt time.Time
+}
+
+type youtubeSharedFeedCache struct {
+	mu         sync.Mutex
+	feeds      map[string]youtubeSharedFeed
+	ttl        time.Duration
+	maxEntries int
+}
+
+var youtubeFeedCache = newYoutubeSharedFeedCache(videoSharedFeedCacheTTL, videoSharedFeedCacheMaxEntries)
+
+func newYoutubeSharedFeedCache(ttl time.Duration, maxEntries int) *youtubeSharedFeedCache {
+	return &youtubeSharedFeedCache{
+		feeds:      make(map[string]youtubeSharedFeed),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+	}
+}
+
+// get returns the feed of the URL if it was stored within the TTL. The feed is shared
+// between widgets so it must not be modified.
+func (c *youtubeSharedFeedCache) get(url string) (youtubeFeedResponseXml, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, exists := c.feeds[url]
+	if !exists || time.Since(cached.storedAt) >= c.ttl {
+		return youtubeFeedResponseXml{}, false
+	}
+
+	return cached.feed, true
+}
+
+func (c *youtubeSharedFeedCache) set(url string, feed youtubeFeedResponseXml) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.feeds[url]; !exists && len(c.feeds) >= c.maxEntries {
+		c.evict()
+	}
+
+	c.feeds[url] = youtubeSharedFeed{feed: feed, storedAt: time.Now()}
+}
+
+// evict makes room for a new feed, must be called with the lock held
+func (c *youtubeSharedFeedCache) evict() {
+	var oldestUrl string
+	var oldest time.Time
+
+	for url, cached := range c.feeds {
+		if time.Since(cached.storedAt) >= c.ttl {
+			delete(c.feeds, url)
+			continue
+		}
+
+		if oldestUrl == "" || cached.storedAt.Before(oldest) {
+			oldestUrl, oldest = url, cached.storedAt
+		}
+	}
+
+	if len(c.feeds) >= c.maxEntries {
+		delete(c.feeds, oldestUrl)
+	}
+}
+
+// wrap returns a task which takes the feed from the cache when it's there and otherwise
+// fetches it with the given task, storing it in the cache if that succeeds
+func (c *youtubeSharedFeedCache) wrap(task func(videoFeedRequest) (youtubeFeedResponseXml, error)) func(videoFeedRequest) (youtubeFeedResponseXml, error) {
+	if c == nil {
+		return task
+	}
+
+	return func(r videoFeedRequest) (youtubeFeedResponseXml, error) {
+		url := r.request.URL.String()
+
+		if feed, ok := c.get(url); ok {
+			return feed, nil
+		}
+
+		feed, err := task(r)
+		if err == nil {
+			c.set(url, feed)
+		}
+
+		return feed, err
+	}
+}
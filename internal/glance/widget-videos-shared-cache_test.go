@@ -0,0 +1,55 @@
+package glance
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVideosWidgetsShareFeedCache(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Shared</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`
+
+	shared := newYoutubeSharedFeedCache(time.Minute, 2)
+	sources := []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}}
+	retry := videoRetryOptions{shared: shared}
+
+	first := mapResponseDoer{youtubeFeedUrl(sources[0].ID, true): feed}
+	if videos, err := fetchYoutubeChannelUploads(context.Background(), sources, "", "", true, "", nil, first, retry, videosDefaultConcurrency); err != nil || len(videos) != 1 {
+		t.Fatalf("Expected the video to be fetched, got %v, %v", videos, err)
+	}
+
+	// The second widget's client has no feeds, so the video can only come from the cache
+	second := mapResponseDoer{}
+	if videos, err := fetchYoutubeChannelUploads(context.Background(), sources, "", "", true, "", nil, second, retry, videosDefaultConcurrency); err != nil || len(videos) != 1 {
+		t.Fatalf("Expected the feed to be reused, got %v, %v", videos, err)
+	}
+
+	headers := http.Header{"Cookie": {"CONSENT=YES+"}}
+	if _, err := fetchYoutubeChannelUploads(context.Background(), sources, "", "", true, "", headers, second, retry, videosDefaultConcurrency); err == nil {
+		t.Fatal("Expected feeds requested with custom headers to not be shared")
+	}
+
+	shared.set("https://example.com/a", youtubeFeedResponseXml{})
+	shared.set("https://example.com/b", youtubeFeedResponseXml{})
+	if len(shared.feeds) != 2 {
+		t.Fatalf("Expected the cache to be capped at 2 feeds, has %d", len(shared.feeds))
+	}
+
+	if _, ok := shared.get(youtubeFeedUrl(sources[0].ID, true)); ok {
+		t.Error("Expected the oldest feed to be evicted")
+	}
+
+	shared.ttl = 0
+	if _, ok := shared.get("https://example.com/b"); ok {
+		t.Error("Expected expired feeds to not be returned")
+	}
+}
@@ -0,0 +1,124 @@
+package glance
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// YouTube's feeds can leave out shorts through the ID of the playlist they're fetched
+// from, but other sources have no such option, so when shorts aren't included they're
+// also detected from the videos themselves and removed from the list of every source.
+
+const defaultShortsMaxDuration = 60 * time.Second
+
+var defaultShortsMarkers = []string{"/shorts/", "#shorts"}
+
+const (
+	// Shows shorts until they've been watched, while long videos stay regardless
+	videosShortsHideWatched = "hide-watched"
+	// Shows shorts in a collapsible strip below the other videos of each section
+	videosShortsSeparate = "separate"
+)
+
+type videoShortsField struct {
+	MaxDuration *durationField `yaml:"max-duration"`
+	Markers     []string       `yaml:"markers"`
+
+	maxDuration time.Duration `yaml:"-"`
+}
+
+func (f *videoShortsField) initialize() {
+	if f.MaxDuration == nil {
+		f.maxDuration = defaultShortsMaxDuration
+	} else {
+		f.maxDuration = time.Duration(*f.MaxDuration)
+	}
+
+	// An empty list disables the markers, only leaving them out uses the defaults
+	if f.Markers == nil {
+		f.Markers = defaultShortsMarkers
+	}
+}
+
+// isShort reports whether the video is likely a short, which is when it's known to be
+// no longer than the max duration or when its URL or title contains one of the markers
+func (f *videoShortsField) isShort(v *video) bool {
+	if v.IsCommunityPost {
+		return false
+	}
+
+	if v.Duration > 0 && v.Duration <= f.maxDuration {
+		return true
+	}
+
+	url, title := strings.ToLower(v.Url), strings.ToLower(v.Title)
+	for _, marker := range f.Markers {
+		marker = strings.ToLower(marker)
+		if marker != "" && (strings.Contains(url, marker) || strings.Contains(title, marker)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasVerticalThumbnail reports whether the thumbnail of the video is likely taller than
+// it's wide. Goes by the dimensions of the thumbnail when the source provides them, and
+// otherwise by whether the video is a short, since YouTube's thumbnails of shorts have
+// the same dimensions as the ones of other videos with the vertical frame in the middle.
+func (f *videoShortsField) hasVerticalThumbnail(v *video) bool {
+	if v.ThumbnailWidth > 0 && v.ThumbnailHeight > 0 {
+		return v.ThumbnailHeight > v.ThumbnailWidth
+	}
+
+	return f.isShort(v)
+}
+
+func (widget *videosWidget) isNotShort(v *video) bool {
+	return !widget.ShortsDetection.isShort(v)
+}
+
+// initializeShorts validates the shorts option, with both modes including the shorts
+// since there would be none to hide once watched or to show separately otherwise
+func (widget *videosWidget) initializeShorts() error {
+	switch widget.Shorts {
+	case "":
+		return nil
+	case videosShortsHideWatched, videosShortsSeparate:
+	default:
+		return fmt.Errorf("shorts must be one of %s or %s when set", videosShortsHideWatched, videosShortsSeparate)
+	}
+
+	widget.IncludeShorts = true
+
+	if widget.Shorts == videosShortsHideWatched && !widget.MarkWatched.Enabled && widget.WatchHistory.Source == "" {
+		slog.Warn("shorts: hide-watched has no effect without mark-watched or watch-history, all shorts will be shown")
+	}
+
+	return nil
+}
+
+// hidesWatchedShort reports whether the video is a short that has been watched and
+// should be left out because of shorts: hide-watched
+func (widget *videosWidget) hidesWatchedShort(v *video) bool {
+	return widget.Shorts == videosShortsHideWatched && widget.ShortsDetection.isShort(v) && widget.MarkWatched.isWatched(v)
+}
+
+// separateShorts splits the videos into the ones that aren't shorts and the shorts,
+// keeping the order of both
+func (widget *videosWidget) separateShorts(videos videoList) (videoList, videoList) {
+	long := make(videoList, 0, len(videos))
+	shorts := make(videoList, 0)
+
+	for i := range videos {
+		if widget.ShortsDetection.isShort(&videos[i]) {
+			shorts = append(shorts, videos[i])
+		} else {
+			long = append(long, videos[i])
+		}
+	}
+
+	return long, shorts
+}
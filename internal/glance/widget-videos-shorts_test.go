@@ -0,0 +1,88 @@
+package glance
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVideosWidgetShortsDetection(t *testing.T) {
+	videos := videoList{
+		{Title: "Long video", Url: "https://rumble.com/v1-long.html", Duration: 10 * time.Minute},
+		{Title: "Quick clip", Url: "https://rumble.com/v2-clip.html", Duration: 45 * time.Second},
+		{Title: "Unknown duration", Url: "https://example.com/videos/3"},
+		{Title: "Funny moment #Shorts", Url: "https://example.com/videos/4"},
+		{Title: "Reposted", Url: "https://www.youtube.com/shorts/abc"},
+	}
+
+	titles := func(widget *videosWidget) []string {
+		var result []string
+		for _, v := range videos.filter(widget.isNotShort) {
+			result = append(result, v.Title)
+		}
+		return result
+	}
+
+	widget := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	if result := titles(widget); !slices.Equal(result, []string{"Long video", "Unknown duration"}) {
+		t.Errorf("Expected shorts to be detected by their duration and markers, got %v", result)
+	}
+
+	widget = newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+shorts-detection:
+  max-duration: 0s
+  markers: []
+`)
+	if result := titles(widget); len(result) != len(videos) {
+		t.Errorf("Expected detection to be disabled, got %v", result)
+	}
+}
+
+func TestVideosWidgetSeparatesShorts(t *testing.T) {
+	widget := newTestVideosWidget(t, "shorts: separate\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	if !widget.IncludeShorts {
+		t.Fatal("Expected separate to include shorts")
+	}
+
+	widget.Videos = videoList{
+		{Title: "short", Url: "https://youtube.com/shorts/a"},
+		{Title: "video", Url: "https://youtube.com/watch?v=b"},
+		{Title: "tagged short #shorts", Url: "https://youtube.com/watch?v=c"},
+		{Title: "other video", Url: "https://youtube.com/watch?v=d"},
+	}
+	widget.ContentAvailable = true
+
+	view := &videosWidgetView{videosWidget: widget}
+	section := view.Sections()[0]
+
+	titlesOf := func(videos videoList) []string {
+		titles := make([]string, len(videos))
+		for i := range videos {
+			titles[i] = videos[i].Title
+		}
+		return titles
+	}
+
+	if expected := []string{"video", "other video"}; !slices.Equal(titlesOf(section.Videos), expected) {
+		t.Errorf("Expected videos %v, got %v", expected, titlesOf(section.Videos))
+	}
+
+	if expected := []string{"short", "tagged short #shorts"}; !slices.Equal(titlesOf(section.Shorts), expected) {
+		t.Errorf("Expected shorts %v, got %v", expected, titlesOf(section.Shorts))
+	}
+
+	html := string(widget.Render())
+	if !strings.Contains(html, `class="videos-shorts"`) || !strings.Contains(html, "https://youtube.com/shorts/a") {
+		t.Error("Expected the shorts to be rendered in their own strip")
+	}
+
+	plain := newTestVideosWidget(t, "include-shorts: true\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	plain.Videos = widget.Videos
+	plain.ContentAvailable = true
+
+	if html := string(plain.Render()); strings.Contains(html, `class="videos-shorts"`) {
+		t.Error("Expected no shorts strip without shorts: separate")
+	}
+}
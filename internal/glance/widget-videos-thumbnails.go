@@ -0,0 +1,130 @@
+package glance
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// With validate-thumbnails, the thumbnails of the videos that are going to be shown are
+// checked with HEAD requests during the update and the ones that no longer exist, such
+// as the ones of deleted videos, are replaced with the placeholder rather than showing
+// up as broken images.
+
+// The checks of an update share this timeout, so they can only add as much to it
+const videoThumbnailValidationTimeout = 3 * time.Second
+
+const videoThumbnailValidationWorkers = 10
+
+type videoThumbnailRequest struct {
+	ctx    context.Context
+	url    string
+	client requestDoer
+}
+
+// checkVideoThumbnailTask reports whether the thumbnail is gone. Only responses saying
+// so count, since failing to check it, such as when it's served slowly or HEAD requests
+// aren't allowed, doesn't mean that the thumbnail won't load in the browser.
+func checkVideoThumbnailTask(r videoThumbnailRequest) (bool, error) {
+	request, err := http.NewRequestWithContext(r.ctx, "HEAD", r.url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusNotFound, http.StatusGone:
+		return true, nil
+	case http.StatusOK:
+		return false, nil
+	}
+
+	return false, fmt.Errorf("unexpected status code %d", response.StatusCode)
+}
+
+// validateThumbnails replaces the thumbnails that no longer exist with the placeholder.
+// Thumbnails that were found to exist aren't checked again for as long as they're shown.
+func (widget *videosWidget) validateThumbnails(ctx context.Context, lists ...videoList) {
+	if widget.validThumbnails == nil {
+		widget.validThumbnails = make(map[string]struct{})
+	}
+
+	current := make(map[string]struct{})
+	requests := make([]videoThumbnailRequest, 0)
+
+	ctx, cancel := context.WithTimeout(ctx, videoThumbnailValidationTimeout)
+	defer cancel()
+
+	for _, videos := range lists {
+		for i := range videos {
+			thumbnailUrl := videos[i].ThumbnailUrl
+			lower := strings.ToLower(thumbnailUrl)
+			if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+				continue
+			}
+
+			if _, ok := current[thumbnailUrl]; ok {
+				continue
+			}
+
+			current[thumbnailUrl] = struct{}{}
+			if _, ok := widget.validThumbnails[thumbnailUrl]; !ok {
+				requests = append(requests, videoThumbnailRequest{ctx: ctx, url: thumbnailUrl, client: widget.httpClient()})
+			}
+		}
+	}
+
+	for thumbnailUrl := range widget.validThumbnails {
+		if _, ok := current[thumbnailUrl]; !ok {
+			delete(widget.validThumbnails, thumbnailUrl)
+		}
+	}
+
+	if len(requests) == 0 {
+		return
+	}
+
+	job := newJob(checkVideoThumbnailTask, requests).withWorkers(min(videoThumbnailValidationWorkers, widget.Concurrency))
+	gone, errs, err := workerPoolDo(job)
+	if err != nil {
+		slog.Error("Failed to validate video thumbnails", "error", err)
+		return
+	}
+
+	dead := make(map[string]struct{})
+	for i := range requests {
+		switch {
+		case errs[i] != nil:
+			slog.Debug("Could not validate video thumbnail, keeping it", "url", requests[i].url, "error", errs[i])
+		case gone[i]:
+			dead[requests[i].url] = struct{}{}
+		default:
+			widget.validThumbnails[requests[i].url] = struct{}{}
+		}
+	}
+
+	if len(dead) == 0 {
+		return
+	}
+
+	slog.Debug("Replacing video thumbnails that no longer exist", "count", len(dead))
+
+	placeholder := cmp.Or(widget.PlaceholderThumbnail, videoThumbnailPlaceholder)
+	for _, videos := range lists {
+		for i := range videos {
+			if _, ok := dead[videos[i].ThumbnailUrl]; ok {
+				videos[i].ThumbnailUrl = placeholder
+				videos[i].ThumbnailWidth, videos[i].ThumbnailHeight = 0, 0
+			}
+		}
+	}
+}
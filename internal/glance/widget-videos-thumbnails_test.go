@@ -0,0 +1,65 @@
+package glance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestVideosWidgetValidateThumbnails(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if r.Method != http.MethodHead {
+			t.Errorf("Expected a HEAD request, got %s", r.Method)
+		}
+
+		switch r.URL.Path {
+		case "/ok.jpg":
+			w.WriteHeader(http.StatusOK)
+		case "/gone.jpg":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\nvalidate-thumbnails: true")
+	widget.Proxy.client = server.Client()
+
+	videos := videoList{
+		{Title: "Ok", ThumbnailUrl: server.URL + "/ok.jpg"},
+		{Title: "Gone", ThumbnailUrl: server.URL + "/gone.jpg", ThumbnailWidth: 720, ThumbnailHeight: 1280},
+		{Title: "Unknown", ThumbnailUrl: server.URL + "/head-not-allowed.jpg"},
+		{Title: "Same", ThumbnailUrl: server.URL + "/ok.jpg"},
+		{Title: "Missing", ThumbnailUrl: videoThumbnailPlaceholder},
+	}
+	widget.validateThumbnails(context.Background(), videos)
+
+	if requests.Load() != 3 {
+		t.Errorf("Expected each distinct thumbnail URL to be checked once, got %d requests", requests.Load())
+	}
+
+	// Thumbnails that couldn't be checked are kept since they may still load in the browser
+	for _, v := range videos {
+		replaced := v.ThumbnailUrl == videoThumbnailPlaceholder
+		if replaced != (v.Title == "Gone" || v.Title == "Missing") {
+			t.Errorf("Unexpected thumbnail for %s: %s", v.Title, v.ThumbnailUrl)
+		}
+	}
+
+	if videos[1].ThumbnailWidth != 0 || videos[1].ThumbnailHeight != 0 {
+		t.Error("Expected the dimensions of the replaced thumbnail to be cleared")
+	}
+
+	// Thumbnails that exist aren't checked again
+	requests.Store(0)
+	widget.validateThumbnails(context.Background(), videoList{{Title: "Ok", ThumbnailUrl: server.URL + "/ok.jpg"}})
+	if requests.Load() != 0 {
+		t.Errorf("Expected thumbnails that exist to not be checked again, got %d requests", requests.Load())
+	}
+}
@@ -0,0 +1,105 @@
+package glance
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"time"
+)
+
+// When videos were posted is shown relative to now, with days counted in the widget's
+// timezone so that "yesterday" means the same day for everyone looking at the widget.
+// The same rules are applied in the browser to keep the times up to date.
+
+func pluralize(count int, singular string) string {
+	if count == 1 {
+		return "1 " + singular
+	}
+
+	return fmt.Sprintf("%d %ss", count, singular)
+}
+
+// calendarDaysBetween returns the number of midnights between from and to in loc
+func calendarDaysBetween(from, to time.Time, loc *time.Location) int {
+	from, to = from.In(loc), to.In(loc)
+
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
+
+// formatRelativeVideoTime formats t relative to now, e.g. "2h ago", "yesterday" or
+// "3 days ago". Videos without a time are shown as "unknown".
+func formatRelativeVideoTime(t, now time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+
+	if t.After(now) {
+		delta := t.Sub(now)
+
+		switch {
+		case delta < time.Hour:
+			return fmt.Sprintf("in %dm", max(int(delta.Minutes()), 1))
+		case delta < 24*time.Hour:
+			return fmt.Sprintf("in %dh", int(delta.Hours()))
+		}
+
+		return "in " + pluralize(calendarDaysBetween(now, t, loc), "day")
+	}
+
+	delta := now.Sub(t)
+
+	switch {
+	case delta < time.Minute:
+		return "just now"
+	case delta < time.Hour:
+		return fmt.Sprintf("%dm ago", int(delta.Minutes()))
+	}
+
+	days := calendarDaysBetween(t, now, loc)
+
+	switch {
+	case days == 0:
+		return fmt.Sprintf("%dh ago", int(delta.Hours()))
+	case days == 1:
+		return "yesterday"
+	case days < 30:
+		return pluralize(days, "day") + " ago"
+	case days < 365:
+		return pluralize(days/30, "month") + " ago"
+	}
+
+	return pluralize(days/365, "year") + " ago"
+}
+
+func (v video) locationOrLocal() *time.Location {
+	if v.timezone != nil {
+		return v.timezone
+	}
+
+	return time.Local
+}
+
+// RelativeTimePosted returns when the video was posted relative to now
+func (v video) RelativeTimePosted() string {
+	return formatRelativeVideoTime(v.TimePosted, time.Now(), v.locationOrLocal())
+}
+
+// TimePostedAttrs returns the attributes through which the browser keeps the relative
+// time up to date, along with the exact time as the title
+func (v video) TimePostedAttrs() template.HTMLAttr {
+	if v.TimePosted.IsZero() {
+		return ""
+	}
+
+	attrs := fmt.Sprintf(`data-dynamic-relative-time="%d" data-relative-time-style="long"`, v.TimePosted.Unix())
+	if v.timezone != nil {
+		attrs += fmt.Sprintf(` data-timezone="%s"`, html.EscapeString(v.timezone.String()))
+	}
+
+	title := v.TimePosted.In(v.locationOrLocal()).Format("Jan 2, 2006 15:04 MST")
+
+	return template.HTMLAttr(attrs + fmt.Sprintf(` title="%s"`, html.EscapeString(title)))
+}
@@ -0,0 +1,68 @@
+package glance
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFormatRelativeVideoTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("Timezone data unavailable: %v", err)
+	}
+
+	// 03:00 UTC is still the previous evening in New York
+	now := time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		posted   time.Time
+		loc      *time.Location
+		expected string
+	}{
+		{posted: time.Time{}, loc: time.UTC, expected: "unknown"},
+		{posted: now.Add(-20 * time.Second), loc: time.UTC, expected: "just now"},
+		{posted: now.Add(-5 * time.Minute), loc: time.UTC, expected: "5m ago"},
+		{posted: now.Add(-2 * time.Hour), loc: time.UTC, expected: "2h ago"},
+		{posted: now.Add(-5 * time.Hour), loc: time.UTC, expected: "yesterday"},
+		{posted: now.Add(-5 * time.Hour), loc: newYork, expected: "5h ago"},
+		{posted: now.Add(-72 * time.Hour), loc: time.UTC, expected: "3 days ago"},
+		{posted: now.Add(-24 * 45 * time.Hour), loc: time.UTC, expected: "1 month ago"},
+		{posted: now.Add(-24 * 800 * time.Hour), loc: time.UTC, expected: "2 years ago"},
+		{posted: now.Add(30 * time.Minute), loc: time.UTC, expected: "in 30m"},
+		{posted: now.Add(49 * time.Hour), loc: time.UTC, expected: "in 2 days"},
+	}
+
+	for _, test := range tests {
+		if result := formatRelativeVideoTime(test.posted, now, test.loc); result != test.expected {
+			t.Errorf("Expected %v in %v to be %q, got %q", test.posted, test.loc, test.expected, result)
+		}
+	}
+
+	widget := newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+timezone: America/New_York
+`)
+	widget.Videos = videoList{{Title: "Video", Url: "https://a", TimePosted: time.Now().Add(-time.Hour)}}
+
+	view := &videosWidgetView{videosWidget: widget}
+	attrs := string(view.Sections()[0].Videos[0].TimePostedAttrs())
+	if !strings.Contains(attrs, `data-timezone="America/New_York"`) || !strings.Contains(attrs, `data-relative-time-style="long"`) {
+		t.Errorf("Expected the widget's timezone in the attributes, got %s", attrs)
+	}
+
+	if attrs := (video{}).TimePostedAttrs(); attrs != "" {
+		t.Errorf("Expected no attributes for a video without a time, got %s", attrs)
+	}
+
+	invalid := &videosWidget{}
+	if err := yaml.Unmarshal([]byte("channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\ntimezone: Nowhere/Else\n"), invalid); err != nil {
+		t.Fatalf("Failed to decode widget config: %v", err)
+	}
+
+	if err := invalid.initialize(); err == nil {
+		t.Error("Expected an error for an invalid timezone")
+	}
+}
@@ -0,0 +1,247 @@
+package glance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Twitch has no feeds, so the past broadcasts and uploads of channels are listed
+// through the Helix API, which requires the client ID of a registered application
+// along with an access token.
+
+const twitchHelixBaseUrl = "https://api.twitch.tv/helix"
+
+// The most logins the users endpoint accepts in a single request
+const twitchMaxLoginsPerRequest = 100
+
+// Tokens expire after a while, at which point they have to be replaced in the config
+var errTwitchUnauthorized = errors.New("twitch token is invalid or has expired")
+
+type twitchUsersResponseJson struct {
+	Data []struct {
+		ID          string `json:"id"`
+		Login       string `json:"login"`
+		DisplayName string `json:"display_name"`
+	} `json:"data"`
+}
+
+type twitchVideosResponseJson struct {
+	Data []struct {
+		ID           string `json:"id"`
+		UserLogin    string `json:"user_login"`
+		UserName     string `json:"user_name"`
+		Title        string `json:"title"`
+		Url          string `json:"url"`
+		ThumbnailUrl string `json:"thumbnail_url"`
+		PublishedAt  string `json:"published_at"`
+		ViewCount    int    `json:"view_count"`
+		Duration     string `json:"duration"`
+	} `json:"data"`
+}
+
+type twitchHelixRequest struct {
+	request *http.Request
+	client  requestDoer
+}
+
+// twitchLoginFromSource returns the login of a channel, such as "someone", from
+// either the login itself or a link to the channel
+func twitchLoginFromSource(id string) string {
+	id = strings.TrimPrefix(id, "https://")
+	id = strings.TrimPrefix(id, "www.")
+	id = strings.TrimPrefix(id, "twitch.tv/")
+
+	return strings.ToLower(strings.Trim(id, "/"))
+}
+
+// twitchThumbnailUrl fills in the size of the thumbnail, which Twitch leaves as
+// placeholders. Videos that are still being processed have no thumbnail yet.
+func twitchThumbnailUrl(thumbnailUrl string) string {
+	if thumbnailUrl == "" || strings.Contains(thumbnailUrl, "404_processing") {
+		return ""
+	}
+
+	thumbnailUrl = strings.ReplaceAll(thumbnailUrl, "%{width}", "640")
+	thumbnailUrl = strings.ReplaceAll(thumbnailUrl, "%{height}", "360")
+
+	return thumbnailUrl
+}
+
+func newTwitchHelixRequest(ctx context.Context, path string, query url.Values, clientID, token string) *http.Request {
+	request, _ := http.NewRequestWithContext(ctx, "GET", twitchHelixBaseUrl+path+"?"+query.Encode(), nil)
+	request.Header.Set("Client-Id", clientID)
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	return request
+}
+
+func decodeTwitchHelixTask[T any](r twitchHelixRequest) (T, error) {
+	var result T
+
+	response, err := r.client.Do(r.request)
+	if err != nil {
+		return result, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusUnauthorized {
+		return result, errTwitchUnauthorized
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return result, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		truncatedBody, _ := limitStringLength(string(body), 256)
+		err := fmt.Errorf("unexpected status code %d from %s, response: %s", response.StatusCode, r.request.URL, truncatedBody)
+		return result, &videoFeedStatusError{status: response.StatusCode, err: err}
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// fetchTwitchChannelVideos fetches the recent videos of Twitch channels through the
+// Helix API. An expired or invalid token fails every channel with errTwitchUnauthorized.
+func fetchTwitchChannelVideos(ctx context.Context, sources []videoSourceField, clientID, token string, limit int, client requestDoer, retry videoRetryOptions, workers int) (videoList, error) {
+	if clientID == "" || token == "" {
+		return nil, fmt.Errorf("%w: twitch-client-id and twitch-token are required for twitch-channels", errNoContent)
+	}
+
+	// Channels are identified by their numeric ID in the videos endpoint, which is
+	// looked up from their logins first
+	logins := make([]string, len(sources))
+	for i := range sources {
+		logins[i] = twitchLoginFromSource(sources[i].ID)
+	}
+
+	userIDs := make(map[string]string, len(logins))
+	for start := 0; start < len(logins); start += twitchMaxLoginsPerRequest {
+		batch := logins[start:min(start+twitchMaxLoginsPerRequest, len(logins))]
+		request := newTwitchHelixRequest(ctx, "/users", url.Values{"login": batch}, clientID, token)
+
+		users, err := decodeTwitchHelixTask[twitchUsersResponseJson](twitchHelixRequest{request: request, client: retry.wrap(client)})
+		if err != nil {
+			return nil, fmt.Errorf("%w: looking up twitch channels: %w", errNoContent, err)
+		}
+
+		for i := range users.Data {
+			userIDs[strings.ToLower(users.Data[i].Login)] = users.Data[i].ID
+		}
+	}
+
+	first := 20
+	if limit > 0 {
+		first = min(limit, 100)
+	}
+
+	requests := make([]twitchHelixRequest, 0, len(sources))
+	requestSources := make([]int, 0, len(sources))
+	var failed []videoSourceError
+
+	for i := range sources {
+		userID, ok := userIDs[logins[i]]
+		if !ok {
+			failed = append(failed, videoSourceError{source: sources[i].ID, err: errVideoSourceNotFound})
+			slog.Error("Twitch channel not found", "channel", sources[i].ID)
+			continue
+		}
+
+		query := url.Values{"user_id": {userID}, "first": {fmt.Sprint(first)}}
+		requests = append(requests, twitchHelixRequest{
+			request: newTwitchHelixRequest(ctx, "/videos", query, clientID, token),
+			client:  retry.wrap(sources[i].clientFor(client)),
+		})
+		requestSources = append(requestSources, i)
+	}
+
+	job := newJob(decodeTwitchHelixTask[twitchVideosResponseJson], requests).withWorkers(workers)
+	responses, errs, err := workerPoolDo(job)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNoContent, err)
+	}
+
+	videos := make(videoList, 0, len(requests)*first)
+	var unauthorized bool
+
+	for i := range responses {
+		source := &sources[requestSources[i]]
+
+		if errs[i] != nil {
+			failed = append(failed, videoSourceError{source: source.ID, err: errs[i]})
+			unauthorized = unauthorized || errors.Is(errs[i], errTwitchUnauthorized)
+			slog.Error("Failed to fetch twitch videos", "channel", source.ID, "error", errs[i])
+			continue
+		}
+
+		for j := range responses[i].Data {
+			v := &responses[i].Data[j]
+
+			if v.Title == "" || v.Url == "" || source.excludesTitle(v.Title) {
+				continue
+			}
+
+			timePosted, err := time.Parse(time.RFC3339, v.PublishedAt)
+			if err != nil {
+				slog.Warn("Skipping Twitch video with invalid publish time", "channel", source.ID, "title", v.Title, "published", v.PublishedAt)
+				continue
+			}
+
+			thumbnailUrl := twitchThumbnailUrl(v.ThumbnailUrl)
+			if thumbnailUrl == "" {
+				thumbnailUrl = videoThumbnailPlaceholder
+			}
+
+			duration, _ := time.ParseDuration(v.Duration)
+
+			videos = append(videos, video{
+				ThumbnailUrl: thumbnailUrl,
+				Title:        v.Title,
+				Url:          v.Url,
+				Author:       v.UserName,
+				AuthorUrl:    "https://www.twitch.tv/" + v.UserLogin,
+				VideoID:      v.ID,
+				Source:       videoSourceTwitch,
+				sourceID:     source.ID,
+				TimePosted:   timePosted,
+				Views:        v.ViewCount,
+				Duration:     duration,
+			})
+		}
+	}
+
+	noContent, partialContent := errNoContent, errPartialContent
+	if unauthorized {
+		noContent = fmt.Errorf("%w: %w", errNoContent, errTwitchUnauthorized)
+		partialContent = fmt.Errorf("%w: %w", errPartialContent, errTwitchUnauthorized)
+	}
+
+	if len(videos) == 0 {
+		if len(failed) > 0 {
+			return nil, &videoSourceFailures{err: noContent, failed: failed, total: len(sources)}
+		}
+
+		return nil, errNoContent
+	}
+
+	videos.sortByNewest()
+
+	if len(failed) > 0 {
+		return videos, &videoSourceFailures{err: partialContent, failed: failed, total: len(sources)}
+	}
+
+	return videos, nil
+}
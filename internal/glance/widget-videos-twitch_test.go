@@ -0,0 +1,82 @@
+package glance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFetchTwitchChannelVideos(t *testing.T) {
+	var expired atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expired.Load() || r.Header.Get("Client-Id") != "client" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/helix/users":
+			fmt.Fprint(w, `{"data": [{"id": "1", "login": "someone", "display_name": "Someone"}]}`)
+		case "/helix/videos":
+			fmt.Fprint(w, `{"data": [{
+				"id": "42", "user_login": "someone", "user_name": "Someone", "title": "Past broadcast",
+				"url": "https://www.twitch.tv/videos/42", "published_at": "2025-01-14T14:00:00Z",
+				"thumbnail_url": "https://static-cdn.jtvnw.net/cf_vods/42/thumb0-%{width}x%{height}.jpg",
+				"view_count": 120, "duration": "1h2m3s"
+			}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: redirectTransport{server: server}}
+	sources := []videoSourceField{{ID: "https://www.twitch.tv/Someone"}, {ID: "missing"}}
+
+	videos, err := fetchTwitchChannelVideos(context.Background(), sources, "client", "token", 0, client, videoRetryOptions{}, videosDefaultConcurrency)
+	if !errors.Is(err, errPartialContent) || len(videos) != 1 {
+		t.Fatalf("Expected a single video and a partial content error, got %+v, %v", videos, err)
+	}
+
+	v := videos[0]
+	if v.Author != "Someone" || v.AuthorUrl != "https://www.twitch.tv/someone" || v.Source != videoSourceTwitch || v.Views != 120 {
+		t.Errorf("Unexpected video details: %+v", v)
+	}
+
+	if v.ThumbnailUrl != "https://static-cdn.jtvnw.net/cf_vods/42/thumb0-640x360.jpg" || v.Duration != time.Hour+2*time.Minute+3*time.Second {
+		t.Errorf("Unexpected video details: %+v", v)
+	}
+
+	expired.Store(true)
+
+	widget := newTestVideosWidget(t, `
+twitch-channels: [someone]
+twitch-client-id: client
+twitch-token: token
+max-retries: -1
+`)
+	widget.Proxy.client = client
+	widget.update(context.Background())
+
+	if widget.Error == nil || !strings.Contains(widget.Error.Error(), errTwitchUnauthorized.Error()) {
+		t.Errorf("Expected the expired token to be shown as the widget's error, got %v", widget.Error)
+	}
+
+	invalid := &videosWidget{}
+	if err := yaml.Unmarshal([]byte("twitch-channels: [someone]\n"), invalid); err != nil {
+		t.Fatalf("Failed to decode widget config: %v", err)
+	}
+
+	if err := invalid.initialize(); err == nil {
+		t.Error("Expected an error for twitch-channels without credentials")
+	}
+}
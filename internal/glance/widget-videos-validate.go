@@ -0,0 +1,179 @@
+package glance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// videoSourceCheck is the outcome of checking whether the feed of a single source is
+// reachable, without fetching its videos
+type videoSourceCheck struct {
+	kind   string
+	source string
+	// The URL that responded, or the last one that was tried when none did
+	url    string
+	status int
+	err    error
+	// Why the source wasn't checked, for sources that aren't fetched from a feed
+	skipped string
+}
+
+type videoFeedCheckRequest struct {
+	ctx     context.Context
+	urls    []string
+	headers http.Header
+	client  requestDoer
+}
+
+type videoFeedCheckResult struct {
+	url    string
+	status int
+}
+
+// checkVideoFeedTask requests the URLs in order until one of them responds
+// successfully, the same way fetching the source falls back to its other URLs
+func checkVideoFeedTask(r videoFeedCheckRequest) (videoFeedCheckResult, error) {
+	var result videoFeedCheckResult
+	var err error
+
+	for _, url := range r.urls {
+		result.url = url
+		result.status, err = checkVideoFeedUrl(r.ctx, url, r.headers, r.client)
+		if err == nil || r.ctx.Err() != nil {
+			break
+		}
+	}
+
+	return result, err
+}
+
+// checkVideoFeedUrl makes a HEAD request for the URL, falling back to a GET request
+// whose body is mostly left unread for servers that don't support HEAD requests
+func checkVideoFeedUrl(ctx context.Context, url string, headers http.Header, client requestDoer) (int, error) {
+	status := 0
+
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		request, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		setRequestHeaders(request, headers)
+
+		response, err := client.Do(request)
+		if err != nil {
+			return 0, err
+		}
+
+		io.Copy(io.Discard, io.LimitReader(response.Body, 4096))
+		response.Body.Close()
+
+		status = response.StatusCode
+		if status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented {
+			break
+		}
+	}
+
+	if status < 200 || status >= 300 {
+		return status, fmt.Errorf("unexpected status code %d", status)
+	}
+
+	return status, nil
+}
+
+// validate checks that the feed of each configured source is reachable, using the same
+// URLs as when fetching the videos but without downloading the feeds or touching the
+// widget's videos. Channel handles are resolved first since they can't be checked otherwise.
+func (widget *videosWidget) validate(ctx context.Context) []videoSourceCheck {
+	checks := make([]videoSourceCheck, 0)
+	requests := make([]videoFeedCheckRequest, 0)
+	checkIndexes := make([]int, 0)
+
+	// The conditional cache is left out since the responses of HEAD requests don't have a body
+	retry := videoRetryOptions{
+		retries:   max(widget.MaxRetries, 0),
+		timeout:   time.Duration(widget.RequestTimeout),
+		userAgent: widget.UserAgent,
+		limiter:   widget.rateLimiter,
+	}
+
+	add := func(kind string, source videoSourceField, headers http.Header, urls ...string) {
+		checkIndexes = append(checkIndexes, len(checks))
+		checks = append(checks, videoSourceCheck{kind: kind, source: source.ID})
+		requests = append(requests, videoFeedCheckRequest{
+			ctx:     ctx,
+			urls:    urls,
+			headers: headers,
+			client:  retry.wrap(source.clientFor(widget.httpClient())),
+		})
+	}
+
+	for _, section := range widget.Sections() {
+		for _, source := range section.Channels {
+			resolved := widget.resolveHandles(ctx, []videoSourceField{source})
+			if len(resolved) == 0 {
+				checks = append(checks, videoSourceCheck{kind: videoSourceYoutube, source: source.ID, err: errors.New("could not resolve handle")})
+				continue
+			}
+
+			add(videoSourceYoutube, source, widget.youtubeHeaders, youtubeFeedUrl(resolved[0].ID, widget.IncludeShorts))
+		}
+
+		for _, source := range section.RumbleChannels {
+			urls := []string{rumbleDirectFeedUrl(source.ID)}
+			for _, bridge := range rumbleBridges(widget.RumbleFeedBase) {
+				urls = append(urls, bridge+source.ID)
+			}
+
+			add(videoSourceRumble, source, nil, urls...)
+		}
+
+		for _, source := range section.VimeoChannels {
+			add(videoSourceVimeo, source, nil, vimeoFeedUrl(source.ID))
+		}
+
+		for _, source := range section.TwitchChannels {
+			checks = append(checks, videoSourceCheck{kind: videoSourceTwitch, source: source.ID, skipped: "fetched through the Twitch API"})
+		}
+
+		for _, source := range section.OdyseeChannels {
+			add(videoSourceOdysee, source, nil, odyseeFeedUrl(source.ID))
+		}
+
+		for _, source := range section.NebulaChannels {
+			add(videoSourceNebula, source, http.Header{"Authorization": {"Bearer " + widget.NebulaToken}}, nebulaEpisodesUrl(nebulaChannelSlug(source.ID), 1))
+		}
+
+		for _, source := range section.BitchuteChannels {
+			add(videoSourceBitchute, source, nil, bitchuteFeedUrl(source.ID))
+		}
+
+		for _, source := range section.Feeds {
+			add(videoSourceFeed, source, nil, source.ID)
+		}
+	}
+
+	if len(requests) == 0 {
+		return checks
+	}
+
+	job := newJob(checkVideoFeedTask, requests).withWorkers(widget.Concurrency)
+	results, errs, err := workerPoolDo(job)
+
+	for i, index := range checkIndexes {
+		if err != nil {
+			checks[index].err = err
+			continue
+		}
+
+		checks[index].url = results[i].url
+		checks[index].status = results[i].status
+		checks[index].err = errs[i]
+	}
+
+	return checks
+}
@@ -0,0 +1,68 @@
+package glance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestVideosWidgetValidate(t *testing.T) {
+	var methods sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods.Store(r.URL.String(), r.Method)
+
+		switch {
+		case r.URL.Query().Get("channel_id") == "UCbroken":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/noheadsupport/videos/rss" && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+include-shorts: true
+max-retries: -1
+channels: [UCworking, UCbroken]
+vimeo-channels: [noheadsupport]
+twitch-channels: [streamer]
+twitch-client-id: id
+twitch-token: token
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+
+	checks := widget.validate(context.Background())
+	if len(checks) != 4 {
+		t.Fatalf("Expected a check for every source, got %+v", checks)
+	}
+
+	if checks[0].err != nil || checks[0].url != "https://www.youtube.com/feeds/videos.xml?channel_id=UCworking" {
+		t.Errorf("Expected the working channel to be reachable through its feed, got %+v", checks[0])
+	}
+
+	if checks[1].err == nil || checks[1].status != http.StatusNotFound {
+		t.Errorf("Expected the broken channel to be reported, got %+v", checks[1])
+	}
+
+	if checks[2].err != nil || checks[2].kind != videoSourceVimeo {
+		t.Errorf("Expected the Vimeo channel to be checked through a GET request, got %+v", checks[2])
+	}
+
+	if checks[3].skipped == "" {
+		t.Errorf("Expected the Twitch channel to be skipped, got %+v", checks[3])
+	}
+
+	if method, _ := methods.Load("/feeds/videos.xml?channel_id=UCworking"); method != http.MethodHead {
+		t.Errorf("Expected feeds to be checked with HEAD requests, got %v", method)
+	}
+
+	if method, _ := methods.Load("/noheadsupport/videos/rss"); method != http.MethodGet {
+		t.Errorf("Expected a GET request after the HEAD request was rejected, got %v", method)
+	}
+
+	if len(widget.Videos) != 0 {
+		t.Errorf("Expected the widget's videos to be left untouched, got %+v", widget.Videos)
+	}
+}
@@ -0,0 +1,150 @@
+package glance
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Vimeo provides an RSS feed of the uploads of every user and channel, so there's
+// no need for a bridge like there is with Rumble.
+
+type vimeoFeedResponseXml struct {
+	Channel     string `xml:"channel>title"`
+	ChannelLink string `xml:"channel>link"`
+	Videos      []struct {
+		Title     string `xml:"title"`
+		Published string `xml:"pubDate"`
+		Link      string `xml:"link"`
+		Creator   string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+		Content   struct {
+			Duration  string `xml:"duration,attr"`
+			Thumbnail struct {
+				Url string `xml:"url,attr"`
+			} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+		} `xml:"http://search.yahoo.com/mrss/ content"`
+	} `xml:"channel>item"`
+}
+
+// vimeoFeedUrl returns the URL of the uploads feed of a user, such as "someone", or
+// of a channel, such as "channels/staffpicks". Full links to the profile also work.
+func vimeoFeedUrl(user string) string {
+	user = strings.TrimPrefix(user, "https://")
+	user = strings.TrimPrefix(user, "www.")
+	user = strings.TrimPrefix(user, "vimeo.com/")
+
+	return "https://vimeo.com/" + strings.Trim(user, "/") + "/videos/rss"
+}
+
+// extractVimeoVideoID returns the numeric ID at the end of links such as
+// https://vimeo.com/123456789
+func extractVimeoVideoID(videoUrl string) string {
+	id := videoUrl[strings.LastIndex(videoUrl, "/")+1:]
+	if id == "" || strings.Trim(id, "0123456789") != "" {
+		return ""
+	}
+
+	return id
+}
+
+// Feed titles are in the form of "Vimeo / Someone's videos"
+func vimeoAuthorFromFeedTitle(title string) string {
+	title = strings.TrimPrefix(title, "Vimeo / ")
+	title = strings.TrimSuffix(title, "'s videos")
+
+	return title
+}
+
+// fetchVimeoChannelUploads fetches videos from Vimeo users and channels
+func fetchVimeoChannelUploads(ctx context.Context, sources []videoSourceField, videoUrlTemplate string, client requestDoer, retry videoRetryOptions, workers int) (videoList, error) {
+	users := videoSourceIDs(sources)
+	requests := make([]videoFeedRequest, 0, len(users))
+
+	for i := range users {
+		request, _ := http.NewRequestWithContext(ctx, "GET", vimeoFeedUrl(users[i]), nil)
+		requests = append(requests, videoFeedRequest{request: request, client: retry.wrap(sources[i].clientFor(client))})
+	}
+
+	job := newJob(decodeVideoFeedTask[vimeoFeedResponseXml], requests).withWorkers(workers)
+	responses, errs, err := workerPoolDo(job)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNoContent, err)
+	}
+
+	videos := make(videoList, 0, len(users)*15)
+	var failed []videoSourceError
+
+	for i := range responses {
+		if errs[i] != nil {
+			failed = append(failed, videoSourceError{source: users[i], err: errs[i]})
+			slog.Error("Failed to fetch vimeo feed", "channel", users[i], "error", errs[i])
+			continue
+		}
+
+		response := responses[i]
+
+		for j := range response.Videos {
+			v := &response.Videos[j]
+
+			if v.Title == "" || v.Link == "" {
+				continue
+			}
+
+			if sources[i].excludesTitle(v.Title) {
+				continue
+			}
+
+			// Vimeo uses the same date format as the other RSS feeds
+			timePosted, err := parseRumbleFeedTime(v.Published)
+			if err != nil {
+				slog.Warn("Skipping Vimeo video with invalid publish time", "channel", users[i], "title", v.Title, "published", v.Published)
+				continue
+			}
+
+			videoUrl := v.Link
+			if id := extractVimeoVideoID(v.Link); videoUrlTemplate != "" && id != "" {
+				videoUrl = strings.ReplaceAll(videoUrlTemplate, "{VIDEO-ID}", id)
+			}
+
+			author := v.Creator
+			if author == "" {
+				author = vimeoAuthorFromFeedTitle(response.Channel)
+			}
+
+			thumbnailUrl := v.Content.Thumbnail.Url
+			if thumbnailUrl == "" {
+				thumbnailUrl = videoThumbnailPlaceholder
+			}
+
+			videos = append(videos, video{
+				ThumbnailUrl: thumbnailUrl,
+				Title:        v.Title,
+				Url:          videoUrl,
+				Author:       author,
+				AuthorUrl:    strings.TrimSuffix(response.ChannelLink, "/videos"),
+				Source:       videoSourceVimeo,
+				sourceID:     sources[i].ID,
+				Duration:     parseFeedDuration(v.Content.Duration),
+				TimePosted:   timePosted,
+			})
+		}
+	}
+
+	if len(videos) == 0 {
+		if len(failed) > 0 {
+			return nil, &videoSourceFailures{err: errNoContent, failed: failed, total: len(sources)}
+		}
+
+		return nil, errNoContent
+	}
+
+	videos.sortByNewest()
+
+	if len(failed) > 0 {
+		return videos, &videoSourceFailures{err: errPartialContent, failed: failed, total: len(sources)}
+	}
+
+	return videos, nil
+}
@@ -0,0 +1,45 @@
+package glance
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFetchVimeoChannelUploads(t *testing.T) {
+	client := mapResponseDoer{
+		"https://vimeo.com/someone/videos/rss": `<?xml version="1.0" encoding="utf-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Vimeo / Someone's videos</title>
+    <link>https://vimeo.com/someone/videos</link>
+    <item>
+      <title>Short film</title>
+      <pubDate>Tue, 14 Jan 2025 14:00:00 -0500</pubDate>
+      <link>https://vimeo.com/123456</link>
+      <media:content>
+        <media:thumbnail height="540" width="960" url="https://i.vimeocdn.com/video/1.jpg"/>
+      </media:content>
+    </item>
+  </channel>
+</rss>`,
+	}
+
+	videos, err := fetchVimeoChannelUploads(context.Background(), []videoSourceField{{ID: "someone"}, {ID: "missing"}}, "https://frontend.example/{VIDEO-ID}", client, videoRetryOptions{}, videosDefaultConcurrency)
+	if !errors.Is(err, errPartialContent) {
+		t.Fatalf("Expected partial content error, got %v", err)
+	}
+
+	if len(videos) != 1 {
+		t.Fatalf("Expected a single video, got %+v", videos)
+	}
+
+	v := videos[0]
+	if v.Url != "https://frontend.example/123456" || v.Author != "Someone" || v.AuthorUrl != "https://vimeo.com/someone" {
+		t.Errorf("Unexpected video details: %+v", v)
+	}
+
+	if v.ThumbnailUrl != "https://i.vimeocdn.com/video/1.jpg" || v.Source != videoSourceVimeo || v.TimePosted.Year() != 2025 {
+		t.Errorf("Unexpected video details: %+v", v)
+	}
+}
@@ -0,0 +1,217 @@
+package glance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Support for hiding or dimming videos that the user has already watched, based on
+// a list of watched videos that's kept outside of Glance, such as a Google Takeout
+// export or a file maintained by a local logger.
+
+const (
+	videoWatchHistoryHide = "hide"
+	videoWatchHistoryDim  = "dim"
+)
+
+const videoWatchHistoryDefaultRefresh = time.Hour
+
+// Watch history exports can get quite large, but not this large
+const videoWatchHistoryMaxSize = 64 * 1024 * 1024
+
+type videoWatchHistoryField struct {
+	Source  string        `yaml:"source"`
+	Action  string        `yaml:"action"`
+	Refresh durationField `yaml:"refresh"`
+
+	watched  map[string]struct{} `yaml:"-"`
+	loadedAt time.Time           `yaml:"-"`
+}
+
+func (h *videoWatchHistoryField) initialize() error {
+	if h.Source == "" {
+		return nil
+	}
+
+	switch h.Action {
+	case "":
+		h.Action = videoWatchHistoryHide
+	case videoWatchHistoryHide, videoWatchHistoryDim:
+	default:
+		return fmt.Errorf("watch-history action must be either %s or %s", videoWatchHistoryHide, videoWatchHistoryDim)
+	}
+
+	if h.Refresh <= 0 {
+		h.Refresh = durationField(videoWatchHistoryDefaultRefresh)
+	}
+
+	return nil
+}
+
+// refresh reloads the watched videos if they haven't been loaded yet or the refresh
+// interval has passed. When loading fails the previously loaded videos are kept.
+func (h *videoWatchHistoryField) refresh(ctx context.Context, client requestDoer) {
+	if h.Source == "" || (!h.loadedAt.IsZero() && time.Since(h.loadedAt) < time.Duration(h.Refresh)) {
+		return
+	}
+
+	// Don't retry on every update if the source is broken
+	h.loadedAt = time.Now()
+
+	watched, err := loadVideoWatchHistory(ctx, h.Source, client)
+	if err != nil {
+		slog.Warn("Failed to load videos watch history, keeping the previous one", "source", h.Source, "error", err)
+		return
+	}
+
+	h.watched = watched
+}
+
+// invalidate makes the next refresh reload the watched videos regardless of when
+// they were last loaded
+func (h *videoWatchHistoryField) invalidate() {
+	h.loadedAt = time.Time{}
+}
+
+func (h *videoWatchHistoryField) isWatched(v *video) bool {
+	if len(h.watched) == 0 {
+		return false
+	}
+
+	if v.VideoID != "" {
+		if _, ok := h.watched[v.VideoID]; ok {
+			return true
+		}
+	}
+
+	_, ok := h.watched[v.Url]
+	return ok
+}
+
+// apply hides or marks the watched videos depending on the configured action
+func (h *videoWatchHistoryField) apply(videos videoList) videoList {
+	if len(h.watched) == 0 {
+		return videos
+	}
+
+	if h.Action == videoWatchHistoryHide {
+		return videos.filter(func(v *video) bool {
+			return !h.isWatched(v)
+		})
+	}
+
+	for i := range videos {
+		videos[i].Watched = h.isWatched(&videos[i])
+	}
+
+	return videos
+}
+
+// loadVideoWatchHistory reads the watched videos from a file or URL
+func loadVideoWatchHistory(ctx context.Context, source string, client requestDoer) (map[string]struct{}, error) {
+	var contents []byte
+
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		request, err := http.NewRequestWithContext(ctx, "GET", source, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := client.Do(request)
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code %d", response.StatusCode)
+		}
+
+		contents, err = io.ReadAll(io.LimitReader(response.Body, videoWatchHistoryMaxSize))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		contents, err = io.ReadAll(io.LimitReader(file, videoWatchHistoryMaxSize))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return parseVideoWatchHistory(contents)
+}
+
+// parseVideoWatchHistory accepts either a Google Takeout watch-history.json export,
+// a JSON array of video IDs or URLs, or plain text with one video ID or URL per line
+// where lines starting with # are ignored. URLs of YouTube videos are reduced to
+// their ID, other URLs are matched as is.
+func parseVideoWatchHistory(contents []byte) (map[string]struct{}, error) {
+	watched := make(map[string]struct{})
+
+	add := func(entry string) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			return
+		}
+
+		if id := extractYoutubeVideoID(entry); id != "" {
+			watched[id] = struct{}{}
+			return
+		}
+
+		watched[entry] = struct{}{}
+	}
+
+	contents = bytes.TrimSpace(contents)
+
+	if bytes.HasPrefix(contents, []byte("[")) {
+		var entries []json.RawMessage
+		if err := json.Unmarshal(contents, &entries); err != nil {
+			return nil, fmt.Errorf("parsing watch history: %v", err)
+		}
+
+		for _, raw := range entries {
+			var entry string
+			if err := json.Unmarshal(raw, &entry); err == nil {
+				add(entry)
+				continue
+			}
+
+			var takeoutEntry struct {
+				TitleUrl string `json:"titleUrl"`
+			}
+			if err := json.Unmarshal(raw, &takeoutEntry); err == nil {
+				add(takeoutEntry.TitleUrl)
+			}
+		}
+	} else {
+		for _, line := range strings.Split(string(contents), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+
+			add(line)
+		}
+	}
+
+	if len(contents) > 0 && len(watched) == 0 {
+		return nil, errors.New("watch history doesn't contain any videos")
+	}
+
+	return watched, nil
+}
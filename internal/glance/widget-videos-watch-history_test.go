@@ -0,0 +1,54 @@
+package glance
+
+import (
+	"testing"
+)
+
+func TestParseVideoWatchHistory(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		expected []string
+	}{
+		{
+			name: "takeout export",
+			contents: `[
+				{"header": "YouTube", "title": "Watched a video", "titleUrl": "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+				{"header": "YouTube", "title": "Watched a removed video"}
+			]`,
+			expected: []string{"dQw4w9WgXcQ"},
+		},
+		{
+			name:     "array of ids and urls",
+			contents: `["9bZkp7q19f0", "https://www.youtube.com/watch?v=jNQXAC9IVRw&t=10s", "https://rumble.com/v123-video.html"]`,
+			expected: []string{"9bZkp7q19f0", "jNQXAC9IVRw", "https://rumble.com/v123-video.html"},
+		},
+		{
+			name:     "plain text",
+			contents: "# watched\n9bZkp7q19f0\n\n  https://www.youtube.com/watch?v=jNQXAC9IVRw  \n",
+			expected: []string{"9bZkp7q19f0", "jNQXAC9IVRw"},
+		},
+	}
+
+	for _, test := range tests {
+		watched, err := parseVideoWatchHistory([]byte(test.contents))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+
+		if len(watched) != len(test.expected) {
+			t.Errorf("%s: expected %d videos, got %v", test.name, len(test.expected), watched)
+		}
+
+		for _, id := range test.expected {
+			if _, ok := watched[id]; !ok {
+				t.Errorf("%s: expected %q to be watched", test.name, id)
+			}
+		}
+	}
+
+	if _, err := parseVideoWatchHistory([]byte(`[{"broken": `)); err == nil {
+		t.Error("Expected an error for malformed JSON")
+	}
+}
@@ -0,0 +1,252 @@
+package glance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Unlike watch-history, which comes from outside of Glance, videos can also be marked
+// as watched by clicking on them within the widget. The marked videos are kept by the
+// widget in a store which is either in memory or backed by a file.
+
+// Only the most recently marked videos are remembered so that the store doesn't grow forever
+const videoWatchedStoreMaxSize = 5000
+
+const maxVideoWatchedBodySize = 4 * 1024
+
+type videoWatchedStore interface {
+	isWatched(url string) bool
+	markWatched(url string) error
+}
+
+type videoMarkWatchedField struct {
+	Enabled  bool   `yaml:"enabled"`
+	Store    string `yaml:"store"`
+	SortLast bool   `yaml:"sort-last"`
+
+	store videoWatchedStore `yaml:"-"`
+}
+
+func (f *videoMarkWatchedField) initialize() error {
+	if !f.Enabled {
+		return nil
+	}
+
+	if f.Store == "" {
+		f.store = newMemoryVideoWatchedStore()
+		return nil
+	}
+
+	store, err := fileVideoWatchedStoreFor(f.Store)
+	if err != nil {
+		return fmt.Errorf("mark-watched: %v", err)
+	}
+
+	f.store = store
+	return nil
+}
+
+// isWatched reports whether the video was watched according to either the watch history
+// or the videos marked through the widget
+func (f *videoMarkWatchedField) isWatched(v *video) bool {
+	return v.Watched || (f.store != nil && f.store.isWatched(v.Url))
+}
+
+// apply marks the videos that were marked as watched through the widget and, if
+// enabled, moves them after the ones that haven't been watched yet
+func (f *videoMarkWatchedField) apply(videos videoList) videoList {
+	if f.store == nil {
+		return videos
+	}
+
+	marked := slices.Clone(videos)
+	for i := range marked {
+		marked[i].Watched = f.isWatched(&marked[i])
+	}
+
+	if f.SortLast {
+		slices.SortStableFunc(marked, func(a, b video) int {
+			switch {
+			case a.Watched == b.Watched:
+				return 0
+			case a.Watched:
+				return 1
+			default:
+				return -1
+			}
+		})
+	}
+
+	return marked
+}
+
+// handleWatchedRequest marks the video with the URL in the body of the request as watched
+func (widget *videosWidget) handleWatchedRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if widget.MarkWatched.store == nil {
+		http.Error(w, "marking videos as watched is not enabled", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxVideoWatchedBodySize))
+	if err != nil {
+		http.Error(w, "could not read request body", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Url string `json:"url"`
+	}
+
+	if err := json.Unmarshal(body, &request); err != nil || request.Url == "" {
+		http.Error(w, "request body must contain the url of the video", http.StatusBadRequest)
+		return
+	}
+
+	if err := widget.MarkWatched.store.markWatched(request.Url); err != nil {
+		slog.Error("Failed to mark video as watched", "url", request.Url, "error", err)
+		http.Error(w, "could not mark video as watched", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// memoryVideoWatchedStore keeps the watched videos for as long as Glance is running
+type memoryVideoWatchedStore struct {
+	mu sync.RWMutex
+	// Ordered from the least to the most recently marked
+	urls    []string
+	watched map[string]struct{}
+}
+
+func newMemoryVideoWatchedStore() *memoryVideoWatchedStore {
+	return &memoryVideoWatchedStore{watched: make(map[string]struct{})}
+}
+
+func (s *memoryVideoWatchedStore) isWatched(url string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.watched[url]
+	return ok
+}
+
+func (s *memoryVideoWatchedStore) markWatched(url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(url)
+	return nil
+}
+
+// add must be called with the lock held, reports whether the URL wasn't already marked
+func (s *memoryVideoWatchedStore) add(url string) bool {
+	if _, ok := s.watched[url]; ok {
+		return false
+	}
+
+	s.urls = append(s.urls, url)
+	s.watched[url] = struct{}{}
+
+	if len(s.urls) > videoWatchedStoreMaxSize {
+		delete(s.watched, s.urls[0])
+		s.urls = s.urls[1:]
+	}
+
+	return true
+}
+
+// fileVideoWatchedStore keeps the watched videos in a JSON file with a list of URLs
+type fileVideoWatchedStore struct {
+	*memoryVideoWatchedStore
+	path string
+}
+
+var (
+	videoWatchedFileStoresMu sync.Mutex
+	// Widgets that point to the same file share the same store so that they
+	// don't overwrite each other's changes
+	videoWatchedFileStores = make(map[string]*fileVideoWatchedStore)
+)
+
+func fileVideoWatchedStoreFor(path string) (*fileVideoWatchedStore, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	videoWatchedFileStoresMu.Lock()
+	defer videoWatchedFileStoresMu.Unlock()
+
+	if store, exists := videoWatchedFileStores[path]; exists {
+		return store, nil
+	}
+
+	store, err := newFileVideoWatchedStore(path)
+	if err != nil {
+		return nil, err
+	}
+
+	videoWatchedFileStores[path] = store
+	return store, nil
+}
+
+func newFileVideoWatchedStore(path string) (*fileVideoWatchedStore, error) {
+	store := &fileVideoWatchedStore{
+		memoryVideoWatchedStore: newMemoryVideoWatchedStore(),
+		path:                    path,
+	}
+
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(contents) == 0) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading watched videos file: %v", err)
+	}
+
+	var urls []string
+	if err := json.Unmarshal(contents, &urls); err != nil {
+		return nil, fmt.Errorf("parsing watched videos file: %v", err)
+	}
+
+	for _, url := range urls {
+		store.add(url)
+	}
+
+	return store, nil
+}
+
+func (s *fileVideoWatchedStore) markWatched(url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.add(url) {
+		return nil
+	}
+
+	return s.save()
+}
+
+// save writes the watched videos to the file
+func (s *fileVideoWatchedStore) save() error {
+	contents, err := json.MarshalIndent(s.urls, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.path, contents, 0o600)
+}
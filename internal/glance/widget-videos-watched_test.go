@@ -0,0 +1,90 @@
+package glance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestVideosWidgetMarkWatched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watched.json")
+	widget := newTestVideosWidget(t, "mark-watched:\n  enabled: true\n  store: "+path+"\n  sort-last: true\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	widget.Videos = videoList{{Title: "first", Url: "https://a"}, {Title: "second", Url: "https://b"}, {Title: "third", Url: "https://c"}}
+
+	request := httptest.NewRequest("POST", "/api/widgets/1/watched", strings.NewReader(`{"url":"https://a"}`))
+	request.SetPathValue("path", "watched")
+	recorder := httptest.NewRecorder()
+	widget.handleRequest(recorder, request)
+
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusNoContent, recorder.Code, recorder.Body.String())
+	}
+
+	view := &videosWidgetView{videosWidget: widget}
+	videos := view.Sections()[0].Videos
+
+	titles := make([]string, len(videos))
+	for i := range videos {
+		titles[i] = videos[i].Title
+	}
+
+	if expected := []string{"second", "third", "first"}; !slices.Equal(titles, expected) {
+		t.Fatalf("Expected %v, got %v", expected, titles)
+	}
+
+	if !videos[2].Watched || videos[0].Watched || widget.Videos[0].Watched {
+		t.Errorf("Expected only the rendered copy of the clicked video to be watched, got %+v", videos)
+	}
+
+	reloaded, err := newFileVideoWatchedStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reload store: %v", err)
+	}
+
+	if !reloaded.isWatched("https://a") || reloaded.isWatched("https://b") {
+		t.Error("Expected the watched video to be persisted")
+	}
+}
+
+func TestVideosWidgetHidesWatchedShorts(t *testing.T) {
+	widget := newTestVideosWidget(t, "shorts: hide-watched\nmark-watched:\n  enabled: true\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	if !widget.IncludeShorts {
+		t.Fatal("Expected hide-watched to include shorts")
+	}
+
+	widget.Videos = videoList{
+		{Title: "watched short", Url: "https://youtube.com/shorts/a"},
+		{Title: "short", Url: "https://youtube.com/shorts/b"},
+		{Title: "watched video", Url: "https://youtube.com/watch?v=c"},
+		{Title: "video", Url: "https://youtube.com/watch?v=d"},
+	}
+
+	widget.MarkWatched.store.markWatched("https://youtube.com/shorts/a")
+	widget.MarkWatched.store.markWatched("https://youtube.com/watch?v=c")
+
+	view := &videosWidgetView{videosWidget: widget}
+	videos := view.Sections()[0].Videos
+
+	titles := make([]string, len(videos))
+	for i := range videos {
+		titles[i] = videos[i].Title
+	}
+
+	if expected := []string{"short", "watched video", "video"}; !slices.Equal(titles, expected) {
+		t.Fatalf("Expected %v, got %v", expected, titles)
+	}
+
+	invalid := &videosWidget{}
+	if err := yaml.Unmarshal([]byte("shorts: hide\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]"), invalid); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := invalid.initialize(); err == nil {
+		t.Error("Expected an unknown shorts value to fail")
+	}
+}
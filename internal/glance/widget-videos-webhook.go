@@ -0,0 +1,124 @@
+package glance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// With webhook-url set, the videos that appear in an update are posted to it as JSON,
+// so that new uploads can be forwarded to a notification service. The first update
+// after startup only remembers the videos since they would all be new otherwise.
+
+// How long the URLs of videos are remembered after they were last fetched, so that the
+// videos of sources which failed to be fetched for a while aren't sent again once the
+// sources are back
+const videosWebhookForgetAfter = 7 * 24 * time.Hour
+
+const videosWebhookTimeout = 10 * time.Second
+
+type videosWebhookPayload struct {
+	Widget string      `json:"widget"`
+	Videos []videoJson `json:"videos"`
+}
+
+// newVideosForWebhook returns the videos that haven't been fetched before, and none
+// during the first update. Videos posted before the first update are never returned,
+// since they're from sources which failed to be fetched back then.
+func (widget *videosWidget) newVideosForWebhook(now time.Time, lists ...videoList) []videoJson {
+	first := widget.webhookSeen == nil
+	if first {
+		widget.webhookSeen = make(map[string]time.Time)
+		widget.webhookSince = now
+	}
+
+	videos := make([]videoJson, 0)
+
+	for _, list := range lists {
+		for i := range list {
+			v := &list[i]
+
+			_, seen := widget.webhookSeen[v.Url]
+			widget.webhookSeen[v.Url] = now
+
+			if first || seen || (!v.TimePosted.IsZero() && v.TimePosted.Before(widget.webhookSince)) {
+				continue
+			}
+
+			videos = append(videos, newVideoJson(v))
+		}
+	}
+
+	for videoUrl, lastSeen := range widget.webhookSeen {
+		if now.Sub(lastSeen) > videosWebhookForgetAfter {
+			delete(widget.webhookSeen, videoUrl)
+		}
+	}
+
+	return videos
+}
+
+// notifyNewVideos posts the new videos to the webhook in the background. Failures are
+// only logged and the videos aren't sent again.
+func (widget *videosWidget) notifyNewVideos(lists ...videoList) {
+	if widget.WebhookUrl == "" {
+		return
+	}
+
+	videos := widget.newVideosForWebhook(time.Now(), lists...)
+	if len(videos) == 0 {
+		return
+	}
+
+	payload := videosWebhookPayload{Widget: widget.Title, Videos: videos}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), videosWebhookTimeout)
+		defer cancel()
+
+		if err := postVideosWebhook(ctx, widget.WebhookUrl, payload, widget.httpClient()); err != nil {
+			slog.Warn("Failed to send new videos to the webhook", "videos", len(videos), "error", err)
+			return
+		}
+
+		slog.Debug("Sent new videos to the webhook", "videos", len(videos))
+	}()
+}
+
+// postVideosWebhook posts the payload to the webhook. The URL is left out of the errors
+// since it commonly contains a token.
+func postVideosWebhook(ctx context.Context, webhookUrl string, payload videosWebhookPayload, client requestDoer) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", webhookUrl, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("invalid webhook URL")
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package glance
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVideosWidgetWebhook(t *testing.T) {
+	widget := newTestVideosWidget(t, "webhook-url: https://hooks.example/new\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	started := time.Date(2025, 1, 14, 14, 0, 0, 0, time.UTC)
+
+	initial := videoList{{Title: "Existing", Url: "https://a", TimePosted: started.Add(-time.Hour)}}
+	if videos := widget.newVideosForWebhook(started, initial); len(videos) != 0 {
+		t.Fatalf("Expected nothing to be sent for the first update, got %+v", videos)
+	}
+
+	next := videoList{
+		{Title: "Existing", Url: "https://a", TimePosted: started.Add(-time.Hour)},
+		{Title: "Uploaded", Url: "https://b", TimePosted: started.Add(10 * time.Minute)},
+		// From a source that failed during the first update
+		{Title: "Recovered", Url: "https://c", TimePosted: started.Add(-2 * time.Hour)},
+	}
+
+	videos := widget.newVideosForWebhook(started.Add(15*time.Minute), next)
+	if len(videos) != 1 || videos[0].Title != "Uploaded" {
+		t.Fatalf("Expected only the uploaded video to be sent, got %+v", videos)
+	}
+
+	if videos := widget.newVideosForWebhook(started.Add(30*time.Minute), next); len(videos) != 0 {
+		t.Errorf("Expected videos to only be sent once, got %+v", videos)
+	}
+
+	received := make(chan videosWebhookPayload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/failing" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var payload videosWebhookPayload
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" || json.NewDecoder(r.Body).Decode(&payload) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		received <- payload
+	}))
+	defer server.Close()
+
+	payload := videosWebhookPayload{Widget: widget.Title, Videos: videos}
+	if err := postVideosWebhook(context.Background(), server.URL+"/hook?token=secret", payload, server.Client()); err != nil {
+		t.Fatalf("Failed to post to the webhook: %v", err)
+	}
+
+	if got := <-received; got.Widget != "Videos" || len(got.Videos) != 1 || got.Videos[0].Url != "https://b" {
+		t.Errorf("Unexpected payload: %+v", got)
+	}
+
+	err := postVideosWebhook(context.Background(), server.URL+"/failing?token=secret", payload, server.Client())
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Expected an error without the webhook URL for a failing webhook, got %v", err)
+	}
+
+	invalid := &videosWidget{WebhookUrl: "hooks.example/new"}
+	if err := invalid.initialize(); err == nil {
+		t.Error("Expected an error for a webhook URL without a scheme")
+	}
+}
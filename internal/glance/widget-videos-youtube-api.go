@@ -0,0 +1,367 @@
+package glance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Helpers for the optional YouTube Data API v3 backend of the videos widget. The
+// API key is always sent through a header rather than the query string so that it
+// doesn't end up in logged request URLs.
+
+const youtubeDataAPIBaseURL = "https://www.googleapis.com/youtube/v3"
+
+// The API allows requesting at most 50 IDs per call
+const youtubeDataAPIMaxIDsPerRequest = 50
+
+// Subscriber counts and avatars change slowly, no need to hammer the API for them
+const youtubeChannelInfoCacheDuration = 24 * time.Hour
+
+type youtubeChannelsResponseJson struct {
+	Items []struct {
+		ID      string `json:"id"`
+		Snippet struct {
+			Thumbnails struct {
+				Default struct {
+					Url string `json:"url"`
+				} `json:"default"`
+			} `json:"thumbnails"`
+		} `json:"snippet"`
+		Statistics struct {
+			SubscriberCount       string `json:"subscriberCount"`
+			HiddenSubscriberCount bool   `json:"hiddenSubscriberCount"`
+		} `json:"statistics"`
+	} `json:"items"`
+}
+
+type youtubeChannelInfo struct {
+	// Zero when the channel hides its subscriber count
+	subscribers int
+	avatarUrl   string
+	fetchedAt   time.Time
+}
+
+func newYoutubeDataAPIRequest(ctx context.Context, apiKey string, endpoint string, query url.Values) *http.Request {
+	request, _ := http.NewRequestWithContext(ctx, "GET", youtubeDataAPIBaseURL+"/"+endpoint+"?"+query.Encode(), nil)
+	request.Header.Set("X-Goog-Api-Key", apiKey)
+
+	return request
+}
+
+// chunkStrings splits values into consecutive chunks of at most size elements
+func chunkStrings(values []string, size int) [][]string {
+	chunks := make([][]string, 0, (len(values)+size-1)/size)
+
+	for size < len(values) {
+		values, chunks = values[size:], append(chunks, values[:size])
+	}
+
+	if len(values) > 0 {
+		chunks = append(chunks, values)
+	}
+
+	return chunks
+}
+
+// fetchYoutubeChannelInfo returns the subscriber count and avatar of each of the given channels
+func fetchYoutubeChannelInfo(ctx context.Context, apiKey string, channelIDs []string, client requestDoer) (map[string]youtubeChannelInfo, error) {
+	chunks := chunkStrings(channelIDs, youtubeDataAPIMaxIDsPerRequest)
+	requests := make([]*http.Request, len(chunks))
+
+	for i := range chunks {
+		requests[i] = newYoutubeDataAPIRequest(ctx, apiKey, "channels", url.Values{
+			"part": {"snippet,statistics"},
+			"id":   {strings.Join(chunks[i], ",")},
+		})
+	}
+
+	job := newJob(decodeJsonFromRequestTask[youtubeChannelsResponseJson](client), requests)
+	responses, errs, err := workerPoolDo(job)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make(map[string]youtubeChannelInfo, len(channelIDs))
+	var failed int
+	var lastErr error
+
+	for i := range responses {
+		if errs[i] != nil {
+			failed++
+			lastErr = errs[i]
+			continue
+		}
+
+		for _, item := range responses[i].Items {
+			info := youtubeChannelInfo{avatarUrl: item.Snippet.Thumbnails.Default.Url}
+
+			if !item.Statistics.HiddenSubscriberCount {
+				info.subscribers, _ = strconv.Atoi(item.Statistics.SubscriberCount)
+			}
+
+			channels[item.ID] = info
+		}
+	}
+
+	if failed > 0 {
+		return channels, fmt.Errorf("%w: failed to fetch channel info for %d of %d batches: %v", errPartialContent, failed, len(requests), lastErr)
+	}
+
+	return channels, nil
+}
+
+type youtubeVideoDetailsResponseJson struct {
+	Items []struct {
+		ID      string `json:"id"`
+		Snippet struct {
+			LiveBroadcastContent string `json:"liveBroadcastContent"`
+			DefaultAudioLanguage string `json:"defaultAudioLanguage"`
+			DefaultLanguage      string `json:"defaultLanguage"`
+		} `json:"snippet"`
+		ContentDetails struct {
+			Duration string `json:"duration"`
+		} `json:"contentDetails"`
+		LiveStreamingDetails *struct {
+			ActualStartTime string `json:"actualStartTime"`
+			ActualEndTime   string `json:"actualEndTime"`
+		} `json:"liveStreamingDetails"`
+	} `json:"items"`
+}
+
+type youtubeVideoDetails struct {
+	duration             time.Duration
+	liveBroadcastContent string
+	streamEndedAt        time.Time
+	// Empty when the creator hasn't set it
+	language string
+}
+
+// fetchYoutubeVideoDetails retrieves the details which aren't available in the RSS
+// feeds for the given video IDs
+func fetchYoutubeVideoDetails(ctx context.Context, apiKey string, videoIDs []string, client requestDoer) (map[string]youtubeVideoDetails, error) {
+	chunks := chunkStrings(videoIDs, youtubeDataAPIMaxIDsPerRequest)
+	requests := make([]*http.Request, len(chunks))
+
+	for i := range chunks {
+		requests[i] = newYoutubeDataAPIRequest(ctx, apiKey, "videos", url.Values{
+			"part": {"snippet,contentDetails,liveStreamingDetails"},
+			"id":   {strings.Join(chunks[i], ",")},
+		})
+	}
+
+	job := newJob(decodeJsonFromRequestTask[youtubeVideoDetailsResponseJson](client), requests)
+	responses, errs, err := workerPoolDo(job)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make(map[string]youtubeVideoDetails, len(videoIDs))
+	var failed int
+	var lastErr error
+
+	for i := range responses {
+		if errs[i] != nil {
+			failed++
+			lastErr = errs[i]
+			continue
+		}
+
+		for _, item := range responses[i].Items {
+			d := youtubeVideoDetails{
+				liveBroadcastContent: item.Snippet.LiveBroadcastContent,
+			}
+
+			d.duration, _ = parseISO8601Duration(item.ContentDetails.Duration)
+
+			// The language of the audio is what matters for filtering, the default language
+			// only describes the title and description but is set more often
+			d.language = item.Snippet.DefaultAudioLanguage
+			if d.language == "" {
+				d.language = item.Snippet.DefaultLanguage
+			}
+
+			if item.LiveStreamingDetails != nil && item.LiveStreamingDetails.ActualEndTime != "" {
+				d.streamEndedAt, _ = time.Parse(time.RFC3339, item.LiveStreamingDetails.ActualEndTime)
+			}
+
+			details[item.ID] = d
+		}
+	}
+
+	if failed > 0 {
+		return details, fmt.Errorf("%w: failed to fetch video details for %d of %d batches: %v", errPartialContent, failed, len(requests), lastErr)
+	}
+
+	return details, nil
+}
+
+// parseISO8601Duration parses the subset of ISO 8601 durations used by the YouTube
+// API, e.g. PT1H2M3S or P1DT2H
+func parseISO8601Duration(value string) (time.Duration, error) {
+	rest, ok := strings.CutPrefix(value, "P")
+	if !ok || rest == "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var duration time.Duration
+	inTime := false
+	number := 0
+	hasNumber := false
+
+	for _, c := range rest {
+		switch {
+		case c >= '0' && c <= '9':
+			number = number*10 + int(c-'0')
+			hasNumber = true
+			continue
+		case c == 'T' && !inTime && !hasNumber:
+			inTime = true
+			continue
+		}
+
+		if !hasNumber {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+
+		switch {
+		case c == 'W' && !inTime:
+			duration += time.Duration(number) * 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			duration += time.Duration(number) * 24 * time.Hour
+		case c == 'H' && inTime:
+			duration += time.Duration(number) * time.Hour
+		case c == 'M' && inTime:
+			duration += time.Duration(number) * time.Minute
+		case c == 'S' && inTime:
+			duration += time.Duration(number) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+
+		number = 0
+		hasNumber = false
+	}
+
+	if hasNumber {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	return duration, nil
+}
+
+type youtubePlaylistItemsResponseJson struct {
+	NextPageToken string `json:"nextPageToken"`
+	Items         []struct {
+		Snippet struct {
+			Title                  string `json:"title"`
+			VideoOwnerChannelTitle string `json:"videoOwnerChannelTitle"`
+			VideoOwnerChannelID    string `json:"videoOwnerChannelId"`
+			Thumbnails             struct {
+				High struct {
+					Url string `json:"url"`
+				} `json:"high"`
+			} `json:"thumbnails"`
+			ResourceID struct {
+				VideoID string `json:"videoId"`
+			} `json:"resourceId"`
+		} `json:"snippet"`
+		ContentDetails struct {
+			VideoPublishedAt string `json:"videoPublishedAt"`
+		} `json:"contentDetails"`
+	} `json:"items"`
+}
+
+type youtubePlaylistAPIRequest struct {
+	ctx               context.Context
+	apiKey            string
+	source            videoSourceField
+	maxItems          int
+	videoUrlTemplate  string
+	authorUrlTemplate string
+	thumbnailQuality  string
+	client            requestDoer
+}
+
+func fetchYoutubePlaylistViaAPITask(r youtubePlaylistAPIRequest) (videoList, error) {
+	return fetchYoutubePlaylistViaAPI(r.ctx, r.apiKey, r.source, r.maxItems, r.videoUrlTemplate, r.authorUrlTemplate, r.thumbnailQuality, r.client)
+}
+
+// fetchYoutubePlaylistViaAPI fetches up to maxItems of the latest videos added to a
+// playlist, which unlike its feed isn't limited to the 15 most recent ones
+func fetchYoutubePlaylistViaAPI(ctx context.Context, apiKey string, source videoSourceField, maxItems int, videoUrlTemplate string, authorUrlTemplate string, thumbnailQuality string, client requestDoer) (videoList, error) {
+	playlistID := strings.TrimPrefix(source.ID, videosWidgetPlaylistPrefix)
+	videos := make(videoList, 0, maxItems)
+	var pageToken string
+
+	for fetched := 0; fetched < maxItems; {
+		query := url.Values{
+			"part":       {"snippet,contentDetails"},
+			"playlistId": {playlistID},
+			"maxResults": {strconv.Itoa(min(maxItems-fetched, youtubeDataAPIMaxIDsPerRequest))},
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		response, err := decodeJsonFromRequest[youtubePlaylistItemsResponseJson](client, newYoutubeDataAPIRequest(ctx, apiKey, "playlistItems", query))
+		if err != nil {
+			return nil, err
+		}
+
+		fetched += len(response.Items)
+
+		for i := range response.Items {
+			snippet := &response.Items[i].Snippet
+			videoID := snippet.ResourceID.VideoID
+
+			// Private and deleted videos remain in the playlist but have no owner
+			if videoID == "" || snippet.VideoOwnerChannelID == "" || source.excludesTitle(snippet.Title) {
+				continue
+			}
+
+			timePosted, err := time.Parse(time.RFC3339, response.Items[i].ContentDetails.VideoPublishedAt)
+			if err != nil {
+				continue
+			}
+
+			videoUrl := "https://www.youtube.com/watch?v=" + videoID
+			if videoUrlTemplate != "" {
+				videoUrl = strings.ReplaceAll(videoUrlTemplate, "{VIDEO-ID}", videoID)
+			}
+
+			thumbnailUrl := youtubeThumbnailUrl(videoID, thumbnailQuality)
+			if thumbnailUrl == "" {
+				thumbnailUrl = snippet.Thumbnails.High.Url
+			}
+			if thumbnailUrl == "" {
+				thumbnailUrl = "https://i.ytimg.com/vi/" + videoID + "/hqdefault.jpg"
+			}
+
+			videos = append(videos, video{
+				ThumbnailUrl: thumbnailUrl,
+				Title:        snippet.Title,
+				Url:          videoUrl,
+				Author:       snippet.VideoOwnerChannelTitle,
+				AuthorUrl:    youtubeAuthorUrl(authorUrlTemplate, snippet.VideoOwnerChannelID, "https://www.youtube.com/channel/"+snippet.VideoOwnerChannelID),
+				ChannelID:    snippet.VideoOwnerChannelID,
+				VideoID:      videoID,
+				Source:       videoSourceYoutube,
+				sourceID:     source.ID,
+				TimePosted:   timePosted,
+			})
+		}
+
+		if response.NextPageToken == "" || len(response.Items) == 0 {
+			break
+		}
+
+		pageToken = response.NextPageToken
+	}
+
+	return videos, nil
+}
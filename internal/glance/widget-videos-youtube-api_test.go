@@ -0,0 +1,109 @@
+package glance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestVideosWidgetPlaylistsViaAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/youtube/v3/playlistItems" {
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <title>Feed</title>
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>From feed</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+			return
+		}
+
+		if r.Header.Get("X-Goog-Api-Key") != "key" || r.URL.Query().Get("playlistId") == "broken" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		// Every page has as many items as requested, with the first two pages pointing to the next
+		page, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+		count, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+
+		var nextPageToken string
+		if page < 2 {
+			nextPageToken = strconv.Itoa(page + 1)
+		}
+
+		items := make([]string, count)
+		for i := range items {
+			id := fmt.Sprintf("video-%d-%d", page, i)
+			items[i] = fmt.Sprintf(`{"snippet": {"title": %q, "videoOwnerChannelTitle": "Owner", "videoOwnerChannelId": "UCowner", "resourceId": {"videoId": %q}}, "contentDetails": {"videoPublishedAt": "2025-01-01T00:00:00Z"}}`, id, id)
+		}
+
+		fmt.Fprintf(w, `{"nextPageToken": %q, "items": [%s]}`, nextPageToken, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+limit: 60
+api-key: key
+playlists: [PLFgquLnL59alCl_2TQvOiD5Vgm1hCaGSI, broken]
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+
+	videos := widget.fetchSourceVideos(context.Background(), widget.Sections()[0])
+
+	var fromAPI, fromFeed int
+	for i := range videos {
+		if videos[i].Title == "From feed" {
+			fromFeed++
+		} else if videos[i].Author == "Owner" {
+			fromAPI++
+		}
+	}
+
+	if fromAPI != 60 {
+		t.Errorf("Expected the playlist to be fetched through the API up to the limit, got %d videos", fromAPI)
+	}
+
+	if fromFeed != 1 {
+		t.Errorf("Expected the broken playlist to fall back to its feed, got %d videos from it", fromFeed)
+	}
+
+	if widget.failedSources != 0 || widget.totalSources != 2 {
+		t.Errorf("Expected both playlists to count as fetched, got %d failed of %d", widget.failedSources, widget.totalSources)
+	}
+}
+
+func TestVideosWidgetYoutubeDataAPIUsesProxy(t *testing.T) {
+	var requested atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested.Store(true)
+		w.Write([]byte(`{"items": [{"id": "UCXuqSBlHAE6Xw-yeJA0Tunw", "statistics": {"subscriberCount": "1200"}}]}`))
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+api-key: key
+show-subscribers: true
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+
+	widget.updateChannelInfo(context.Background(), videoList{{ChannelID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}})
+
+	if !requested.Load() {
+		t.Fatal("Expected the YouTube Data API to be requested through the widget's proxy")
+	}
+
+	if _, ok := widget.channelInfo["UCXuqSBlHAE6Xw-yeJA0Tunw"]; !ok {
+		t.Error("Expected the channel info fetched through the proxy to be cached")
+	}
+}
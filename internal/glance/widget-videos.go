@@ -1,25 +1,102 @@
 package glance
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+	"gopkg.in/yaml.v3"
 )
 
 // Constants
 const videosWidgetPlaylistPrefix = "playlist:"
 
+// Values of videosWidget.FutureHandling
+const (
+	videosFutureAsScheduled = "as-scheduled"
+	videosFutureTop         = "top"
+	videosFutureBottom      = "bottom"
+	videosFutureHide        = "hide"
+)
+
+// Values of videosWidget.AuthorLink
+const (
+	videosAuthorLinkVideos  = "videos"
+	videosAuthorLinkChannel = "channel"
+	videosAuthorLinkNone    = "none"
+)
+
+// Values of videosWidget.SortBy
+const (
+	videosSortNewest = "newest"
+	videosSortOldest = "oldest"
+	videosSortTitle  = "title"
+	videosSortAuthor = "author"
+)
+
+// Values of videosWidget.ThumbnailQuality
+const (
+	youtubeThumbnailDefault = "default"
+	youtubeThumbnailHigh    = "high"
+	youtubeThumbnailMax     = "max"
+)
+
+// Number of consecutive updates without any videos after which the widget's caches are
+// cleared and the videos are fetched again from scratch
+const videosDefaultRecoverAfterEmpty = 3
+
+// Shown in place of the videos when none of them are left after filtering
+const videosDefaultEmptyMessage = "No recent videos"
+
+// Number of feeds fetched at the same time, which is never more than the number of feeds
+const (
+	videosDefaultConcurrency = 30
+	videosMaxConcurrency     = 100
+)
+
+// Values of video.Source
+const (
+	videoSourceYoutube  = "youtube"
+	videoSourceRumble   = "rumble"
+	videoSourceVimeo    = "vimeo"
+	videoSourceTwitch   = "twitch"
+	videoSourceOdysee   = "odysee"
+	videoSourceNebula   = "nebula"
+	videoSourceBitchute = "bitchute"
+	videoSourceFeed     = "feed"
+)
+
+var videoSources = []string{videoSourceYoutube, videoSourceRumble, videoSourceVimeo, videoSourceTwitch, videoSourceOdysee, videoSourceNebula, videoSourceBitchute, videoSourceFeed}
+
 // Template variables
 var (
-	videosWidgetTemplate             = mustParseTemplate("videos.html", "widget-base.html", "video-card-contents.html")
-	videosWidgetGridTemplate         = mustParseTemplate("videos-grid.html", "widget-base.html", "video-card-contents.html")
-	videosWidgetVerticalListTemplate = mustParseTemplate("videos-vertical-list.html", "widget-base.html")
+	videosWidgetTemplate               = mustParseTemplate("videos.html", "widget-base.html", "videos-header-status.html", "video-card-contents.html", "videos-next-refresh.html", "videos-next-page.html", "videos-empty.html", "videos-section-title.html")
+	videosWidgetGridTemplate           = mustParseTemplate("videos-grid.html", "widget-base.html", "videos-header-status.html", "video-card-contents.html", "videos-next-refresh.html", "videos-next-page.html", "videos-empty.html", "videos-section-title.html")
+	videosWidgetHorizontalListTemplate = mustParseTemplate("videos-horizontal-list.html", "widget-base.html", "videos-header-status.html", "video-card-contents.html", "videos-next-refresh.html", "videos-next-page.html", "videos-empty.html", "videos-section-title.html")
+	videosWidgetVerticalListTemplate   = mustParseTemplate("videos-vertical-list.html", "widget-base.html", "videos-header-status.html", "video-card-contents.html", "videos-next-refresh.html", "videos-next-page.html", "videos-empty.html", "videos-section-title.html")
+	videosWidgetGroupedListTemplate    = mustParseTemplate("videos-grouped-list.html", "widget-base.html", "videos-header-status.html", "video-card-contents.html", "videos-next-refresh.html", "videos-next-page.html", "videos-empty.html", "videos-section-title.html")
+	videosWidgetTitlesOnlyTemplate     = mustParseTemplate("videos-titles-only.html", "widget-base.html", "videos-header-status.html", "videos-next-refresh.html", "videos-next-page.html", "videos-empty.html", "videos-section-title.html")
+	videosWidgetSnapshotTemplate       = mustParseTemplate("videos-snapshot.html")
 )
 
 // =============================================================================
@@ -29,19 +106,218 @@ var (
 // videosWidget represents the main video widget structure
 type videosWidget struct {
 	widgetBase        `yaml:",inline"`
-	Videos            videoList `yaml:"-"`
-	VideoUrlTemplate  string    `yaml:"video-url-template"`
-	Style             string    `yaml:"style"`
-	CollapseAfter     int       `yaml:"collapse-after"`
-	CollapseAfterRows int       `yaml:"collapse-after-rows"`
-	Channels          []string  `yaml:"channels"`
-	RumbleChannels    []string  `yaml:"rumble-channels"`
-	Playlists         []string  `yaml:"playlists"`
-	Limit             int       `yaml:"limit"`
-	IncludeShorts     bool      `yaml:"include-shorts"`
-	
+	Videos            videoList              `yaml:"-"`
+	VideoUrlTemplate  string                 `yaml:"video-url-template"`
+	AuthorUrlTemplate string                 `yaml:"author-url-template"`
+	Style             string                 `yaml:"style"`
+	CollapseAfter     int                    `yaml:"collapse-after"`      // Videos shown before collapsing, list styles only
+	CollapseAfterRows int                    `yaml:"collapse-after-rows"` // Rows shown before collapsing, grid-cards only
+	Channels          []videoSourceField     `yaml:"channels"`
+	RumbleChannels    []videoSourceField     `yaml:"rumble-channels"`
+	RumbleFeedBase    string                 `yaml:"rumble-feed-base"`
+	VimeoChannels     []videoSourceField     `yaml:"vimeo-channels"`
+	TwitchChannels    []videoSourceField     `yaml:"twitch-channels"`
+	OdyseeChannels    []videoSourceField     `yaml:"odysee-channels"`
+	NebulaChannels    []videoSourceField     `yaml:"nebula-channels"`
+	BitchuteChannels  []videoSourceField     `yaml:"bitchute-channels"`
+	Feeds             []videoSourceField     `yaml:"feeds"`
+	Playlists         []videoSourceField     `yaml:"playlists"`
+	Groups            []videosWidgetGroup    `yaml:"groups"`
+	Limit             int                    `yaml:"limit"`
+	DisplayLimit      int                    `yaml:"display-limit"`
+	PageSize          int                    `yaml:"page-size"`
+	LimitPerChannel   int                    `yaml:"limit-per-channel"`
+	MinPerSource      int                    `yaml:"min-per-source"`
+	MinPerChannel     int                    `yaml:"min-per-channel"`
+	SourceWeights     map[string]int         `yaml:"source-weights"`
+	IncludeShorts     bool                   `yaml:"include-shorts"`
+	Shorts            string                 `yaml:"shorts"`
+	SortBy            string                 `yaml:"sort-by"`
+	SortExpression    string                 `yaml:"sort-expression"`
+	ChannelBoosts     map[string]float64     `yaml:"channel-boosts"`
+	APIKey            string                 `yaml:"api-key"`
+	TwitchClientID    string                 `yaml:"twitch-client-id"`
+	TwitchToken       string                 `yaml:"twitch-token"`
+	NebulaToken       string                 `yaml:"nebula-token"`
+	YoutubeHeaders    map[string]string      `yaml:"youtube-headers"`
+	UserAgent         string                 `yaml:"user-agent"`
+	ShowSubscribers   bool                   `yaml:"show-subscribers"`
+	ShowViews         bool                   `yaml:"show-views"`
+	ShowAvatars       bool                   `yaml:"show-avatars"`
+	HidePastStreams   bool                   `yaml:"hide-past-streams"`
+	RecentLiveBoost   durationField          `yaml:"recent-live-boost"`
+	NormalizeTitles   bool                   `yaml:"normalize-titles"`
+	StripTitleEmoji   bool                   `yaml:"strip-title-emoji"`
+	ShowNextRefresh   bool                   `yaml:"show-next-refresh"`
+	ShowCounts        bool                   `yaml:"show-counts"`
+	ShowRefreshButton bool                   `yaml:"show-refresh-button"`
+	Proxy             proxyOptionsField      `yaml:"proxy"`
+	HighlightNew      bool                   `yaml:"highlight-new"`
+	ShowNewBadge      bool                   `yaml:"show-new-badge"`
+	FutureHandling    string                 `yaml:"future-handling"`
+	AuthorLink        string                 `yaml:"author-link"`
+	PrioritizeLive    bool                   `yaml:"prioritize-live"`
+	PinnedChannels    []string               `yaml:"pinned-channels"`
+	ThumbnailQuality  string                 `yaml:"thumbnail-quality"`
+	WatchHistory      videoWatchHistoryField `yaml:"watch-history"`
+	MarkWatched       videoMarkWatchedField  `yaml:"mark-watched"`
+	ShortsDetection   videoShortsField       `yaml:"shorts-detection"`
+	// Pointers to know whether a value was provided since deduplication is enabled by
+	// default, deduplicate-videos is the older name of the option
+	DeduplicateRaw       *bool         `yaml:"deduplicate"`
+	DeduplicateVideosRaw *bool         `yaml:"deduplicate-videos"`
+	Deduplicate          bool          `yaml:"-"`
+	IncludeCommunity     bool          `yaml:"include-community"`
+	CommunityFeedUrl     string        `yaml:"community-feed-url"`
+	FetchDeadline        durationField `yaml:"fetch-deadline"`
+	LanguageInclude      []string      `yaml:"language-include"`
+	LanguageExclude      []string      `yaml:"language-exclude"`
+	ShowLanguage         bool          `yaml:"show-language"`
+	RecoverAfterEmpty    int           `yaml:"recover-after-empty"`
+	MaxRetries           int           `yaml:"max-retries"`
+	RequestTimeout       durationField `yaml:"request-timeout"`
+	ExcludeKeywords      []string      `yaml:"exclude-keywords"`
+	IncludeKeywords      []string      `yaml:"include-keywords"`
+	ExcludeCategories    []string      `yaml:"exclude-categories"`
+	IncludeCategories    []string      `yaml:"include-categories"`
+	ShowCategories       bool          `yaml:"show-categories"`
+	MaxAge               durationField `yaml:"max-age"`
+	MinViews             int           `yaml:"min-views"`
+	CacheFile            string        `yaml:"cache-file"`
+	ChannelsOPML         string        `yaml:"channels-opml"`
+	Concurrency          int           `yaml:"concurrency"`
+	RateLimit            float64       `yaml:"rate-limit"`
+	YoutubeBackend       string        `yaml:"youtube-backend"`
+	YoutubeBackendUrl    string        `yaml:"youtube-backend-url"`
+	YoutubeFallback      bool          `yaml:"youtube-backend-fallback"`
+	Timezone             string        `yaml:"timezone"`
+	PlaceholderThumbnail string        `yaml:"placeholder-thumbnail"`
+	ThumbnailPreload     int           `yaml:"thumbnail-preload"`
+	ValidateThumbnails   bool          `yaml:"validate-thumbnails"`
+	ShareFeedCache       bool          `yaml:"share-feed-cache"`
+	Metrics              bool          `yaml:"metrics"`
+	Diagnostics          bool          `yaml:"diagnostics"`
+	EmptyMessage         string        `yaml:"empty-message"`
+	WebhookUrl           string        `yaml:"webhook-url"`
+	Mode                 string        `yaml:"mode"`
+	PrefetchRaw          *bool         `yaml:"prefetch"`
+	Prefetch             bool          `yaml:"-"`
+	LastFetchedAt        time.Time     `yaml:"-"`
+
+	channelInfo  map[string]youtubeChannelInfo  `yaml:"-"`
+	videoDetails map[string]youtubeVideoDetails `yaml:"-"`
+	previousUrls map[string]struct{}            `yaml:"-"`
+	// Channel IDs of the channels specified through their handle, keyed by the lowercase handle
+	resolvedHandles map[string]string `yaml:"-"`
+	emptyFetches    int               `yaml:"-"`
+	// Consecutive updates that failed to fetch any videos, for backing off between them
+	failedUpdates int `yaml:"-"`
+	// When each video was last fetched and when the first update happened, for telling
+	// which videos are new to the webhook
+	webhookSeen  map[string]time.Time `yaml:"-"`
+	webhookSince time.Time            `yaml:"-"`
+	// Posting time of the newest watched video of each channel, for mode catch-up
+	catchUpMarks map[string]time.Time `yaml:"-"`
+	// Thumbnails that were found to exist with validate-thumbnails
+	validThumbnails map[string]struct{} `yaml:"-"`
+	// Closed once the update started along with the server is done, nil without one
+	prefetchDone chan struct{} `yaml:"-"`
+	// How many of the sources failed to be fetched during the last update, guarded by
+	// sourcesMu since the different kinds of sources are fetched at the same time
+	sourcesMu     sync.Mutex `yaml:"-"`
+	failedSources int        `yaml:"-"`
+	totalSources  int        `yaml:"-"`
+	// Same as above but only updated once an update completes, for showing in the header
+	shownSources int `yaml:"-"`
+	shownOf      int `yaml:"-"`
+	// Summary of the videos for show-counts, updated along with them
+	counts videosCounts `yaml:"-"`
+	// Set when a source rejected its credentials during the last update, also guarded by sourcesMu
+	authError error `yaml:"-"`
+	// Which of the sources failed and why, also guarded by sourcesMu
+	sourceFailures []videoSourceFailure `yaml:"-"`
+	// Same as above but only updated along with the videos, guarded by mu
+	lastSourceFailures []videoSourceFailure `yaml:"-"`
+	// The last response of each feed, reused when the feed didn't change since
+	conditionalCache *videoConditionalCache `yaml:"-"`
+	// Only set with rate-limit, kept between updates along with any pause a site asked for
+	rateLimiter *videoRateLimiter `yaml:"-"`
+
+	sortExpression sortExpression `yaml:"-"`
+	location       *time.Location `yaml:"-"`
+	youtubeHeaders http.Header    `yaml:"-"`
+	// Only set when metrics is enabled
+	metrics videosMetrics `yaml:"-"`
+
 	// Add flag to track if this is the first load
-	isFirstLoad       bool      `yaml:"-"`
+	isFirstLoad bool `yaml:"-"`
+
+	// Guards Videos for requests that are served outside of the page lock
+	mu sync.RWMutex `yaml:"-"`
+}
+
+// videoSourceField is a single channel or playlist entry, which can either be
+// specified as a plain string or as an object with additional options
+type videoSourceField struct {
+	ID           string            `yaml:"id"`
+	Name         string            `yaml:"name"`
+	Proxy        proxyOptionsField `yaml:"proxy"`
+	TitleExclude string            `yaml:"title-exclude"`
+	Limit        int               `yaml:"limit"`
+
+	titleExclude *regexp.Regexp `yaml:"-"`
+}
+
+// isVideoSourceOptionKey reports whether the key looks like the name of an option, such
+// keys are decoded as options rather than taken for the ID of a source with a display
+// name so that misspelled options still get reported
+func isVideoSourceOptionKey(key string) bool {
+	return strings.Trim(key, "abcdefghijklmnopqrstuvwxyz-") == ""
+}
+
+func (s *videoSourceField) UnmarshalYAML(node *yaml.Node) error {
+	type videoSourceFieldAlias videoSourceField
+
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&s.ID)
+	}
+
+	// The shorthand for a source with a display name, as in "UCXuqSBlHAE6Xw-yeJA0Tunw: Some Name"
+	if node.Kind == yaml.MappingNode && len(node.Content) == 2 && node.Content[1].Kind == yaml.ScalarNode &&
+		!isVideoSourceOptionKey(node.Content[0].Value) {
+		s.ID, s.Name = node.Content[0].Value, strings.TrimSpace(node.Content[1].Value)
+		return nil
+	}
+
+	if err := node.Decode((*videoSourceFieldAlias)(s)); err != nil {
+		return err
+	}
+
+	if s.ID == "" {
+		return fmt.Errorf("line %d: source is missing an id", node.Line)
+	}
+
+	return nil
+}
+
+// videosWidgetGroup is a named set of sources which gets its own titled section within
+// the widget. Groups share the widget's fetch and display settings but keep separate lists.
+type videosWidgetGroup struct {
+	Title            string             `yaml:"title"`
+	Channels         []videoSourceField `yaml:"channels"`
+	RumbleChannels   []videoSourceField `yaml:"rumble-channels"`
+	VimeoChannels    []videoSourceField `yaml:"vimeo-channels"`
+	TwitchChannels   []videoSourceField `yaml:"twitch-channels"`
+	OdyseeChannels   []videoSourceField `yaml:"odysee-channels"`
+	NebulaChannels   []videoSourceField `yaml:"nebula-channels"`
+	BitchuteChannels []videoSourceField `yaml:"bitchute-channels"`
+	Feeds            []videoSourceField `yaml:"feeds"`
+	Playlists        []videoSourceField `yaml:"playlists"`
+	Videos           videoList          `yaml:"-"`
+	// The query of the request for the section's next page, empty when there isn't one
+	NextPage string `yaml:"-"`
+	// Only set with shorts: separate, in which case Videos doesn't include them
+	Shorts videoList `yaml:"-"`
 }
 
 // video represents a single video entry
@@ -51,7 +327,151 @@ type video struct {
 	Url          string
 	Author       string
 	AuthorUrl    string
+	ChannelID    string
+	VideoID      string
+	Source       string
 	TimePosted   time.Time
+	// Zero when the source doesn't provide them
+	Views         int
+	Duration      time.Duration
+	StreamEndedAt time.Time
+	// Whether the video is a livestream that's currently ongoing
+	IsLive bool
+	// Whether the video wasn't present in the previous fetch
+	IsNew bool
+	// Whether the video is in the watch history, only set when dimming watched videos
+	Watched bool
+	// Community posts are shown as cards without a playable URL and possibly no thumbnail
+	IsCommunityPost bool
+	// Language code as set by the creator, only set when show-language is enabled
+	Language string
+	// Categories or keywords from the feed, empty when the source doesn't provide them
+	Categories []string
+	// Only set when show-avatars is enabled, a placeholder when the avatar isn't known
+	AuthorAvatarUrl string
+	// Only set for sources whose feeds include them, zero otherwise
+	ThumbnailWidth  int
+	ThumbnailHeight int
+	// Only set while rendering when the widget has a timezone
+	timezone *time.Location
+	// Only set while rendering for the first videos up to thumbnail-preload
+	preloadThumbnail bool
+	// Only set while rendering when show-views is enabled
+	showViews bool
+	// Only set while rendering when show-new-badge is enabled
+	newBadge bool
+	// Only set while rendering when show-categories is enabled
+	showCategories bool
+	// Set once the videos are fetched, see videoShortsField.hasVerticalThumbnail
+	verticalThumbnail bool
+	// The ID of the configured source the video was fetched from, only set while fetching
+	sourceID string
+}
+
+// FormattedDuration returns the duration in the same format as YouTube, e.g. 4:05 or 1:02:03
+func (v video) FormattedDuration() string {
+	seconds := int(v.Duration.Round(time.Second).Seconds())
+	hours, minutes := seconds/3600, seconds/60%60
+
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds%60)
+	}
+
+	return fmt.Sprintf("%d:%02d", minutes, seconds%60)
+}
+
+// IsScheduled reports whether the video is a premiere or stream that hasn't started yet
+func (v video) IsScheduled() bool {
+	return v.TimePosted.After(time.Now())
+}
+
+// ShowsViews reports whether the view count should be shown, which is only when it's known
+func (v video) ShowsViews() bool {
+	return v.showViews && v.Views > 0
+}
+
+// ShowsNewBadge reports whether the video gets a badge which the browser reveals when
+// the video was posted after the widget was last viewed, which needs a known post time
+func (v video) ShowsNewBadge() bool {
+	return v.newBadge && !v.TimePosted.IsZero()
+}
+
+// ShownCategories returns the first few categories of the video when show-categories
+// is enabled, so that it's visible why the video matched include-categories
+func (v video) ShownCategories() []string {
+	if !v.showCategories {
+		return nil
+	}
+
+	return v.Categories[:min(len(v.Categories), videoMaxShownCategories)]
+}
+
+// VerticalThumbnail reports whether the thumbnail is shown in a frame for vertical
+// images, which keeps the thumbnails of shorts from being cropped to a thin strip
+func (v video) VerticalThumbnail() bool {
+	return v.verticalThumbnail
+}
+
+// ThumbnailLoading returns the loading attribute of the thumbnail. Browsers that don't
+// support lazy loading ignore it and load every thumbnail right away.
+func (v video) ThumbnailLoading() string {
+	if v.preloadThumbnail {
+		return "eager"
+	}
+
+	return "lazy"
+}
+
+// isVideoThumbnailUrlAllowed reports whether the URL can be used as the source of a
+// thumbnail, which excludes schemes such as javascript:
+func isVideoThumbnailUrlAllowed(thumbnailUrl string) bool {
+	lower := strings.ToLower(thumbnailUrl)
+
+	return strings.HasPrefix(lower, "http://") ||
+		strings.HasPrefix(lower, "https://") ||
+		strings.HasPrefix(lower, "data:image/") ||
+		strings.HasPrefix(lower, "/")
+}
+
+// validateVideoUrlTemplate checks that the template contains the ID of the video, since
+// every video would get the same link otherwise, and that filling it in results in an
+// http(s) URL or a path
+func validateVideoUrlTemplate(videoUrlTemplate string) error {
+	if !strings.Contains(videoUrlTemplate, "{VIDEO-ID}") {
+		return errors.New("must contain the {VIDEO-ID} placeholder")
+	}
+
+	parsed, err := url.Parse(strings.ReplaceAll(videoUrlTemplate, "{VIDEO-ID}", "jNQXAC9IVRw"))
+	if err != nil {
+		return fmt.Errorf("is not a valid URL: %v", err)
+	}
+
+	isHttp := (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+	isPath := parsed.Scheme == "" && parsed.Host == "" && strings.HasPrefix(parsed.Path, "/")
+	if !isHttp && !isPath {
+		return errors.New("must be an http(s) URL or a path")
+	}
+
+	return nil
+}
+
+// ThumbnailSrc returns the thumbnail URL for use as the source of an image. Templates
+// replace data URIs with a harmless value unless they're marked as safe, which is only
+// done for images.
+func (v video) ThumbnailSrc() any {
+	if strings.HasPrefix(strings.ToLower(v.ThumbnailUrl), "data:image/") {
+		return template.URL(v.ThumbnailUrl)
+	}
+
+	return v.ThumbnailUrl
+}
+
+// videoChannelGroup holds the videos of a single channel for the grouped-list style
+type videoChannelGroup struct {
+	Author      string
+	AuthorUrl   string
+	Subscribers int
+	Videos      videoList
 }
 
 // videoList represents a collection of videos
@@ -65,6 +485,10 @@ type rumbleVideo struct {
 	Author       string
 	AuthorUrl    string
 	TimePosted   time.Time
+	Duration     time.Duration
+	Categories   []string
+
+	sourceID string
 }
 
 // rumbleVideoList represents a collection of Rumble videos
@@ -74,22 +498,37 @@ type rumbleVideoList []rumbleVideo
 type youtubeFeedResponseXml struct {
 	Channel     string `xml:"author>name"`
 	ChannelLink string `xml:"author>uri"`
+	ChannelID   string `xml:"http://www.youtube.com/xml/schemas/2015 channelId"`
 	Videos      []struct {
 		Title     string `xml:"title"`
 		Published string `xml:"published"`
+		VideoID   string `xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
 		Link      struct {
 			Href string `xml:"href,attr"`
 		} `xml:"link"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
 
 		Group struct {
 			Thumbnail struct {
 				Url string `xml:"url,attr"`
 			} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+			Content struct {
+				Duration string `xml:"duration,attr"`
+			} `xml:"http://search.yahoo.com/mrss/ content"`
+			Keywords  string `xml:"http://search.yahoo.com/mrss/ keywords"`
+			Community struct {
+				Statistics struct {
+					Views string `xml:"views,attr"`
+				} `xml:"http://search.yahoo.com/mrss/ statistics"`
+			} `xml:"http://search.yahoo.com/mrss/ community"`
 		} `xml:"http://search.yahoo.com/mrss/ group"`
 	} `xml:"entry"`
 }
 
-// Rumble API response structures
+// Rumble API response structures, matching both Rumble's own feeds and the ones
+// generated by the bridge
 type rumbleFeedResponseXml struct {
 	Channel     string `xml:"channel>title"`
 	ChannelLink string `xml:"channel>link"`
@@ -97,331 +536,3095 @@ type rumbleFeedResponseXml struct {
 		Title     string `xml:"title"`
 		Published string `xml:"pubDate"`
 		Link      string `xml:"guid"`
+		ItemLink  string `xml:"link"`
+		Enclosure struct {
+			Url  string `xml:"url,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"enclosure"`
 		Thumbnail struct {
 			Url string `xml:"url,attr"`
 		} `xml:"itunes:image"`
 		MediaThumbnail struct {
 			Url string `xml:"url,attr"`
 		} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+		MediaContent struct {
+			Duration string `xml:"duration,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ content"`
+		ItunesDuration string   `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+		Categories     []string `xml:"category"`
 	} `xml:"channel>item"`
 }
 
-// =============================================================================
-// VIDEOS WIDGET METHODS
-// =============================================================================
+// =============================================================================
+// VIDEOS WIDGET METHODS
+// =============================================================================
+
+// initialize sets up the videos widget with default values
+func (widget *videosWidget) initialize() error {
+	// Set initial cache duration - will be extended after first successful fetch
+	widget.withTitle("Videos").withCacheDuration(1 * time.Minute)
+	widget.conditionalCache = newVideoConditionalCache()
+
+	if widget.Metrics {
+		widget.metrics = newVideoFetchMetrics()
+	}
+
+	if widget.Limit <= 0 {
+		widget.Limit = 25
+	}
+
+	// Only what gets rendered is capped, the JSON endpoint still has every video
+	if widget.DisplayLimit <= 0 || widget.DisplayLimit > widget.Limit {
+		widget.DisplayLimit = widget.Limit
+	}
+
+	// Users can still switch to the grid through the widget's preferences, so the
+	// option is kept, it just doesn't do anything until then
+	if widget.CollapseAfterRows != 0 && widget.Style != "" && widget.Style != "grid-cards" {
+		slog.Warn("collapse-after-rows only applies to the grid-cards style, use collapse-after for other styles", "style", widget.Style)
+	}
+
+	if widget.CollapseAfterRows == 0 || widget.CollapseAfterRows < -1 {
+		widget.CollapseAfterRows = 4
+	}
+
+	if widget.CollapseAfter == 0 || widget.CollapseAfter < -1 {
+		widget.CollapseAfter = 7
+	}
+
+	if widget.MaxRetries == 0 || widget.MaxRetries < -1 {
+		widget.MaxRetries = videosDefaultMaxRetries
+	}
+
+	if widget.RequestTimeout <= 0 {
+		widget.RequestTimeout = durationField(videosDefaultRequestTimeout)
+	}
+
+	if widget.FetchDeadline <= 0 {
+		widget.FetchDeadline = durationField(videosDefaultFetchDeadline)
+	}
+
+	if widget.RecoverAfterEmpty == 0 || widget.RecoverAfterEmpty < -1 {
+		widget.RecoverAfterEmpty = videosDefaultRecoverAfterEmpty
+	}
+
+	widget.Concurrency = clampVideosConcurrency(widget.Concurrency)
+
+	if widget.RateLimit < 0 {
+		return errors.New("rate-limit must be a positive number of requests per second")
+	}
+
+	if widget.RateLimit > 0 {
+		widget.rateLimiter = newVideoRateLimiter(widget.RateLimit)
+	}
+
+	widget.VideoUrlTemplate = strings.TrimSpace(widget.VideoUrlTemplate)
+	if widget.VideoUrlTemplate != "" {
+		if err := validateVideoUrlTemplate(widget.VideoUrlTemplate); err != nil {
+			return fmt.Errorf("video-url-template %v", err)
+		}
+	}
+
+	// Enabled unless turned off since it only changes when the first fetch happens
+	widget.Prefetch = widget.PrefetchRaw == nil || *widget.PrefetchRaw
+
+	if widget.EmptyMessage == "" {
+		widget.EmptyMessage = videosDefaultEmptyMessage
+	}
+
+	widget.PlaceholderThumbnail = strings.TrimSpace(widget.PlaceholderThumbnail)
+	if widget.PlaceholderThumbnail != "" && !isVideoThumbnailUrlAllowed(widget.PlaceholderThumbnail) {
+		return fmt.Errorf("placeholder-thumbnail must be an http(s) URL, a path or an image data URI")
+	}
+
+	if widget.Timezone != "" {
+		location, err := time.LoadLocation(widget.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone '%s': %v", widget.Timezone, err)
+		}
+
+		widget.location = location
+	}
+
+	widget.UserAgent = strings.TrimSpace(widget.UserAgent)
+	if strings.ContainsAny(widget.UserAgent, "\r\n\x00") {
+		return errors.New("user-agent contains a line break")
+	}
+
+	youtubeHeaders, err := parseVideoRequestHeaders(widget.YoutubeHeaders)
+	if err != nil {
+		return fmt.Errorf("youtube-headers: %v", err)
+	}
+
+	if youtubeHeaders != nil {
+		widget.youtubeHeaders = youtubeHeaders
+		slog.Debug("Sending custom headers with YouTube feed requests", "headers", redactedHeaderNames(youtubeHeaders))
+	}
+
+	if widget.ChannelsOPML != "" {
+		if len(widget.Groups) > 0 {
+			return errors.New("channels-opml can't be used together with groups")
+		}
+
+		contents, err := loadChannelsOPML(widget.ChannelsOPML, widget.httpClient())
+		if err != nil {
+			return fmt.Errorf("channels-opml: %v", err)
+		}
+
+		channels, err := parseChannelsOPML(contents)
+		if err != nil {
+			return fmt.Errorf("channels-opml: parsing %s: %v", widget.ChannelsOPML, err)
+		}
+
+		slog.Info("Loaded channels from OPML file", "path", widget.ChannelsOPML, "channel_count", len(channels))
+		widget.Channels = append(widget.Channels, channels...)
+	}
+
+	if len(widget.Groups) > 0 {
+		if len(widget.Channels) > 0 || len(widget.RumbleChannels) > 0 || len(widget.VimeoChannels) > 0 || len(widget.TwitchChannels) > 0 || len(widget.OdyseeChannels) > 0 || len(widget.NebulaChannels) > 0 || len(widget.BitchuteChannels) > 0 || len(widget.Feeds) > 0 || len(widget.Playlists) > 0 {
+			return errors.New("channels, rumble-channels, vimeo-channels, twitch-channels, odysee-channels, nebula-channels, bitchute-channels, feeds and playlists must be specified within each group when using groups")
+		}
+
+		for i := range widget.Groups {
+			group := &widget.Groups[i]
+			if group.Title == "" {
+				return fmt.Errorf("group %d is missing a title", i+1)
+			}
+
+			var err error
+			group.Channels, group.RumbleChannels, err = prepareVideoSources(group.Channels, group.Playlists, group.RumbleChannels)
+			if err != nil {
+				return fmt.Errorf("group %s: %v", group.Title, err)
+			}
+
+			group.VimeoChannels, err = prepareVideoSourceList(group.VimeoChannels, videoSourceVimeo)
+			if err != nil {
+				return fmt.Errorf("group %s: %v", group.Title, err)
+			}
+
+			group.TwitchChannels, err = prepareVideoSourceList(group.TwitchChannels, videoSourceTwitch)
+			if err != nil {
+				return fmt.Errorf("group %s: %v", group.Title, err)
+			}
+
+			group.OdyseeChannels, err = prepareVideoSourceList(group.OdyseeChannels, videoSourceOdysee)
+			if err != nil {
+				return fmt.Errorf("group %s: %v", group.Title, err)
+			}
+
+			group.NebulaChannels, err = prepareVideoSourceList(group.NebulaChannels, videoSourceNebula)
+			if err != nil {
+				return fmt.Errorf("group %s: %v", group.Title, err)
+			}
+
+			group.BitchuteChannels, err = prepareVideoSourceList(group.BitchuteChannels, videoSourceBitchute)
+			if err != nil {
+				return fmt.Errorf("group %s: %v", group.Title, err)
+			}
+
+			group.Feeds, err = prepareVideoSourceList(group.Feeds, videoSourceFeed)
+			if err != nil {
+				return fmt.Errorf("group %s: %v", group.Title, err)
+			}
+		}
+	} else {
+		var err error
+		widget.Channels, widget.RumbleChannels, err = prepareVideoSources(widget.Channels, widget.Playlists, widget.RumbleChannels)
+		if err != nil {
+			return err
+		}
+
+		widget.VimeoChannels, err = prepareVideoSourceList(widget.VimeoChannels, videoSourceVimeo)
+		if err != nil {
+			return err
+		}
+
+		widget.TwitchChannels, err = prepareVideoSourceList(widget.TwitchChannels, videoSourceTwitch)
+		if err != nil {
+			return err
+		}
+
+		widget.OdyseeChannels, err = prepareVideoSourceList(widget.OdyseeChannels, videoSourceOdysee)
+		if err != nil {
+			return err
+		}
+
+		widget.NebulaChannels, err = prepareVideoSourceList(widget.NebulaChannels, videoSourceNebula)
+		if err != nil {
+			return err
+		}
+
+		widget.BitchuteChannels, err = prepareVideoSourceList(widget.BitchuteChannels, videoSourceBitchute)
+		if err != nil {
+			return err
+		}
+
+		widget.Feeds, err = prepareVideoSourceList(widget.Feeds, videoSourceFeed)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, section := range widget.Sections() {
+		if len(section.TwitchChannels) > 0 && (widget.TwitchClientID == "" || widget.TwitchToken == "") {
+			return errors.New("twitch-client-id and twitch-token are required when using twitch-channels")
+		}
+
+		if len(section.NebulaChannels) > 0 && widget.NebulaToken == "" {
+			return errors.New("nebula-token is required when using nebula-channels")
+		}
+	}
+
+	switch {
+	case widget.DeduplicateRaw != nil:
+		widget.Deduplicate = *widget.DeduplicateRaw
+	case widget.DeduplicateVideosRaw != nil:
+		widget.Deduplicate = *widget.DeduplicateVideosRaw
+	default:
+		widget.Deduplicate = true
+	}
+
+	switch widget.FutureHandling {
+	case "":
+		widget.FutureHandling = videosFutureAsScheduled
+	case videosFutureAsScheduled, videosFutureTop, videosFutureBottom, videosFutureHide:
+	default:
+		return fmt.Errorf("future-handling must be one of %s, %s, %s or %s", videosFutureAsScheduled, videosFutureTop, videosFutureBottom, videosFutureHide)
+	}
+
+	switch widget.AuthorLink {
+	case "":
+		widget.AuthorLink = videosAuthorLinkVideos
+	case videosAuthorLinkVideos, videosAuthorLinkChannel, videosAuthorLinkNone:
+	default:
+		return fmt.Errorf("author-link must be one of %s, %s or %s", videosAuthorLinkVideos, videosAuthorLinkChannel, videosAuthorLinkNone)
+	}
+
+	switch widget.YoutubeBackend {
+	case "":
+		widget.YoutubeBackend = youtubeBackendRss
+	case youtubeBackendRss:
+	case youtubeBackendInvidious, youtubeBackendPiped:
+		widget.YoutubeBackendUrl = strings.TrimRight(strings.TrimSpace(widget.YoutubeBackendUrl), "/")
+		if !strings.HasPrefix(widget.YoutubeBackendUrl, "http://") && !strings.HasPrefix(widget.YoutubeBackendUrl, "https://") {
+			return fmt.Errorf("youtube-backend %s requires a youtube-backend-url starting with http:// or https://", widget.YoutubeBackend)
+		}
+	default:
+		return fmt.Errorf("youtube-backend must be one of %s, %s or %s", youtubeBackendRss, youtubeBackendInvidious, youtubeBackendPiped)
+	}
+
+	switch widget.SortBy {
+	case "":
+		widget.SortBy = videosSortNewest
+	case videosSortNewest, videosSortOldest, videosSortTitle, videosSortAuthor:
+	default:
+		return fmt.Errorf("sort-by must be one of %s, %s, %s or %s", videosSortNewest, videosSortOldest, videosSortTitle, videosSortAuthor)
+	}
+
+	switch widget.ThumbnailQuality {
+	case "":
+		widget.ThumbnailQuality = youtubeThumbnailDefault
+	case youtubeThumbnailDefault, youtubeThumbnailHigh, youtubeThumbnailMax:
+	default:
+		return fmt.Errorf("thumbnail-quality must be one of %s, %s or %s", youtubeThumbnailDefault, youtubeThumbnailHigh, youtubeThumbnailMax)
+	}
+
+	if widget.IncludeCommunity && !strings.Contains(widget.CommunityFeedUrl, "{CHANNEL-ID}") {
+		return errors.New("include-community requires a community-feed-url containing {CHANNEL-ID}")
+	}
+
+	widget.WebhookUrl = strings.TrimSpace(widget.WebhookUrl)
+	if widget.WebhookUrl != "" && !strings.HasPrefix(widget.WebhookUrl, "http://") && !strings.HasPrefix(widget.WebhookUrl, "https://") {
+		return errors.New("webhook-url must start with http:// or https://")
+	}
+
+	if widget.RumbleFeedBase != "" {
+		if !strings.HasPrefix(widget.RumbleFeedBase, "http://") && !strings.HasPrefix(widget.RumbleFeedBase, "https://") {
+			return errors.New("rumble-feed-base must start with http:// or https://")
+		}
+
+		// The channel name gets appended to the base
+		if !strings.HasSuffix(widget.RumbleFeedBase, "/") {
+			widget.RumbleFeedBase += "/"
+		}
+	}
+
+	for i := range widget.ExcludeKeywords {
+		widget.ExcludeKeywords[i] = strings.ToLower(widget.ExcludeKeywords[i])
+	}
+
+	for i := range widget.IncludeKeywords {
+		widget.IncludeKeywords[i] = strings.ToLower(widget.IncludeKeywords[i])
+	}
+
+	for i := range widget.ExcludeCategories {
+		widget.ExcludeCategories[i] = strings.ToLower(strings.TrimSpace(widget.ExcludeCategories[i]))
+	}
+
+	for i := range widget.IncludeCategories {
+		widget.IncludeCategories[i] = strings.ToLower(strings.TrimSpace(widget.IncludeCategories[i]))
+	}
+
+	for i := range widget.LanguageInclude {
+		widget.LanguageInclude[i] = strings.ToLower(strings.TrimSpace(widget.LanguageInclude[i]))
+	}
+
+	for i := range widget.LanguageExclude {
+		widget.LanguageExclude[i] = strings.ToLower(strings.TrimSpace(widget.LanguageExclude[i]))
+	}
+
+	if widget.APIKey == "" && (len(widget.LanguageInclude) > 0 || len(widget.LanguageExclude) > 0 || widget.ShowLanguage) {
+		slog.Warn("Video languages are only available through the YouTube Data API, language options have no effect without an api-key")
+	}
+
+	for source, weight := range widget.SourceWeights {
+		if !slices.Contains(videoSources, source) {
+			return fmt.Errorf("source-weights: unknown source %s, must be one of %s", source, strings.Join(videoSources, ", "))
+		}
+
+		if weight < 1 {
+			return fmt.Errorf("source-weights: weight of %s must be at least 1", source)
+		}
+	}
+
+	widget.ShortsDetection.initialize()
+
+	if err := widget.initializeShorts(); err != nil {
+		return err
+	}
+
+	if err := widget.MarkWatched.initialize(); err != nil {
+		return err
+	}
+
+	if err := widget.WatchHistory.initialize(); err != nil {
+		return err
+	}
+
+	switch widget.Mode {
+	case "":
+	case videosModeCatchUp:
+		if !widget.MarkWatched.Enabled && widget.WatchHistory.Source == "" {
+			return errors.New("mode catch-up requires either mark-watched or watch-history")
+		}
+	default:
+		return fmt.Errorf("mode must be %s when set", videosModeCatchUp)
+	}
+
+	if widget.SortExpression != "" {
+		expr, err := parseSortExpression(widget.SortExpression)
+		if err != nil {
+			slog.Warn("Invalid videos sort-expression, falling back to newest", "expression", widget.SortExpression, "error", err)
+		} else {
+			widget.sortExpression = expr
+		}
+	}
+
+	// Mark as first load and set ContentAvailable to false initially
+	widget.isFirstLoad = true
+	widget.ContentAvailable = false
+
+	// Force immediate update by setting nextUpdate to now
+	widget.nextUpdate = time.Now()
+
+	// Show the videos from before a restart until they're fetched again
+	widget.loadDiskCache()
+
+	return nil
+}
+
+// update handles the widget update cycle with progressive caching
+func (widget *videosWidget) update(ctx context.Context) {
+	// The page waits for the prefetch started along with the server and gets its videos
+	if widget.waitForPrefetch() {
+		return
+	}
+
+	widget.updateVideos(ctx)
+}
+
+// updateVideos fetches the videos, swaps them in and schedules the next update depending
+// on whether fetching them succeeded
+func (widget *videosWidget) updateVideos(ctx context.Context) {
+	// On first load, use shorter cache duration for faster initial display
+	if widget.isFirstLoad {
+		slog.Debug("Video widget first load - fetching videos immediately")
+		widget.withCacheDuration(5 * time.Minute) // Shorter cache on first load
+		widget.isFirstLoad = false
+	} else {
+		// Normal updates use longer cache duration
+		widget.withCacheDuration(30 * time.Minute)
+	}
+
+	// Requests that are still in flight when the deadline passes are aborted through the
+	// context and their sources counted as failed
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(widget.FetchDeadline))
+	defer cancel()
+
+	lists := widget.fetchVideos(ctx)
+
+	allVideos := make(videoList, 0)
+	for i := range lists {
+		allVideos = append(allVideos, lists[i]...)
+	}
+
+	slog.Debug("Video widget update complete", "total_videos", len(allVideos))
+
+	// Log the first few videos to see what data we have
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		for i, v := range allVideos {
+			if i >= 3 { // Only log first 3 videos
+				break
+			}
+			slog.Debug("Video data", "index", i, "title", v.Title, "author", v.Author, "thumbnail", v.ThumbnailUrl, "url", v.Url, "time", v.TimePosted)
+		}
+	}
+
+	var notice error
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		slog.Warn("Videos fetch deadline exceeded, showing partial results", "deadline", time.Duration(widget.FetchDeadline))
+		notice = fmt.Errorf(
+			"%w: failed to fetch videos from %d of %d sources, those that didn't respond within %s were skipped",
+			errPartialContent, widget.failedSources, widget.totalSources, time.Duration(widget.FetchDeadline),
+		)
+	case widget.authError != nil:
+		notice = fmt.Errorf("%w: %v", errPartialContent, widget.authError)
+	case widget.failedSources > 0:
+		notice = fmt.Errorf("%w: failed to fetch videos from %d of %d sources", errPartialContent, widget.failedSources, widget.totalSources)
+	}
+
+	var fetchErr error
+	if len(allVideos) == 0 && widget.failedSources > 0 {
+		// Rather than an empty widget or one that looks like it's still loading, show
+		// that fetching failed and try again sooner than usual
+		slog.Error("Failed to fetch any videos", "failed_sources", widget.failedSources, "total_sources", widget.totalSources)
+		if widget.authError != nil {
+			fetchErr = fmt.Errorf("failed to fetch videos from %d of %d sources: %v", widget.failedSources, widget.totalSources, widget.authError)
+		} else {
+			fetchErr = fmt.Errorf("failed to fetch videos from %d of %d sources", widget.failedSources, widget.totalSources)
+		}
+
+		// Sources that are down get requested less and less often with every update in
+		// a row that fails, rather than every few minutes until they're back
+		widget.failedUpdates++
+		widget.withCacheDuration(videosFailureBackoff(widget.failedUpdates))
+	} else {
+		widget.failedUpdates = 0
+	}
+
+	recovered := widget.recoverFromEmptyFetches(len(allVideos))
+
+	// Everything the API handlers check is swapped in at once, since they only hold mu
+	widget.mu.Lock()
+	widget.Videos = allVideos
+	widget.LastFetchedAt = time.Now()
+	for i := range widget.Groups {
+		widget.Groups[i].Videos = lists[i]
+	}
+	widget.counts = countVideos(allVideos)
+	widget.lastSourceFailures = widget.sourceFailures
+	widget.shownSources, widget.shownOf = widget.totalSources-widget.failedSources, widget.totalSources
+	widget.withNotice(notice)
+	widget.withError(fetchErr)
+	// After a successful fetch content is available, even when none of the videos
+	// matched the filters, which gets shown as the empty-message
+	widget.ContentAvailable = fetchErr == nil
+	widget.scheduleNextUpdate()
+	if recovered {
+		// Refetch on the next request rather than waiting for the cache to expire
+		widget.nextUpdate = time.Now()
+	}
+	widget.mu.Unlock()
+
+	logSourceFailures(widget.lastSourceFailures)
+	if fetchErr == nil {
+		slog.Debug("Videos fetched successfully", "count", len(allVideos))
+	}
+
+	if len(allVideos) > 0 {
+		widget.saveDiskCache(lists)
+	}
+}
+
+// recoverFromEmptyFetches keeps track of consecutive updates that returned no videos
+// despite the widget having sources and once there have been enough of them, clears
+// everything that's carried over between updates so that the next one starts from a
+// clean state. Reports whether the caches were cleared.
+func (widget *videosWidget) recoverFromEmptyFetches(fetched int) bool {
+	if fetched > 0 || !widget.hasSources() {
+		widget.emptyFetches = 0
+		return false
+	}
+
+	widget.emptyFetches++
+
+	if widget.RecoverAfterEmpty < 0 || widget.emptyFetches < widget.RecoverAfterEmpty {
+		return false
+	}
+
+	slog.Warn("Videos widget returned no videos for several consecutive updates, clearing caches and fetching again",
+		"title", widget.Title,
+		"updates", widget.emptyFetches,
+	)
+
+	widget.emptyFetches = 0
+	widget.videoDetails = nil
+	widget.channelInfo = nil
+	widget.WatchHistory.invalidate()
+	widget.conditionalCache.clear()
+
+	// Don't reuse connections that may have been left in a bad state
+	for _, client := range []requestDoer{widget.httpClient(), defaultHTTPClient} {
+		if c, ok := client.(interface{ CloseIdleConnections() }); ok {
+			c.CloseIdleConnections()
+		}
+	}
+
+	return true
+}
+
+// clampVideosConcurrency returns the default concurrency when it isn't set and
+// otherwise keeps it between 1 and videosMaxConcurrency. It isn't capped at the number
+// of feeds here, the worker pool never starts more workers than it has feeds to fetch.
+func clampVideosConcurrency(concurrency int) int {
+	if concurrency == 0 {
+		return videosDefaultConcurrency
+	}
+
+	return min(max(concurrency, 1), videosMaxConcurrency)
+}
+
+// hasSources reports whether any of the widget's sections has channels to fetch
+func (widget *videosWidget) hasSources() bool {
+	for _, section := range widget.Sections() {
+		if len(section.Channels) > 0 || len(section.RumbleChannels) > 0 || len(section.VimeoChannels) > 0 || len(section.TwitchChannels) > 0 || len(section.OdyseeChannels) > 0 || len(section.NebulaChannels) > 0 || len(section.BitchuteChannels) > 0 || len(section.Feeds) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetchVideos fetches and arranges the videos of every section, which the caller then
+// swaps in. When groups are used, each group's videos are fetched with the same client
+// and arranged independently of one another. Requests still in flight when ctx is done
+// fail, leaving only the videos that were already fetched.
+func (widget *videosWidget) fetchVideos(ctx context.Context) []videoList {
+	sections := widget.Sections()
+	lists := make([]videoList, len(sections))
+
+	widget.failedSources, widget.totalSources = 0, 0
+	widget.authError = nil
+	widget.sourceFailures = nil
+	widget.WatchHistory.refresh(ctx, widget.retryOptions().wrap(widget.httpClient()))
+
+	for i := range sections {
+		lists[i] = widget.fetchSourceVideos(ctx, sections[i])
+		widget.setPlaceholderThumbnails(lists[i])
+		widget.setAuthorLinks(lists[i])
+
+		if widget.Mode == videosModeCatchUp {
+			lists[i] = widget.catchUp(lists[i])
+		}
+
+		lists[i] = widget.WatchHistory.apply(lists[i])
+
+		if len(widget.ExcludeKeywords) > 0 || len(widget.IncludeKeywords) > 0 {
+			lists[i] = lists[i].filter(widget.titleMatchesKeywords)
+		}
+
+		if len(widget.ExcludeCategories) > 0 || len(widget.IncludeCategories) > 0 {
+			lists[i] = lists[i].filter(widget.categoriesAllowed)
+		}
+
+		if !widget.IncludeShorts {
+			lists[i] = lists[i].filter(widget.isNotShort)
+		}
+
+		if widget.MinViews > 0 {
+			lists[i] = lists[i].filter(widget.hasMinViews)
+		}
+
+		if widget.MaxAge > 0 {
+			lists[i] = lists[i].postedAfter(time.Now().Add(-time.Duration(widget.MaxAge)))
+		}
+
+		if widget.Deduplicate {
+			// Keeps the newest occurrence of each video
+			lists[i].sortByNewest()
+			lists[i] = lists[i].deduplicate()
+		}
+
+		if limits := widget.sourceLimits(sections[i]); len(limits) > 0 {
+			// Keeps the newest videos of each source, the widget's limit still applies after
+			lists[i].sortByNewest()
+			lists[i] = lists[i].limitPerSource(limits)
+		}
+	}
+
+	if widget.needsVideoDetails() {
+		widget.updateVideoDetails(ctx, lists...)
+
+		// The durations of YouTube videos are only known once their details are fetched
+		if !widget.IncludeShorts {
+			for i := range lists {
+				lists[i] = lists[i].filter(widget.isNotShort)
+			}
+		}
+
+		if len(widget.LanguageInclude) > 0 || len(widget.LanguageExclude) > 0 {
+			for i := range lists {
+				lists[i] = lists[i].filter(widget.languageAllowed)
+			}
+		}
+	}
+
+	for i := range lists {
+		lists[i] = widget.arrangeVideos(lists[i])
+	}
+
+	if widget.ValidateThumbnails {
+		widget.validateThumbnails(ctx, lists...)
+	}
+
+	widget.setThumbnailOrientations(lists...)
+
+	widget.markNewVideos(lists...)
+	widget.notifyNewVideos(lists...)
+
+	if (widget.ShowSubscribers || widget.ShowAvatars) && widget.APIKey != "" {
+		widget.updateChannelInfo(ctx, lists...)
+	}
+
+	if widget.ShowAvatars {
+		widget.setAuthorAvatars(lists...)
+	}
+
+	return lists
+}
+
+// fetchSourceVideos fetches the videos of all sources of the section
+func (widget *videosWidget) fetchSourceVideos(ctx context.Context, section videosWidgetGroup) videoList {
+	channels, rumbleChannels := widget.resolveHandles(ctx, section.Channels), section.RumbleChannels
+
+	slog.Debug("Video widget update",
+		"channels", videoSourceIDs(channels),
+		"rumble_channels", videoSourceIDs(rumbleChannels),
+		"vimeo_channels", videoSourceIDs(section.VimeoChannels),
+		"twitch_channels", videoSourceIDs(section.TwitchChannels),
+		"odysee_channels", videoSourceIDs(section.OdyseeChannels),
+		"nebula_channels", videoSourceIDs(section.NebulaChannels),
+		"bitchute_channels", videoSourceIDs(section.BitchuteChannels),
+		"feeds", videoSourceIDs(section.Feeds),
+	)
+
+	// Every kind of source is fetched at the same time. The videos of each are kept in
+	// the order of the sources below rather than the order in which they finish, so that
+	// deduplication and the order of videos posted at the same time stay the same.
+	var wg sync.WaitGroup
+	var results []*videoList
+
+	fetch := func(source string, fetcher func() videoList) {
+		result := new(videoList)
+		results = append(results, result)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			started := time.Now()
+			*result = fetcher()
+
+			if widget.metrics != nil {
+				widget.metrics.observeFetch(source, time.Since(started), len(*result))
+			}
+		}()
+	}
+
+	// Fetch YouTube videos, with playlists fetched through the API and channels through
+	// an instance when possible so that they aren't limited to the latest 15 videos of
+	// their feeds
+	if len(channels) > 0 {
+		fetch(videoSourceYoutube, func() videoList {
+			var videos videoList
+			feedChannels := channels
+			if widget.APIKey != "" {
+				videos, feedChannels = widget.fetchYoutubePlaylistsViaAPI(ctx, channels)
+			}
+
+			if widget.YoutubeBackend != youtubeBackendRss {
+				var backendVideos videoList
+				backendVideos, feedChannels = widget.fetchYoutubeChannelsViaBackend(ctx, feedChannels)
+				videos = append(videos, backendVideos...)
+			}
+
+			if len(feedChannels) == 0 {
+				return videos
+			}
+
+			youtubeVideos, err := fetchYoutubeChannelUploads(ctx, feedChannels, widget.VideoUrlTemplate, widget.AuthorUrlTemplate, widget.IncludeShorts, widget.ThumbnailQuality, widget.youtubeHeaders, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			widget.recordSourceFailures(videoSourceYoutube, err, len(feedChannels))
+			// Partial results still contain the videos of the channels that were fetched
+			if err != nil && !errors.Is(err, errPartialContent) {
+				slog.Error("Failed to fetch YouTube videos", "error", err)
+				return videos
+			}
+
+			slog.Debug("Successfully fetched YouTube videos", "count", len(youtubeVideos))
+			return append(videos, youtubeVideos...)
+		})
+	}
+
+	// Fetch Rumble videos
+	if len(rumbleChannels) > 0 {
+		fetch(videoSourceRumble, func() videoList {
+			rumbleVideos, err := fetchRumbleChannelUploads(ctx, rumbleChannels, widget.VideoUrlTemplate, rumbleBridges(widget.RumbleFeedBase), widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			widget.recordSourceFailures(videoSourceRumble, err, len(rumbleChannels))
+			if err != nil && !errors.Is(err, errPartialContent) {
+				slog.Error("Failed to fetch Rumble videos", "error", err)
+				return nil
+			}
+
+			slog.Debug("Successfully fetched Rumble videos", "count", len(rumbleVideos))
+			// Convert rumbleVideoList to videoList
+			videos := make(videoList, 0, len(rumbleVideos))
+			for _, rv := range rumbleVideos {
+				videos = append(videos, video{
+					ThumbnailUrl: rv.ThumbnailUrl,
+					Title:        rv.Title,
+					Url:          rv.Url,
+					Author:       rv.Author,
+					AuthorUrl:    rv.AuthorUrl,
+					Source:       videoSourceRumble,
+					sourceID:     rv.sourceID,
+					TimePosted:   rv.TimePosted,
+					Duration:     rv.Duration,
+					Categories:   rv.Categories,
+				})
+			}
+
+			return videos
+		})
+	}
+
+	// Fetch Vimeo videos
+	if len(section.VimeoChannels) > 0 {
+		fetch(videoSourceVimeo, func() videoList {
+			vimeoVideos, err := fetchVimeoChannelUploads(ctx, section.VimeoChannels, widget.VideoUrlTemplate, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			widget.recordSourceFailures(videoSourceVimeo, err, len(section.VimeoChannels))
+			if err != nil && !errors.Is(err, errPartialContent) {
+				slog.Error("Failed to fetch Vimeo videos", "error", err)
+				return nil
+			}
+
+			slog.Debug("Successfully fetched Vimeo videos", "count", len(vimeoVideos))
+			return vimeoVideos
+		})
+	}
+
+	// Fetch Twitch videos
+	if len(section.TwitchChannels) > 0 {
+		fetch(videoSourceTwitch, func() videoList {
+			twitchVideos, err := fetchTwitchChannelVideos(ctx, section.TwitchChannels, widget.TwitchClientID, widget.TwitchToken, widget.Limit, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			widget.recordSourceFailures(videoSourceTwitch, err, len(section.TwitchChannels))
+			if err != nil && !errors.Is(err, errPartialContent) {
+				slog.Error("Failed to fetch Twitch videos", "error", err)
+				return nil
+			}
+
+			slog.Debug("Successfully fetched Twitch videos", "count", len(twitchVideos))
+			return twitchVideos
+		})
+	}
+
+	// Fetch Odysee videos
+	if len(section.OdyseeChannels) > 0 {
+		fetch(videoSourceOdysee, func() videoList {
+			odyseeVideos, err := fetchOdyseeChannelUploads(ctx, section.OdyseeChannels, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			widget.recordSourceFailures(videoSourceOdysee, err, len(section.OdyseeChannels))
+			if err != nil && !errors.Is(err, errPartialContent) {
+				slog.Error("Failed to fetch Odysee videos", "error", err)
+				return nil
+			}
+
+			slog.Debug("Successfully fetched Odysee videos", "count", len(odyseeVideos))
+			return odyseeVideos
+		})
+	}
+
+	// Fetch Nebula videos
+	if len(section.NebulaChannels) > 0 {
+		fetch(videoSourceNebula, func() videoList {
+			nebulaVideos, err := fetchNebulaUploads(ctx, section.NebulaChannels, widget.NebulaToken, widget.Limit, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			widget.recordSourceFailures(videoSourceNebula, err, len(section.NebulaChannels))
+			if err != nil && !errors.Is(err, errPartialContent) {
+				slog.Error("Failed to fetch Nebula videos", "error", err)
+				return nil
+			}
+
+			slog.Debug("Successfully fetched Nebula videos", "count", len(nebulaVideos))
+			return nebulaVideos
+		})
+	}
+
+	// Fetch BitChute videos
+	if len(section.BitchuteChannels) > 0 {
+		fetch(videoSourceBitchute, func() videoList {
+			bitchuteVideos, err := fetchBitchuteChannelUploads(ctx, section.BitchuteChannels, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			widget.recordSourceFailures(videoSourceBitchute, err, len(section.BitchuteChannels))
+			if err != nil && !errors.Is(err, errPartialContent) {
+				slog.Error("Failed to fetch BitChute videos", "error", err)
+				return nil
+			}
+
+			slog.Debug("Successfully fetched BitChute videos", "count", len(bitchuteVideos))
+			return bitchuteVideos
+		})
+	}
+
+	// Fetch videos from the generic feeds
+	if len(section.Feeds) > 0 {
+		fetch(videoSourceFeed, func() videoList {
+			feedVideos, err := fetchGenericVideoFeeds(ctx, section.Feeds, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			widget.recordSourceFailures(videoSourceFeed, err, len(section.Feeds))
+			if err != nil && !errors.Is(err, errPartialContent) {
+				slog.Error("Failed to fetch videos from feeds", "error", err)
+				return nil
+			}
+
+			slog.Debug("Successfully fetched videos from feeds", "count", len(feedVideos))
+			return feedVideos
+		})
+	}
+
+	if widget.IncludeCommunity && len(channels) > 0 {
+		fetch(videosMetricsCommunitySource, func() videoList {
+			return fetchCommunityPosts(ctx, widget.CommunityFeedUrl, channels, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+		})
+	}
+
+	wg.Wait()
+
+	var allVideos videoList
+	for _, result := range results {
+		allVideos = append(allVideos, *result...)
+	}
+
+	if widget.NormalizeTitles || widget.StripTitleEmoji {
+		for i := range allVideos {
+			allVideos[i].Title = normalizeVideoTitle(allVideos[i].Title, widget.NormalizeTitles, widget.StripTitleEmoji)
+		}
+	}
+
+	return allVideos
+}
+
+// fetchYoutubePlaylistsViaAPI fetches the videos of the playlists among the sources
+// through the YouTube Data API. Returns the sources that are left to be fetched from
+// their feeds, which are the channels and the playlists that failed to be fetched.
+func (widget *videosWidget) fetchYoutubePlaylistsViaAPI(ctx context.Context, sources []videoSourceField) (videoList, []videoSourceField) {
+	remaining := make([]videoSourceField, 0, len(sources))
+	requests := make([]youtubePlaylistAPIRequest, 0)
+
+	for i := range sources {
+		if !strings.HasPrefix(sources[i].ID, videosWidgetPlaylistPrefix) {
+			remaining = append(remaining, sources[i])
+			continue
+		}
+
+		requests = append(requests, youtubePlaylistAPIRequest{
+			ctx:               ctx,
+			apiKey:            widget.APIKey,
+			source:            sources[i],
+			maxItems:          widget.Limit,
+			videoUrlTemplate:  widget.VideoUrlTemplate,
+			authorUrlTemplate: widget.AuthorUrlTemplate,
+			thumbnailQuality:  widget.ThumbnailQuality,
+			client:            widget.retryOptions().wrap(sources[i].clientFor(widget.httpClient())),
+		})
+	}
+
+	if len(requests) == 0 {
+		return nil, remaining
+	}
+
+	job := newJob(fetchYoutubePlaylistViaAPITask, requests).withWorkers(min(10, widget.Concurrency))
+	results, errs, err := workerPoolDo(job)
+	if err != nil {
+		slog.Error("Failed to fetch YouTube playlists through the API, falling back to their feeds", "error", err)
+		for i := range requests {
+			remaining = append(remaining, requests[i].source)
+		}
+
+		return nil, remaining
+	}
+
+	var videos videoList
+	var fetched int
+
+	for i := range results {
+		if errs[i] != nil {
+			slog.Warn("Failed to fetch YouTube playlist through the API, falling back to its feed", "playlist", requests[i].source.ID, "error", errs[i])
+			remaining = append(remaining, requests[i].source)
+			continue
+		}
+
+		fetched++
+		videos = append(videos, results[i]...)
+	}
+
+	widget.recordSourceFailures(videoSourceYoutube, nil, fetched)
+
+	return videos, remaining
+}
+
+// recordSourceFailures counts how many of the sources of the given kind passed to one
+// of the fetchers failed to be fetched based on the error it returned, and keeps track
+// of rejected credentials. Safe to call from the fetchers running at the same time.
+func (widget *videosWidget) recordSourceFailures(kind string, err error, sources int) {
+	widget.sourcesMu.Lock()
+	defer widget.sourcesMu.Unlock()
+
+	for _, authErr := range []error{errTwitchUnauthorized, errNebulaUnauthorized} {
+		if errors.Is(err, authErr) && widget.authError == nil {
+			widget.authError = authErr
+		}
+	}
+
+	failed := 0
+
+	var failures *videoSourceFailures
+	switch {
+	case err == nil || err == errNoContent:
+		// Every source was fetched, even if none of them had videos
+	case errors.As(err, &failures):
+		failed = len(failures.failed)
+		for _, sourceErr := range failures.failed {
+			widget.sourceFailures = append(widget.sourceFailures, newVideoSourceFailure(kind, sourceErr.source, sourceErr.err))
+		}
+	default:
+		failed = sources
+		widget.sourceFailures = append(widget.sourceFailures, newVideoSourceFailure(kind, "", err))
+	}
+
+	widget.totalSources += sources
+	widget.failedSources += failed
+
+	if widget.metrics != nil {
+		widget.metrics.observeSources(kind, sources-failed, failed)
+	}
+}
+
+// arrangeVideos sorts, filters and limits the videos according to the widget's settings
+func (widget *videosWidget) arrangeVideos(videos videoList) videoList {
+	switch {
+	case widget.sortExpression != nil:
+		videos.sortByExpression(widget.sortExpression, widget.ChannelBoosts)
+	case widget.SortBy == videosSortOldest:
+		videos.sortByOldest()
+	case widget.SortBy == videosSortTitle:
+		videos.sortByTitle()
+	case widget.SortBy == videosSortAuthor:
+		videos.sortByAuthor()
+	default:
+		videos.sortByNewest()
+	}
+
+	if widget.RecentLiveBoost > 0 {
+		videos.boostRecentlyEnded(time.Duration(widget.RecentLiveBoost), time.Now())
+	}
+
+	videos = videos.placeScheduled(widget.FutureHandling, time.Now())
+
+	if widget.HidePastStreams {
+		videos = videos.filter(func(v *video) bool {
+			return v.StreamEndedAt.IsZero()
+		})
+	}
+
+	if widget.PrioritizeLive {
+		videos = videos.prioritizeLive()
+	}
+
+	if len(widget.PinnedChannels) > 0 {
+		videos = videos.moveToFront(widget.isPinned)
+	}
+
+	if widget.LimitPerChannel > 0 {
+		videos = videos.limitPerAuthor(widget.LimitPerChannel)
+	}
+
+	// Apply limit
+	reservations := make([]videoReservation, 0, 2)
+	if widget.MinPerSource > 0 {
+		reservations = append(reservations, videoReservation{min: widget.MinPerSource, key: videoSourceKey})
+	}
+	if widget.MinPerChannel > 0 {
+		reservations = append(reservations, videoReservation{min: widget.MinPerChannel, key: videoAuthorKey})
+	}
+
+	// Without weights the remaining slots go to the newest videos
+	var fillOrder []int
+	if len(widget.SourceWeights) > 0 {
+		fillOrder = videos.weightedSourceOrder(widget.SourceWeights)
+	}
+
+	videos = videos.limitWithReservations(widget.Limit, fillOrder, reservations...)
+
+	return videos
+}
+
+// Sections returns the groups of the widget, or a single untitled group holding
+// all of the widget's sources and videos when it doesn't use groups
+func (widget *videosWidget) Sections() []videosWidgetGroup {
+	if len(widget.Groups) > 0 {
+		return widget.Groups
+	}
+
+	return []videosWidgetGroup{{
+		Channels:         widget.Channels,
+		RumbleChannels:   widget.RumbleChannels,
+		VimeoChannels:    widget.VimeoChannels,
+		TwitchChannels:   widget.TwitchChannels,
+		OdyseeChannels:   widget.OdyseeChannels,
+		NebulaChannels:   widget.NebulaChannels,
+		BitchuteChannels: widget.BitchuteChannels,
+		Feeds:            widget.Feeds,
+		Videos:           widget.Videos,
+	}}
+}
+
+// Gives the update some time to complete before the loading placeholder asks for
+// the widget again
+const videosWidgetLoadingRefreshBuffer = 2 * time.Second
+
+// loadingRefreshDelay returns how long the loading placeholder should wait before
+// requesting the widget again, which is until its next update is due
+func (widget *videosWidget) loadingRefreshDelay() time.Duration {
+	return max(time.Until(widget.nextUpdate), 0) + videosWidgetLoadingRefreshBuffer
+}
+
+// IsContentAvailable reports whether the widget is ready to be shown, which also
+// includes it having failed to fetch any videos so that pages don't wait for it
+func (widget *videosWidget) IsContentAvailable() bool {
+	return widget.ContentAvailable || widget.Error != nil
+}
+
+func (widget *videosWidget) HasRefreshButton() bool {
+	return widget.ShowRefreshButton
+}
+
+func (widget *videosWidget) lastUpdatedAt() time.Time {
+	return widget.LastFetchedAt
+}
+
+// Render generates the HTML output for the videos widget
+func (widget *videosWidget) Render() template.HTML {
+	return widget.renderWithPreferences(widgetPreferences{})
+}
+
+// videosWidgetView is the data the templates get rendered with, which allows
+// adjusting what gets displayed without modifying the widget
+type videosWidgetView struct {
+	*videosWidget
+	// Only the one used by the rendered style is set, the other one is -1
+	CollapseAfter     int
+	CollapseAfterRows int
+	hiddenChannels    []string
+	style             string
+	// Set when only a single page of a single section is rendered
+	page *videosPage
+}
+
+// Sections returns the widget's sections without the videos of hidden channels and up
+// to display-limit videos each, or only the rendered page of them with page-size, with
+// the videos that were marked as watched through the widget marked as such and with the
+// videos having the widget's timezone and whether their thumbnail is preloaded
+func (view *videosWidgetView) Sections() []videosWidgetGroup {
+	sections := view.videosWidget.Sections()
+	if len(view.hiddenChannels) == 0 && view.MarkWatched.store == nil && view.Shorts == "" &&
+		view.location == nil && view.ThumbnailPreload <= 0 && !view.ShowViews && !view.ShowNewBadge &&
+		!view.ShowCategories && view.PageSize <= 0 &&
+		(view.DisplayLimit <= 0 || view.DisplayLimit >= view.Limit) {
+		return sections
+	}
+
+	preload := view.ThumbnailPreload
+	offset := 0
+	if view.page != nil {
+		// Only the first page preloads its thumbnails
+		sections = sections[view.page.section : view.page.section+1]
+		preload, offset = 0, view.page.offset
+	}
+
+	filtered := make([]videosWidgetGroup, len(sections))
+	for i := range sections {
+		filtered[i] = sections[i]
+		filtered[i].Videos = sections[i].Videos.filter(func(v *video) bool {
+			for _, channel := range view.hiddenChannels {
+				if v.isFromChannel(channel) {
+					return false
+				}
+			}
+
+			return !view.hidesWatchedShort(v)
+		})
+
+		// The shorts are left out of the pages after the first one, which only have
+		// room for the other videos
+		if view.Shorts == videosShortsSeparate {
+			filtered[i].Videos, filtered[i].Shorts = view.separateShorts(filtered[i].Videos)
+			if view.page != nil {
+				filtered[i].Shorts = nil
+			}
+		}
+
+		if view.DisplayLimit > 0 && len(filtered[i].Videos) > view.DisplayLimit {
+			filtered[i].Videos = filtered[i].Videos[:view.DisplayLimit]
+		}
+
+		if view.DisplayLimit > 0 && len(filtered[i].Shorts) > view.DisplayLimit {
+			filtered[i].Shorts = filtered[i].Shorts[:view.DisplayLimit]
+		}
+
+		if view.PageSize > 0 {
+			section := i
+			if view.page != nil {
+				section = view.page.section
+			}
+
+			filtered[i].Videos, filtered[i].NextPage = view.paginate(section, filtered[i].Videos, offset)
+		}
+
+		filtered[i].Videos = view.MarkWatched.apply(filtered[i].Videos)
+		filtered[i].Shorts = view.MarkWatched.apply(filtered[i].Shorts)
+
+		for _, videos := range []videoList{filtered[i].Videos, filtered[i].Shorts} {
+			for j := range videos {
+				videos[j].timezone = view.location
+				videos[j].preloadThumbnail = preload > 0
+				videos[j].showViews = view.ShowViews
+				videos[j].newBadge = view.ShowNewBadge
+				videos[j].showCategories = view.ShowCategories
+				preload--
+			}
+		}
+	}
+
+	return filtered
+}
+
+var videosWidgetStyles = []string{"horizontal-cards", "horizontal-list", "grid-cards", "vertical-list", "grouped-list", "titles-only"}
+
+func (widget *videosWidget) validatePreferences(prefs *widgetPreferences) error {
+	if prefs.Style != "" && !slices.Contains(videosWidgetStyles, prefs.Style) {
+		return fmt.Errorf("style must be one of %s", strings.Join(videosWidgetStyles, ", "))
+	}
+
+	return nil
+}
+
+// renderWithPreferences renders the widget with the display preferences of a user
+// applied on top of the widget's own settings
+func (widget *videosWidget) renderWithPreferences(prefs widgetPreferences) template.HTML {
+	slog.Debug("Rendering video widget", "style", widget.Style, "video_count", len(widget.Videos), "content_available", widget.ContentAvailable)
+
+	// If content is not available yet, show a loading message which requests the
+	// widget again once its next update is due. When the last update failed the
+	// error gets shown instead.
+	if !widget.ContentAvailable && widget.Error == nil {
+		slog.Debug("Rendering loading state for videos")
+		return template.HTML(fmt.Sprintf(
+			`<div class="widget-loading" data-loading-widget-id="%d" data-refresh-after="%d">Loading videos...</div>`,
+			widget.GetID(),
+			widget.loadingRefreshDelay().Milliseconds(),
+		))
+	}
+
+	view, tmpl := widget.viewFor(prefs, nil)
+	return widget.renderTemplate(view, tmpl)
+}
+
+// viewFor returns the view to render the widget with and the template of its style,
+// with only the given page of one of its sections when the page isn't nil
+func (widget *videosWidget) viewFor(prefs widgetPreferences, page *videosPage) (*videosWidgetView, *template.Template) {
+	var tmpl *template.Template
+
+	style := widget.Style
+	if prefs.Style != "" {
+		style = prefs.Style
+	}
+
+	switch style {
+	case "grid-cards":
+		tmpl = videosWidgetGridTemplate
+		slog.Debug("Using grid template")
+	case "horizontal-list":
+		tmpl = videosWidgetHorizontalListTemplate
+		slog.Debug("Using horizontal list template")
+	case "vertical-list":
+		tmpl = videosWidgetVerticalListTemplate
+		slog.Debug("Using vertical list template")
+	case "grouped-list":
+		tmpl = videosWidgetGroupedListTemplate
+		slog.Debug("Using grouped list template")
+	case "titles-only":
+		tmpl = videosWidgetTitlesOnlyTemplate
+		slog.Debug("Using titles only template")
+	default:
+		tmpl = videosWidgetTemplate
+		slog.Debug("Using default template")
+	}
+
+	view := &videosWidgetView{
+		videosWidget:   widget,
+		hiddenChannels: prefs.HiddenChannels,
+		style:          prefs.Style,
+		page:           page,
+	}
+
+	if !prefs.Expanded {
+		view.CollapseAfter, view.CollapseAfterRows = widget.collapseFor(style)
+	} else {
+		view.CollapseAfter, view.CollapseAfterRows = -1, -1
+	}
+
+	return view, tmpl
+}
+
+// collapseFor returns the collapse-after and collapse-after-rows values to render the
+// style with. Each style only collapses by one of them, the other one is -1 so that
+// it's never mistaken for being in effect.
+func (widget *videosWidget) collapseFor(style string) (collapseAfter, collapseAfterRows int) {
+	// Pages already keep the widget from growing too long
+	if widget.PageSize > 0 {
+		return -1, -1
+	}
+
+	switch style {
+	case "grid-cards":
+		return -1, widget.CollapseAfterRows
+	case "horizontal-list", "vertical-list", "grouped-list", "titles-only":
+		return widget.CollapseAfter, -1
+	default:
+		// Cards are shown in a carousel which doesn't collapse
+		return -1, -1
+	}
+}
+
+// markNewVideos flags the videos that weren't present in the previous fetch. Nothing
+// is considered new on the first fetch since there's nothing to compare against.
+func (widget *videosWidget) markNewVideos(lists ...videoList) {
+	current := make(map[string]struct{})
+
+	for _, videos := range lists {
+		for i := range videos {
+			current[videos[i].Url] = struct{}{}
+
+			if widget.previousUrls == nil {
+				continue
+			}
+
+			_, seen := widget.previousUrls[videos[i].Url]
+			videos[i].IsNew = !seen
+		}
+	}
+
+	widget.previousUrls = current
+}
+
+// NextRefresh returns when the videos are going to be fetched again
+func (widget *videosWidget) NextRefresh() time.Time {
+	return widget.nextUpdate
+}
+
+// PartialSources returns how many of the sources the shown videos come from when some
+// of them failed during the last update, and an empty string otherwise
+func (widget *videosWidget) PartialSources() string {
+	if widget.shownSources >= widget.shownOf {
+		return ""
+	}
+
+	return fmt.Sprintf("showing %d of %d sources", widget.shownSources, widget.shownOf)
+}
+
+// NextRefreshIn is the server rendered fallback for the client side countdown
+func (widget *videosWidget) NextRefreshIn() string {
+	remaining := time.Until(widget.nextUpdate)
+	if remaining <= 0 {
+		return "now"
+	}
+
+	if remaining < time.Hour {
+		return fmt.Sprintf("in %dm", int(math.Ceil(remaining.Minutes())))
+	}
+
+	return fmt.Sprintf("in %dh", int(remaining.Hours()))
+}
+
+// updateChannelInfo refreshes the cached subscriber counts and avatars of the
+// channels present in the lists whose info is missing or has expired
+func (widget *videosWidget) updateChannelInfo(ctx context.Context, lists ...videoList) {
+	if widget.channelInfo == nil {
+		widget.channelInfo = make(map[string]youtubeChannelInfo)
+	}
+
+	now := time.Now()
+	stale := make([]string, 0)
+	seen := make(map[string]struct{})
+
+	for _, videos := range lists {
+		for i := range videos {
+			channelID := videos[i].ChannelID
+			if channelID == "" {
+				continue
+			}
+
+			if _, ok := seen[channelID]; ok {
+				continue
+			}
+			seen[channelID] = struct{}{}
+
+			cached, ok := widget.channelInfo[channelID]
+			if !ok || now.Sub(cached.fetchedAt) > youtubeChannelInfoCacheDuration {
+				stale = append(stale, channelID)
+			}
+		}
+	}
+
+	if len(stale) == 0 {
+		return
+	}
+
+	channels, err := fetchYoutubeChannelInfo(ctx, widget.APIKey, stale, widget.httpClient())
+	if err != nil {
+		slog.Error("Failed to fetch YouTube channel info", "error", err)
+	}
+
+	for channelID, info := range channels {
+		info.fetchedAt = now
+		widget.channelInfo[channelID] = info
+	}
+}
+
+// Shown in place of thumbnails that aren't known unless the widget has its own placeholder
+const videoThumbnailPlaceholder = "data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg' width='16' height='9'%3E%3Crect width='16' height='9' fill='%23ccc'/%3E%3C/svg%3E"
+
+// Shown in place of avatars that aren't known, such as the ones of Rumble channels
+const videoAuthorAvatarPlaceholder = "data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg' width='1' height='1'%3E%3Crect width='1' height='1' fill='%23ccc'/%3E%3C/svg%3E"
+
+// setPlaceholderThumbnails replaces the default placeholder of videos without a
+// thumbnail with the one of the widget
+func (widget *videosWidget) setPlaceholderThumbnails(lists ...videoList) {
+	if widget.PlaceholderThumbnail == "" {
+		return
+	}
+
+	for _, videos := range lists {
+		for i := range videos {
+			if videos[i].ThumbnailUrl == videoThumbnailPlaceholder {
+				videos[i].ThumbnailUrl = widget.PlaceholderThumbnail
+			}
+		}
+	}
+}
+
+// setThumbnailOrientations marks the videos whose thumbnails are likely vertical, after
+// their details are known since the durations of YouTube videos may only be known then
+func (widget *videosWidget) setThumbnailOrientations(lists ...videoList) {
+	for _, videos := range lists {
+		for i := range videos {
+			videos[i].verticalThumbnail = widget.ShortsDetection.hasVerticalThumbnail(&videos[i])
+		}
+	}
+}
+
+// setAuthorAvatars sets the avatar of every video's channel, falling back to a
+// placeholder so that all cards are laid out the same way
+func (widget *videosWidget) setAuthorAvatars(lists ...videoList) {
+	for _, videos := range lists {
+		for i := range videos {
+			videos[i].AuthorAvatarUrl = widget.channelInfo[videos[i].ChannelID].avatarUrl
+			if videos[i].AuthorAvatarUrl == "" {
+				videos[i].AuthorAvatarUrl = videoAuthorAvatarPlaceholder
+			}
+		}
+	}
+}
+
+// hasMinViews reports whether the video has at least min-views views. Videos whose view
+// count isn't known are kept rather than left out.
+func (widget *videosWidget) hasMinViews(v *video) bool {
+	return v.Views == 0 || v.Views >= widget.MinViews
+}
+
+// titleMatchesKeywords reports whether the video's title doesn't contain any of the
+// excluded keywords and, when there are included keywords, contains at least one of them
+func (widget *videosWidget) titleMatchesKeywords(v *video) bool {
+	title := strings.ToLower(v.Title)
+
+	for _, keyword := range widget.ExcludeKeywords {
+		if strings.Contains(title, keyword) {
+			return false
+		}
+	}
+
+	if len(widget.IncludeKeywords) == 0 {
+		return true
+	}
+
+	for _, keyword := range widget.IncludeKeywords {
+		if strings.Contains(title, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// needsVideoDetails reports whether any of the enabled options rely on the details
+// that are only available through the Data API
+func (widget *videosWidget) needsVideoDetails() bool {
+	return widget.APIKey != "" && (widget.HidePastStreams ||
+		widget.RecentLiveBoost > 0 ||
+		widget.ShowLanguage ||
+		len(widget.LanguageInclude) > 0 ||
+		len(widget.LanguageExclude) > 0)
+}
+
+// languageAllowed reports whether the video passes the language filters. Videos whose
+// language is unknown, such as ones from Rumble or ones without language metadata, are
+// always allowed since there's nothing to filter them by.
+func (widget *videosWidget) languageAllowed(v *video) bool {
+	if v.Source != videoSourceYoutube {
+		return true
+	}
+
+	language := widget.videoDetails[v.VideoID].language
+	if language == "" {
+		return true
+	}
+
+	if len(widget.LanguageInclude) > 0 && !videoLanguageMatches(language, widget.LanguageInclude) {
+		return false
+	}
+
+	return !videoLanguageMatches(language, widget.LanguageExclude)
+}
+
+// videoLanguageMatches reports whether the language matches any of the given codes,
+// either exactly or by its primary subtag, so that "en" matches "en-US" but "en-US"
+// doesn't match "en-GB"
+func videoLanguageMatches(language string, codes []string) bool {
+	language = strings.ToLower(language)
+
+	for _, code := range codes {
+		if language == code || strings.HasPrefix(language, code+"-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// updateVideoDetails fills in the details of YouTube videos that are only available
+// through the Data API. Details are cached for as long as the video remains in the feeds.
+func (widget *videosWidget) updateVideoDetails(ctx context.Context, lists ...videoList) {
+	if widget.videoDetails == nil {
+		widget.videoDetails = make(map[string]youtubeVideoDetails)
+	}
+
+	current := make(map[string]struct{})
+	missing := make([]string, 0)
+
+	for _, videos := range lists {
+		for i := range videos {
+			if videos[i].Source != videoSourceYoutube || videos[i].VideoID == "" {
+				continue
+			}
+
+			if _, ok := current[videos[i].VideoID]; ok {
+				continue
+			}
+
+			current[videos[i].VideoID] = struct{}{}
+			if _, ok := widget.videoDetails[videos[i].VideoID]; !ok {
+				missing = append(missing, videos[i].VideoID)
+			}
+		}
+	}
+
+	for id := range widget.videoDetails {
+		if _, ok := current[id]; !ok {
+			delete(widget.videoDetails, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		details, err := fetchYoutubeVideoDetails(ctx, widget.APIKey, missing, widget.httpClient())
+		if err != nil {
+			slog.Error("Failed to fetch YouTube video details", "error", err)
+		}
+
+		for id, d := range details {
+			widget.videoDetails[id] = d
+		}
+	}
+
+	for _, videos := range lists {
+		for i := range videos {
+			d, ok := widget.videoDetails[videos[i].VideoID]
+			if !ok || videos[i].Source != videoSourceYoutube {
+				continue
+			}
+
+			// Upcoming videos don't have a duration yet, keep the one from the feed if any
+			if d.duration > 0 {
+				videos[i].Duration = d.duration
+			}
+			videos[i].StreamEndedAt = d.streamEndedAt
+			videos[i].IsLive = d.liveBroadcastContent == "live"
+
+			if widget.ShowLanguage {
+				videos[i].Language = d.language
+			}
+		}
+	}
+}
+
+// ChannelGroups groups the videos by channel, ordering the groups by their newest video
+func (widget *videosWidget) ChannelGroups(videos videoList) []videoChannelGroup {
+	groups := make([]videoChannelGroup, 0)
+	indexByAuthor := make(map[string]int)
+
+	for i := range videos {
+		v := &videos[i]
+
+		index, ok := indexByAuthor[v.Author]
+		if !ok {
+			index = len(groups)
+			indexByAuthor[v.Author] = index
+			group := videoChannelGroup{Author: v.Author, AuthorUrl: v.AuthorUrl}
+
+			if widget.ShowSubscribers && v.ChannelID != "" {
+				group.Subscribers = widget.channelInfo[v.ChannelID].subscribers
+			}
+
+			groups = append(groups, group)
+		}
+
+		groups[index].Videos = append(groups[index].Videos, *v)
+	}
+
+	return groups
+}
+
+// videoSourceReport is the outcome of fetching a single source during a dry-run
+type videoSourceReport struct {
+	kind   string
+	source string
+	count  int
+	err    error
+}
+
+// dryRun fetches each configured source on its own and reports how many videos
+// it returned, without touching the widget's state
+func (widget *videosWidget) dryRun() []videoSourceReport {
+	reports := make([]videoSourceReport, 0)
+
+	for _, section := range widget.Sections() {
+		for i := range section.Channels {
+			source := section.Channels[i]
+
+			resolved := widget.resolveHandles(context.Background(), []videoSourceField{source})
+			if len(resolved) == 0 {
+				reports = append(reports, videoSourceReport{kind: videoSourceYoutube, source: source.ID, err: errors.New("could not resolve handle")})
+				continue
+			}
+
+			var videos videoList
+			var err error
+			if widget.YoutubeBackend != youtubeBackendRss && strings.HasPrefix(resolved[0].ID, "UC") {
+				videos, err = widget.fetchYoutubeBackend(context.Background(), resolved)
+			} else {
+				videos, err = fetchYoutubeChannelUploads(context.Background(), resolved, widget.VideoUrlTemplate, widget.AuthorUrlTemplate, widget.IncludeShorts, widget.ThumbnailQuality, widget.youtubeHeaders, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			}
+			reports = append(reports, videoSourceReport{kind: videoSourceYoutube, source: source.ID, count: len(videos), err: err})
+		}
+
+		for i := range section.RumbleChannels {
+			source := section.RumbleChannels[i]
+			videos, err := fetchRumbleChannelUploads(context.Background(), []videoSourceField{source}, widget.VideoUrlTemplate, rumbleBridges(widget.RumbleFeedBase), widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			reports = append(reports, videoSourceReport{kind: videoSourceRumble, source: source.ID, count: len(videos), err: err})
+		}
+
+		for i := range section.VimeoChannels {
+			source := section.VimeoChannels[i]
+			videos, err := fetchVimeoChannelUploads(context.Background(), []videoSourceField{source}, widget.VideoUrlTemplate, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			reports = append(reports, videoSourceReport{kind: videoSourceVimeo, source: source.ID, count: len(videos), err: err})
+		}
+
+		for i := range section.TwitchChannels {
+			source := section.TwitchChannels[i]
+			videos, err := fetchTwitchChannelVideos(context.Background(), []videoSourceField{source}, widget.TwitchClientID, widget.TwitchToken, widget.Limit, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			reports = append(reports, videoSourceReport{kind: videoSourceTwitch, source: source.ID, count: len(videos), err: err})
+		}
+
+		for i := range section.OdyseeChannels {
+			source := section.OdyseeChannels[i]
+			videos, err := fetchOdyseeChannelUploads(context.Background(), []videoSourceField{source}, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			reports = append(reports, videoSourceReport{kind: videoSourceOdysee, source: source.ID, count: len(videos), err: err})
+		}
+
+		for i := range section.NebulaChannels {
+			source := section.NebulaChannels[i]
+			videos, err := fetchNebulaUploads(context.Background(), []videoSourceField{source}, widget.NebulaToken, widget.Limit, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			reports = append(reports, videoSourceReport{kind: videoSourceNebula, source: source.ID, count: len(videos), err: err})
+		}
+
+		for i := range section.BitchuteChannels {
+			source := section.BitchuteChannels[i]
+			videos, err := fetchBitchuteChannelUploads(context.Background(), []videoSourceField{source}, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			reports = append(reports, videoSourceReport{kind: videoSourceBitchute, source: source.ID, count: len(videos), err: err})
+		}
+
+		for i := range section.Feeds {
+			source := section.Feeds[i]
+			videos, err := fetchGenericVideoFeeds(context.Background(), []videoSourceField{source}, widget.httpClient(), widget.retryOptions(), widget.Concurrency)
+			reports = append(reports, videoSourceReport{kind: videoSourceFeed, source: source.ID, count: len(videos), err: err})
+		}
+	}
+
+	return reports
+}
+
+// handleRequest routes requests made to /api/widgets/{id}/{path...}
+func (widget *videosWidget) handleRequest(w http.ResponseWriter, r *http.Request) {
+	switch r.PathValue("path") {
+	case "snapshot":
+		widget.handleSnapshotRequest(w, r)
+	case "videos":
+		widget.handleVideosRequest(w, r)
+	case "watched":
+		widget.handleWatchedRequest(w, r)
+	case "metrics":
+		widget.handleMetricsRequest(w, r)
+	case "page":
+		widget.handlePageRequest(w, r)
+	case "diagnostics":
+		widget.handleDiagnosticsRequest(w, r)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+// handleSnapshotRequest renders the current video list as a standalone, email-safe
+// HTML document with inline styles and no external CSS or JS
+func (widget *videosWidget) handleSnapshotRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	widget.mu.RLock()
+	defer widget.mu.RUnlock()
+
+	if !widget.ContentAvailable {
+		http.Error(w, "videos are not available yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	var buffer bytes.Buffer
+	err := videosWidgetSnapshotTemplate.Execute(&buffer, struct {
+		Title       string
+		Videos      videoList
+		GeneratedAt time.Time
+	}{
+		Title:       widget.Title,
+		Videos:      widget.Videos,
+		GeneratedAt: time.Now(),
+	})
+	if err != nil {
+		slog.Error("Failed to render videos snapshot", "error", err)
+		http.Error(w, "failed to render snapshot", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buffer.Bytes())
+}
+
+const (
+	videosWidgetDefaultPageSize = 25
+	videosWidgetMaxPageSize     = 100
+)
+
+// videoJson is the representation of a video returned by the videos endpoint
+type videoJson struct {
+	Title           string    `json:"title"`
+	Url             string    `json:"url"`
+	ThumbnailUrl    string    `json:"thumbnailUrl"`
+	Author          string    `json:"author"`
+	AuthorUrl       string    `json:"authorUrl"`
+	ChannelID       string    `json:"channelId,omitempty"`
+	VideoID         string    `json:"videoId,omitempty"`
+	Source          string    `json:"source"`
+	TimePosted      time.Time `json:"timePosted"`
+	Views           int       `json:"views,omitempty"`
+	DurationSeconds int       `json:"durationSeconds,omitempty"`
+	IsNew           bool      `json:"isNew"`
+	IsCommunityPost bool      `json:"isCommunityPost"`
+	Language        string    `json:"language,omitempty"`
+	Categories      []string  `json:"categories,omitempty"`
+}
+
+func newVideoJson(v *video) videoJson {
+	return videoJson{
+		Title:           v.Title,
+		Url:             v.Url,
+		ThumbnailUrl:    v.ThumbnailUrl,
+		Author:          v.Author,
+		AuthorUrl:       v.AuthorUrl,
+		ChannelID:       v.ChannelID,
+		VideoID:         v.VideoID,
+		Source:          v.Source,
+		TimePosted:      v.TimePosted,
+		Views:           v.Views,
+		DurationSeconds: int(v.Duration.Seconds()),
+		IsNew:           v.IsNew,
+		IsCommunityPost: v.IsCommunityPost,
+		Language:        v.Language,
+		Categories:      v.Categories,
+	}
+}
+
+type videosPageJson struct {
+	Items      []videoJson `json:"items"`
+	NextCursor *string     `json:"nextCursor"`
+	TotalCount int         `json:"totalCount"`
+}
+
+// handleVideosRequest returns the current video list as JSON, paginated through
+// the after and limit query parameters
+func (widget *videosWidget) handleVideosRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := videosWidgetDefaultPageSize
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+			return
+		}
+
+		limit = min(parsed, videosWidgetMaxPageSize)
+	}
+
+	widget.mu.RLock()
+	defer widget.mu.RUnlock()
+
+	// The list is only ever read here, an update is never started by the request
+	if !widget.ContentAvailable {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(widget.loadingRefreshDelay().Seconds()))))
+		http.Error(w, "videos are not available yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	start := 0
+	if cursor := r.URL.Query().Get("after"); cursor != "" {
+		position, ok := widget.Videos.positionAfterCursor(cursor)
+		if !ok {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+
+		start = position
+	}
+
+	end := min(start+limit, len(widget.Videos))
+	page := videosPageJson{
+		Items:      make([]videoJson, 0, end-start),
+		TotalCount: len(widget.Videos),
+	}
+
+	for i := start; i < end; i++ {
+		page.Items = append(page.Items, newVideoJson(&widget.Videos[i]))
+	}
+
+	if end < len(widget.Videos) {
+		cursor := encodeVideoCursor(end-1, widget.Videos[end-1].Url)
+		page.NextCursor = &cursor
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// A cursor holds the position of the last video of a page along with its URL. The
+// URL is what's used to find where the next page starts so that pagination doesn't
+// skip or repeat videos when the list changes in between requests, the position is
+// only used as a fallback when the video is no longer in the list.
+func encodeVideoCursor(position int, videoUrl string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(position) + ":" + videoUrl))
+}
+
+func (v videoList) positionAfterCursor(cursor string) (int, bool) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, false
+	}
+
+	positionString, videoUrl, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return 0, false
+	}
+
+	position, err := strconv.Atoi(positionString)
+	if err != nil || position < 0 {
+		return 0, false
+	}
+
+	for i := range v {
+		if v[i].Url == videoUrl {
+			return i + 1, true
+		}
+	}
+
+	return min(position+1, len(v)), true
+}
+
+// =============================================================================
+// VIDEO LIST METHODS
+// =============================================================================
+
+// sortByNewest sorts the video list by newest first, keeping the order of videos
+// posted at the same time
+func (v videoList) sortByNewest() videoList {
+	sort.SliceStable(v, func(i, j int) bool {
+		return v[i].TimePosted.After(v[j].TimePosted)
+	})
+
+	return v
+}
+
+// sortByOldest sorts the video list by oldest first, keeping the order of videos
+// posted at the same time
+func (v videoList) sortByOldest() videoList {
+	sort.SliceStable(v, func(i, j int) bool {
+		return v[i].TimePosted.Before(v[j].TimePosted)
+	})
+
+	return v
+}
+
+// sortByTitle sorts the video list alphabetically by title regardless of case, with
+// videos that have the same title ordered by newest first
+func (v videoList) sortByTitle() videoList {
+	sort.SliceStable(v, func(i, j int) bool {
+		if c := compareFoldedStrings(v[i].Title, v[j].Title); c != 0 {
+			return c < 0
+		}
+
+		return v[i].TimePosted.After(v[j].TimePosted)
+	})
+
+	return v
+}
+
+// sortByAuthor sorts the video list alphabetically by channel regardless of case, with
+// the videos of each channel ordered by newest first
+func (v videoList) sortByAuthor() videoList {
+	sort.SliceStable(v, func(i, j int) bool {
+		if c := compareFoldedStrings(v[i].Author, v[j].Author); c != 0 {
+			return c < 0
+		}
+
+		return v[i].TimePosted.After(v[j].TimePosted)
+	})
+
+	return v
+}
+
+func compareFoldedStrings(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// sortByExpression sorts the video list by the score computed by expr, highest first,
+// with ties broken by newest first
+func (v videoList) sortByExpression(expr sortExpression, channelBoosts map[string]float64) videoList {
+	now := time.Now()
+	vars := make(map[string]float64, len(videoSortExpressionVariables))
+	scored := make([]struct {
+		video video
+		score float64
+	}, len(v))
+
+	for i := range v {
+		vars["views"] = float64(v[i].Views)
+		vars["age"] = now.Sub(v[i].TimePosted).Hours()
+		vars["duration"] = v[i].Duration.Seconds()
+		vars["channel_boost"] = 0
+
+		for channel, boost := range channelBoosts {
+			if strings.EqualFold(channel, v[i].Author) {
+				vars["channel_boost"] = boost
+				break
+			}
+		}
+
+		scored[i].video = v[i]
+		scored[i].score = evalSortExpression(expr, vars)
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+
+		return scored[i].video.TimePosted.After(scored[j].video.TimePosted)
+	})
+
+	for i := range scored {
+		v[i] = scored[i].video
+	}
+
+	return v
+}
+
+// boostRecentlyEnded moves livestreams and premieres that ended less than window ago
+// to the top of the list. The boost decays linearly over the window so the most
+// recently ended ones come first, and once the window has passed a video falls back
+// to its regular position. Videos without an end time are unaffected.
+func (v videoList) boostRecentlyEnded(window time.Duration, now time.Time) videoList {
+	boost := func(video *video) float64 {
+		if video.StreamEndedAt.IsZero() {
+			return 0
+		}
+
+		elapsed := now.Sub(video.StreamEndedAt)
+		if elapsed < 0 || elapsed >= window {
+			return 0
+		}
+
+		return 1 - float64(elapsed)/float64(window)
+	}
+
+	sort.SliceStable(v, func(i, j int) bool {
+		return boost(&v[i]) > boost(&v[j])
+	})
+
+	return v
+}
+
+// placeScheduled positions the videos whose time is in the future according to
+// handling, see the videosFuture* constants. With as-scheduled the list is left as is.
+func (v videoList) placeScheduled(handling string, now time.Time) videoList {
+	isScheduled := func(video *video) bool {
+		return video.TimePosted.After(now)
+	}
+
+	switch handling {
+	case videosFutureHide:
+		return v.filter(func(video *video) bool {
+			return !isScheduled(video)
+		})
+	case videosFutureTop, videosFutureBottom:
+		scheduled := v.filter(isScheduled)
+		if len(scheduled) == 0 {
+			return v
+		}
+
+		// Soonest first, since those are the ones that are most relevant
+		sort.SliceStable(scheduled, func(i, j int) bool {
+			return scheduled[i].TimePosted.Before(scheduled[j].TimePosted)
+		})
+
+		rest := v.filter(func(video *video) bool {
+			return !isScheduled(video)
+		})
+
+		if handling == videosFutureTop {
+			return append(scheduled, rest...)
+		}
+
+		return append(rest, scheduled...)
+	}
+
+	return v
+}
+
+// prioritizeLive moves the ongoing livestreams before all other videos, keeping the
+// order of both
+func (v videoList) prioritizeLive() videoList {
+	return v.moveToFront(func(video *video) bool {
+		return video.IsLive
+	})
+}
+
+// moveToFront moves the videos that match before all other videos, keeping the order
+// of both
+func (v videoList) moveToFront(matches func(*video) bool) videoList {
+	front := v.filter(matches)
+	if len(front) == 0 {
+		return v
+	}
+
+	return append(front, v.filter(func(video *video) bool {
+		return !matches(video)
+	})...)
+}
+
+// isFromChannel reports whether the video was posted by the channel, which is either
+// the name of the channel or its ID
+func (v *video) isFromChannel(channel string) bool {
+	return strings.EqualFold(channel, v.Author) || channel == v.ChannelID
+}
+
+// isPinned reports whether the video is from one of the pinned channels. Handles are
+// matched through the channel ID they were resolved to.
+func (widget *videosWidget) isPinned(v *video) bool {
+	for _, channel := range widget.PinnedChannels {
+		if v.isFromChannel(channel) {
+			return true
+		}
+
+		if channelID, ok := widget.resolvedHandles[strings.ToLower(channel)]; ok && channelID == v.ChannelID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// postedAfter removes the videos posted before the cutoff. Videos without a time are
+// kept, while entries whose time couldn't be parsed never get here since the fetchers
+// skip them.
+func (v videoList) postedAfter(cutoff time.Time) videoList {
+	return v.filter(func(video *video) bool {
+		return video.TimePosted.IsZero() || !video.TimePosted.Before(cutoff)
+	})
+}
+
+// deduplicate removes videos that appear more than once, keeping the first occurrence.
+// YouTube videos are compared by their ID and everything else by its normalized URL.
+func (v videoList) deduplicate() videoList {
+	seen := make(map[string]struct{}, len(v))
+
+	return v.filter(func(video *video) bool {
+		key := video.deduplicationKey()
+		if _, exists := seen[key]; exists {
+			return false
+		}
+
+		seen[key] = struct{}{}
+		return true
+	})
+}
+
+func (v *video) deduplicationKey() string {
+	if v.Source == videoSourceYoutube && v.VideoID != "" {
+		return "youtube:" + v.VideoID
+	}
+
+	return normalizeVideoUrl(v.Url)
+}
+
+// Query parameters which don't change which video a URL points to
+var videoUrlIgnoredQueryParams = []string{
+	"feature", "si", "pp", "t", "start", "time_continue", "ab_channel",
+	"ref", "referrer", "source", "fbclid", "gclid", "igshid", "mc_cid", "mc_eid",
+}
+
+// normalizeVideoUrl strips the parts of a URL which differ between links to the same
+// video, such as the fragment, tracking and timestamp parameters and the www subdomain.
+// The remaining query parameters, such as YouTube's v, are kept and sorted.
+func normalizeVideoUrl(videoUrl string) string {
+	parsedUrl, err := url.Parse(strings.TrimSpace(videoUrl))
+	if err != nil || parsedUrl.Host == "" {
+		return videoUrl
+	}
+
+	parsedUrl.Scheme = "https"
+	parsedUrl.Host = strings.TrimPrefix(strings.ToLower(parsedUrl.Host), "www.")
+	parsedUrl.Path = strings.TrimSuffix(parsedUrl.Path, "/")
+	parsedUrl.Fragment = ""
+	parsedUrl.RawFragment = ""
+
+	query := parsedUrl.Query()
+	for param := range query {
+		if strings.HasPrefix(param, "utm_") || slices.Contains(videoUrlIgnoredQueryParams, param) {
+			query.Del(param)
+		}
+	}
+
+	// Encode sorts the parameters by key
+	parsedUrl.RawQuery = query.Encode()
+
+	return parsedUrl.String()
+}
+
+// filter returns the videos for which keep returns true
+func (v videoList) filter(keep func(*video) bool) videoList {
+	filtered := make(videoList, 0, len(v))
+
+	for i := range v {
+		if keep(&v[i]) {
+			filtered = append(filtered, v[i])
+		}
+	}
+
+	return filtered
+}
+
+// limitPerAuthor keeps at most limit videos from each author, preserving order
+func (v videoList) limitPerAuthor(limit int) videoList {
+	counts := make(map[string]int)
+	limited := make(videoList, 0, len(v))
+
+	for i := range v {
+		if counts[v[i].Author] >= limit {
+			continue
+		}
+
+		counts[v[i].Author]++
+		limited = append(limited, v[i])
+	}
+
+	return limited
+}
+
+// limitPerSource keeps the first videos of each source up to its limit, the limits
+// being keyed by videoSourceLimitKey. Videos of sources without a limit are all kept.
+func (v videoList) limitPerSource(limits map[string]int) videoList {
+	counts := make(map[string]int)
+	limited := make(videoList, 0, len(v))
+
+	for i := range v {
+		key := videoSourceLimitKey(v[i].Source, v[i].sourceID)
+		if limit, ok := limits[key]; ok && counts[key] >= limit {
+			continue
+		}
+
+		counts[key]++
+		limited = append(limited, v[i])
+	}
+
+	return limited
+}
+
+func videoSourceLimitKey(kind string, id string) string {
+	return kind + "\x00" + id
+}
+
+// sourceLimits returns the limits of the section's sources that have one. Handles are
+// keyed by the channel ID they were resolved to, which is what their videos carry.
+func (widget *videosWidget) sourceLimits(section videosWidgetGroup) map[string]int {
+	limits := make(map[string]int)
+
+	for kind, sources := range map[string][]videoSourceField{
+		videoSourceYoutube:  section.Channels,
+		videoSourceRumble:   section.RumbleChannels,
+		videoSourceVimeo:    section.VimeoChannels,
+		videoSourceTwitch:   section.TwitchChannels,
+		videoSourceOdysee:   section.OdyseeChannels,
+		videoSourceNebula:   section.NebulaChannels,
+		videoSourceBitchute: section.BitchuteChannels,
+		videoSourceFeed:     section.Feeds,
+	} {
+		for i := range sources {
+			if sources[i].Limit <= 0 {
+				continue
+			}
+
+			id := sources[i].ID
+			if channelID, ok := widget.resolvedHandles[strings.ToLower(id)]; ok && kind == videoSourceYoutube {
+				id = channelID
+			}
+
+			limits[videoSourceLimitKey(kind, id)] = sources[i].Limit
+		}
+	}
+
+	return limits
+}
+
+// videoReservation reserves up to min slots for each group of videos that share the same key
+type videoReservation struct {
+	min int
+	key func(*video) string
+}
+
+func videoSourceKey(v *video) string { return v.Source }
+func videoAuthorKey(v *video) string { return v.Author }
+
+// limitWithReservations truncates the list to limit videos while reserving up to min
+// slots for each key of every reservation that has videos. Reserved slots are handed
+// out round-robin so that every key gets a fair share when the reservations exceed the
+// limit. Reservations are applied in order and videos picked by an earlier one count
+// towards the later ones. The remaining slots are filled in the order of the indices
+// in fillOrder, or in the list's order when it's nil, and the returned list keeps the
+// list's existing order.
+func (v videoList) limitWithReservations(limit int, fillOrder []int, reservations ...videoReservation) videoList {
+	if len(v) <= limit {
+		return v
+	}
+
+	selected := make([]bool, len(v))
+	taken := 0
+
+	for _, reservation := range reservations {
+		indicesByKey := make(map[string][]int)
+		keys := make([]string, 0)
+		counts := make(map[string]int)
+
+		for i := range v {
+			key := reservation.key(&v[i])
+			if _, ok := indicesByKey[key]; !ok {
+				keys = append(keys, key)
+			}
+
+			indicesByKey[key] = append(indicesByKey[key], i)
+			if selected[i] {
+				counts[key]++
+			}
+		}
+
+		next := make(map[string]int)
+
+		for round := 0; round < reservation.min && taken < limit; round++ {
+			for _, key := range keys {
+				if taken >= limit {
+					break
+				}
+
+				if counts[key] > round {
+					continue
+				}
+
+				indices, n := indicesByKey[key], next[key]
+				for n < len(indices) && selected[indices[n]] {
+					n++
+				}
+
+				if n >= len(indices) {
+					next[key] = n
+					continue
+				}
+
+				selected[indices[n]] = true
+				next[key] = n + 1
+				counts[key]++
+				taken++
+			}
+		}
+	}
+
+	if fillOrder == nil {
+		fillOrder = make([]int, len(v))
+		for i := range fillOrder {
+			fillOrder[i] = i
+		}
+	}
+
+	for _, i := range fillOrder {
+		if taken >= limit {
+			break
+		}
+
+		if !selected[i] {
+			selected[i] = true
+			taken++
+		}
+	}
+
+	limited := make(videoList, 0, limit)
+	for i := range v {
+		if selected[i] {
+			limited = append(limited, v[i])
+		}
+	}
+
+	return limited
+}
+
+// weightedSourceOrder returns the indices of the videos interleaved by their source,
+// taking as many videos from each source in turn as its weight, in the order they
+// appear in the list. Sources without a weight have a weight of 1 and sources
+// take turns in the order their first video appears in.
+func (v videoList) weightedSourceOrder(weights map[string]int) []int {
+	indicesBySource := make(map[string][]int)
+	sources := make([]string, 0)
+
+	for i := range v {
+		if _, ok := indicesBySource[v[i].Source]; !ok {
+			sources = append(sources, v[i].Source)
+		}
+
+		indicesBySource[v[i].Source] = append(indicesBySource[v[i].Source], i)
+	}
+
+	order := make([]int, 0, len(v))
+	next := make(map[string]int, len(sources))
+
+	for len(order) < len(v) {
+		for _, source := range sources {
+			indices := indicesBySource[source]
+			n := min(len(indices)-next[source], max(weights[source], 1))
+
+			order = append(order, indices[next[source]:next[source]+n]...)
+			next[source] += n
+		}
+	}
+
+	return order
+}
+
+// sortByNewest sorts the rumble video list by newest first
+func (v rumbleVideoList) sortByNewest() rumbleVideoList {
+	sort.Slice(v, func(i, j int) bool {
+		return v[i].TimePosted.After(v[j].TimePosted)
+	})
+
+	return v
+}
+
+// =============================================================================
+// HELPER FUNCTIONS
+// =============================================================================
+
+// videoFeedRequest is a feed request along with the client it should be made with
+type videoFeedRequest struct {
+	request *http.Request
+	client  requestDoer
+}
+
+func decodeVideoFeedTask[T any](r videoFeedRequest) (T, error) {
+	var result T
+
+	response, err := r.client.Do(r.request)
+	if err != nil {
+		return result, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return result, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		truncatedBody, _ := limitStringLength(string(body), 256)
+		err := fmt.Errorf("unexpected status code %d for %s, response: %s", response.StatusCode, r.request.URL, truncatedBody)
+		return result, &videoFeedStatusError{status: response.StatusCode, err: err}
+	}
+
+	if err := checkVideoFeedContent(response, body); err != nil {
+		return result, err
+	}
+
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// clientFor returns the client requests for the source should be made with,
+// which is the source's own proxy if it has one or the given default otherwise
+func (s *videoSourceField) clientFor(defaultClient requestDoer) requestDoer {
+	if s.Proxy.client != nil {
+		return s.Proxy.client
+	}
+
+	return defaultClient
+}
+
+// httpClient returns the client used for the widget's requests
+func (widget *videosWidget) httpClient() requestDoer {
+	if widget.Proxy.client != nil {
+		return widget.Proxy.client
+	}
 
-// initialize sets up the videos widget with default values
-func (widget *videosWidget) initialize() error {
-	// Set initial cache duration - will be extended after first successful fetch
-	widget.withTitle("Videos").withCacheDuration(1 * time.Minute)
+	return videosHTTPClient
+}
 
-	if widget.Limit <= 0 {
-		widget.Limit = 25
+// retryOptions returns how the widget's feed requests should be retried
+func (widget *videosWidget) retryOptions() videoRetryOptions {
+	options := videoRetryOptions{
+		retries:     max(widget.MaxRetries, 0),
+		timeout:     time.Duration(widget.RequestTimeout),
+		conditional: widget.conditionalCache,
+		userAgent:   widget.UserAgent,
+		limiter:     widget.rateLimiter,
 	}
 
-	if widget.CollapseAfterRows == 0 || widget.CollapseAfterRows < -1 {
-		widget.CollapseAfterRows = 4
+	if widget.ShareFeedCache {
+		options.shared = youtubeFeedCache
 	}
 
-	if widget.CollapseAfter == 0 || widget.CollapseAfter < -1 {
-		widget.CollapseAfter = 7
+	return options
+}
+
+// videoSourceIDs returns the IDs of the given sources
+func videoSourceIDs(sources []videoSourceField) []string {
+	ids := make([]string, len(sources))
+	for i := range sources {
+		ids[i] = sources[i].ID
 	}
 
+	return ids
+}
+
+// prepareVideoSources merges the playlists into the YouTube channels, removes
+// duplicate sources and compiles their filters, returning the resulting YouTube
+// and Rumble sources
+func prepareVideoSources(channels, playlists, rumbleChannels []videoSourceField) ([]videoSourceField, []videoSourceField, error) {
 	// A bit cheeky, but from a user's perspective it makes more sense when channels and
 	// playlists are separate things rather than specifying a list of channels and some of
 	// them awkwardly have a "playlist:" prefix
-	if len(widget.Playlists) > 0 {
-		initialLen := len(widget.Channels)
-		widget.Channels = append(widget.Channels, make([]string, len(widget.Playlists))...)
+	for i := range playlists {
+		playlist := playlists[i]
 
-		for i := range widget.Playlists {
-			widget.Channels[initialLen+i] = videosWidgetPlaylistPrefix + widget.Playlists[i]
+		// Links to the playlist already come with the prefix
+		playlist.ID = normalizeYoutubeChannelEntry(strings.TrimSpace(playlist.ID))
+		if !strings.HasPrefix(playlist.ID, videosWidgetPlaylistPrefix) {
+			playlist.ID = videosWidgetPlaylistPrefix + playlist.ID
 		}
+
+		channels = append(channels, playlist)
 	}
 
-	// Mark as first load and set ContentAvailable to false initially
-	widget.isFirstLoad = true
-	widget.ContentAvailable = false
-	
-	// Force immediate update by setting nextUpdate to now
-	widget.nextUpdate = time.Now()
+	for i := range channels {
+		channels[i].ID = normalizeYoutubeChannelEntry(strings.TrimSpace(channels[i].ID))
+	}
 
-	return nil
-}
+	channels, err := prepareVideoSourceList(channels, videoSourceYoutube)
+	if err != nil {
+		return nil, nil, err
+	}
 
-// update handles the widget update cycle with progressive caching
-func (widget *videosWidget) update(ctx context.Context) {
-	// On first load, use shorter cache duration for faster initial display
-	if widget.isFirstLoad {
-		slog.Info("Video widget first load - fetching videos immediately")
-		widget.withCacheDuration(5 * time.Minute) // Shorter cache on first load
-		widget.isFirstLoad = false
-	} else {
-		// Normal updates use longer cache duration
-		widget.withCacheDuration(30 * time.Minute)
+	for i := range rumbleChannels {
+		rumbleChannels[i].ID = normalizeRumbleChannelEntry(strings.TrimSpace(rumbleChannels[i].ID))
 	}
 
-	// Fetch videos immediately
-	widget.fetchVideos()
-	
-	// After successful fetch, content is available
-	if len(widget.Videos) > 0 {
-		widget.ContentAvailable = true
-		slog.Info("Videos fetched successfully", "count", len(widget.Videos))
+	rumbleChannels, err = prepareVideoSourceList(rumbleChannels, videoSourceRumble)
+	if err != nil {
+		return nil, nil, err
 	}
+
+	return channels, rumbleChannels, nil
 }
 
-// fetchVideos fetches videos from both YouTube and Rumble sources
-func (widget *videosWidget) fetchVideos() {
-	slog.Info("Video widget update", "channels", widget.Channels, "rumble_channels", widget.RumbleChannels)
+// prepareVideoSourceList removes duplicates from the sources of a single kind and
+// compiles their filters
+func prepareVideoSourceList(sources []videoSourceField, kind string) ([]videoSourceField, error) {
+	sources = deduplicateVideoSources(sources, kind)
 
-	// Fetch YouTube videos
-	var allVideos videoList
-	if len(widget.Channels) > 0 {
-		youtubeVideos, err := fetchYoutubeChannelUploads(widget.Channels, widget.VideoUrlTemplate, widget.IncludeShorts)
-		if err != nil {
-			slog.Error("Failed to fetch YouTube videos", "error", err)
-		} else {
-			slog.Info("Successfully fetched YouTube videos", "count", len(youtubeVideos))
-			allVideos = append(allVideos, youtubeVideos...)
+	for i := range sources {
+		if err := sources[i].compileFilters(); err != nil {
+			return nil, err
 		}
 	}
 
-	// Fetch Rumble videos
-	if len(widget.RumbleChannels) > 0 {
-		rumbleVideos, err := fetchRumbleChannelUploads(widget.RumbleChannels, widget.VideoUrlTemplate)
-		if err != nil {
-			slog.Error("Failed to fetch Rumble videos", "error", err)
-		} else {
-			slog.Info("Successfully fetched Rumble videos", "count", len(rumbleVideos))
-			// Convert rumbleVideoList to videoList
-			for _, rv := range rumbleVideos {
-				allVideos = append(allVideos, video{
-					ThumbnailUrl: rv.ThumbnailUrl,
-					Title:        rv.Title,
-					Url:          rv.Url,
-					Author:       rv.Author,
-					AuthorUrl:    rv.AuthorUrl,
-					TimePosted:   rv.TimePosted,
-				})
-			}
-		}
+	return sources, nil
+}
+
+func (s *videoSourceField) compileFilters() error {
+	if s.Limit < 0 {
+		return fmt.Errorf("limit of %s must be a positive number", s.ID)
 	}
 
-	// Sort all videos by newest
-	allVideos.sortByNewest()
+	if s.TitleExclude == "" {
+		return nil
+	}
 
-	// Apply limit
-	if len(allVideos) > widget.Limit {
-		allVideos = allVideos[:widget.Limit]
+	pattern, err := regexp.Compile(s.TitleExclude)
+	if err != nil {
+		return fmt.Errorf("invalid title-exclude pattern for %s: %v", s.ID, err)
 	}
 
-	slog.Info("Video widget update complete", "total_videos", len(allVideos))
-	
-	// Debug: Log first few videos to see what data we have
-	for i, v := range allVideos {
-		if i >= 3 { // Only log first 3 videos
-			break
+	s.titleExclude = pattern
+	return nil
+}
+
+// excludesTitle reports whether videos with the given title should be left out
+func (s *videoSourceField) excludesTitle(title string) bool {
+	return s.titleExclude != nil && s.titleExclude.MatchString(title)
+}
+
+// deduplicateVideoSources removes repeated sources, keeping the first occurrence.
+// Handles are compared case-insensitively since YouTube treats them that way,
+// while channel and playlist IDs are case-sensitive and compared exactly.
+func deduplicateVideoSources(sources []videoSourceField, kind string) []videoSourceField {
+	// The ID of the first occurrence of each source, for pointing it out in the warning
+	seen := make(map[string]string, len(sources))
+	deduplicated := make([]videoSourceField, 0, len(sources))
+
+	for i := range sources {
+		sources[i].ID = strings.TrimSpace(sources[i].ID)
+		key := videoSourceDeduplicationKey(sources[i].ID, kind)
+
+		if first, exists := seen[key]; exists {
+			slog.Warn("Collapsed duplicate videos widget source", "kind", kind, "source", sources[i].ID, "duplicate_of", first)
+			continue
 		}
-		slog.Info("Video data", "index", i, "title", v.Title, "author", v.Author, "thumbnail", v.ThumbnailUrl, "url", v.Url, "time", v.TimePosted)
+
+		seen[key] = sources[i].ID
+		deduplicated = append(deduplicated, sources[i])
 	}
-	
-	widget.Videos = allVideos
-	widget.ContentAvailable = true
-	slog.Info("Video content now available", "video_count", len(allVideos))
+
+	return deduplicated
 }
 
-// Render generates the HTML output for the videos widget
-func (widget *videosWidget) Render() template.HTML {
-	var tmpl *template.Template
+// videoSourceDeduplicationKey returns what sources are compared by. Rumble channels
+// given by their name alone are the same as the ones with the c/ prefix, and YouTube
+// handles are the only IDs that aren't case-sensitive.
+func videoSourceDeduplicationKey(id string, kind string) string {
+	if kind == videoSourceRumble && !strings.Contains(id, "/") {
+		id = "c/" + id
+	}
 
-	slog.Info("Rendering video widget", "style", widget.Style, "video_count", len(widget.Videos), "content_available", widget.ContentAvailable)
+	if kind == videoSourceYoutube && isUnresolvedYoutubeChannel(id) {
+		return strings.ToLower(id)
+	}
 
-	// If content is not available yet, show loading message
-	if !widget.ContentAvailable {
-		slog.Info("Rendering loading state for videos")
-		return template.HTML("<div class=\"widget-loading\">Loading videos...</div>")
+	return id
+}
+
+// normalizeVideoTitle cleans up titles that contain invisible characters or
+// inconsistent Unicode. When normalize is true the title is converted to NFKC
+// and zero-width characters are removed, when stripEmoji is true emoji are removed.
+func normalizeVideoTitle(title string, normalize bool, stripEmoji bool) string {
+	if normalize {
+		title = norm.NFKC.String(title)
 	}
 
-	switch widget.Style {
-	case "grid-cards":
-		tmpl = videosWidgetGridTemplate
-		slog.Info("Using grid template")
-	case "vertical-list":
-		tmpl = videosWidgetVerticalListTemplate
-		slog.Info("Using vertical list template")
-	default:
-		tmpl = videosWidgetTemplate
-		slog.Info("Using default template")
+	if normalize {
+		title = strings.Map(func(r rune) rune {
+			if isZeroWidthRune(r) {
+				return -1
+			}
+
+			return r
+		}, title)
+	}
+
+	if stripEmoji {
+		title = stripTitleEmoji(title)
 	}
 
-	return widget.renderTemplate(widget, tmpl)
+	return strings.TrimSpace(sequentialWhitespacePattern.ReplaceAllString(title, " "))
 }
 
-// =============================================================================
-// VIDEO LIST METHODS
-// =============================================================================
+func isZeroWidthRune(r rune) bool {
+	switch r {
+	case '\u200B', '\u200C', '\u200D', '\u2060', '\uFEFF', '\u00AD':
+		return true
+	}
 
-// sortByNewest sorts the video list by newest first
-func (v videoList) sortByNewest() videoList {
-	sort.Slice(v, func(i, j int) bool {
-		return v[i].TimePosted.After(v[j].TimePosted)
-	})
+	return false
+}
 
-	return v
+// stripTitleEmoji removes the characters that are shown as emoji by default and the
+// ones turned into emoji by a variation selector, along with the characters that join
+// or modify them. Symbols that are shown as text by default, such as ♪ or ★, are kept.
+func stripTitleEmoji(title string) string {
+	runes := []rune(title)
+	stripped := make([]rune, 0, len(runes))
+	// Whether the previous character was removed as part of an emoji and whether it
+	// was a joiner, after which the next symbol belongs to the same emoji
+	inEmoji, joined := false, false
+
+	for i, r := range runes {
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		emoji := unicode.Is(emojiPresentation, r) ||
+			next == '\uFE0F' ||
+			(inEmoji && isEmojiComponentRune(r)) ||
+			(joined && unicode.IsSymbol(r))
+
+		if !emoji {
+			stripped = append(stripped, r)
+		}
+
+		inEmoji, joined = emoji, emoji && r == '\u200D'
+	}
+
+	return string(stripped)
 }
 
-// sortByNewest sorts the rumble video list by newest first
-func (v rumbleVideoList) sortByNewest() rumbleVideoList {
-	sort.Slice(v, func(i, j int) bool {
-		return v[i].TimePosted.After(v[j].TimePosted)
-	})
+// isEmojiComponentRune reports whether the character modifies or joins the emoji before
+// it: the emoji variation selector, the zero width joiner, the keycap and the tags of
+// subdivision flags
+func isEmojiComponentRune(r rune) bool {
+	return r == '\uFE0F' || r == '\u200D' || r == '\u20E3' || (r >= 0xE0020 && r <= 0xE007F)
+}
 
-	return v
+// emojiPresentation holds the characters with the Emoji_Presentation property as of
+// Unicode 15.1, which are shown as emoji even without a variation selector
+var emojiPresentation = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x231A, 0x231B, 1}, {0x23E9, 0x23EC, 1}, {0x23F0, 0x23F0, 1}, {0x23F3, 0x23F3, 1},
+		{0x25FD, 0x25FE, 1}, {0x2614, 0x2615, 1}, {0x2648, 0x2653, 1}, {0x267F, 0x267F, 1},
+		{0x2693, 0x2693, 1}, {0x26A1, 0x26A1, 1}, {0x26AA, 0x26AB, 1}, {0x26BD, 0x26BE, 1},
+		{0x26C4, 0x26C5, 1}, {0x26CE, 0x26CE, 1}, {0x26D4, 0x26D4, 1}, {0x26EA, 0x26EA, 1},
+		{0x26F2, 0x26F3, 1}, {0x26F5, 0x26F5, 1}, {0x26FA, 0x26FA, 1}, {0x26FD, 0x26FD, 1},
+		{0x2705, 0x2705, 1}, {0x270A, 0x270B, 1}, {0x2728, 0x2728, 1}, {0x274C, 0x274C, 1},
+		{0x274E, 0x274E, 1}, {0x2753, 0x2755, 1}, {0x2757, 0x2757, 1}, {0x2795, 0x2797, 1},
+		{0x27B0, 0x27B0, 1}, {0x27BF, 0x27BF, 1}, {0x2B1B, 0x2B1C, 1}, {0x2B50, 0x2B50, 1},
+		{0x2B55, 0x2B55, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1F004, 0x1F004, 1}, {0x1F0CF, 0x1F0CF, 1}, {0x1F18E, 0x1F18E, 1}, {0x1F191, 0x1F19A, 1},
+		{0x1F1E6, 0x1F1FF, 1}, {0x1F201, 0x1F201, 1}, {0x1F21A, 0x1F21A, 1}, {0x1F22F, 0x1F22F, 1},
+		{0x1F232, 0x1F236, 1}, {0x1F238, 0x1F23A, 1}, {0x1F250, 0x1F251, 1}, {0x1F300, 0x1F320, 1},
+		{0x1F32D, 0x1F335, 1}, {0x1F337, 0x1F37C, 1}, {0x1F37E, 0x1F393, 1}, {0x1F3A0, 0x1F3CA, 1},
+		{0x1F3CF, 0x1F3D3, 1}, {0x1F3E0, 0x1F3F0, 1}, {0x1F3F4, 0x1F3F4, 1}, {0x1F3F8, 0x1F43E, 1},
+		{0x1F440, 0x1F440, 1}, {0x1F442, 0x1F4FC, 1}, {0x1F4FF, 0x1F53D, 1}, {0x1F54B, 0x1F54E, 1},
+		{0x1F550, 0x1F567, 1}, {0x1F57A, 0x1F57A, 1}, {0x1F595, 0x1F596, 1}, {0x1F5A4, 0x1F5A4, 1},
+		{0x1F5FB, 0x1F64F, 1}, {0x1F680, 0x1F6C5, 1}, {0x1F6CC, 0x1F6CC, 1}, {0x1F6D0, 0x1F6D2, 1},
+		{0x1F6D5, 0x1F6D7, 1}, {0x1F6DC, 0x1F6DF, 1}, {0x1F6EB, 0x1F6EC, 1}, {0x1F6F4, 0x1F6FC, 1},
+		{0x1F7E0, 0x1F7EB, 1}, {0x1F7F0, 0x1F7F0, 1}, {0x1F90C, 0x1F93A, 1}, {0x1F93C, 0x1F945, 1},
+		{0x1F947, 0x1F9FF, 1}, {0x1FA70, 0x1FA7C, 1}, {0x1FA80, 0x1FA89, 1}, {0x1FA8F, 0x1FAC6, 1},
+		{0x1FACE, 0x1FADC, 1}, {0x1FADF, 0x1FAE9, 1}, {0x1FAF0, 0x1FAF8, 1},
+	},
 }
 
-// =============================================================================
-// HELPER FUNCTIONS
-// =============================================================================
+// extractYoutubeVideoID returns the ID of the video from a watch URL
+func extractYoutubeVideoID(videoUrl string) string {
+	parsedUrl, err := url.Parse(videoUrl)
+	if err != nil {
+		return ""
+	}
 
-// parseYoutubeFeedTime parses YouTube feed time format
-func parseYoutubeFeedTime(t string) time.Time {
-	parsedTime, err := time.Parse("2006-01-02T15:04:05-07:00", t)
+	return parsedUrl.Query().Get("v")
+}
+
+// extractRumbleVideoID returns the ID of the video from links to its page, which are in
+// the form of rumble.com/v4abc12-title.html, or to its embed at rumble.com/embed/v4abc12
+func extractRumbleVideoID(videoUrl string) string {
+	parsedUrl, err := url.Parse(strings.TrimSpace(videoUrl))
 	if err != nil {
-		return time.Now()
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(parsedUrl.Path, "/"), "/")
+	segment := segments[0]
+	if segment == "embed" && len(segments) > 1 {
+		segment = segments[1]
+	}
+
+	id, _, _ := strings.Cut(strings.TrimSuffix(segment, ".html"), "-")
+
+	// Rules out the paths of other pages, such as /c/ for channels and /videos
+	if len(id) < 2 || id[0] != 'v' || id[1] < '0' || id[1] > '9' || strings.Trim(id, "0123456789abcdefghijklmnopqrstuvwxyz") != "" {
+		return ""
+	}
+
+	return id
+}
+
+// youtubeThumbnailUrl returns the URL of the video's thumbnail in the given quality,
+// or an empty string for the default quality, which is the thumbnail from the feed,
+// and when the ID of the video isn't known
+func youtubeThumbnailUrl(videoID string, quality string) string {
+	if videoID == "" {
+		return ""
+	}
+
+	switch quality {
+	case youtubeThumbnailHigh:
+		return "https://i.ytimg.com/vi/" + videoID + "/hqdefault.jpg"
+	case youtubeThumbnailMax:
+		return "https://i.ytimg.com/vi/" + videoID + "/maxresdefault.jpg"
+	}
+
+	return ""
+}
+
+// parseYoutubeFeedTime parses YouTube feed time format
+func parseYoutubeFeedTime(t string) (time.Time, error) {
+	return time.Parse("2006-01-02T15:04:05-07:00", t)
+}
+
+// parseFeedDuration parses durations found in feeds, which are either a number of
+// seconds or in the form of H:MM:SS or MM:SS. Returns zero for invalid durations.
+func parseFeedDuration(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	var seconds int
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0
+		}
+
+		seconds = seconds*60 + n
 	}
 
-	return parsedTime
+	return time.Duration(seconds) * time.Second
+}
+
+// isYoutubeLiveThumbnail reports whether the thumbnail from a YouTube feed is the one
+// of an ongoing livestream, which YouTube names differently, e.g. hqdefault_live.jpg
+func isYoutubeLiveThumbnail(url string) bool {
+	return strings.Contains(url, "_live.")
 }
 
 // parseRumbleFeedTime parses Rumble feed time format
-func parseRumbleFeedTime(t string) time.Time {
+func parseRumbleFeedTime(t string) (time.Time, error) {
 	// Handle invalid date strings
 	if t == "" || t == "Invalid Date" {
-		return time.Now()
+		return time.Time{}, fmt.Errorf("invalid time %q", t)
 	}
-	
-	parsedTime, err := time.Parse("Mon, 02 Jan 2006 15:04:05 GMT", t)
-	if err != nil {
-		// Try alternative formats
-		parsedTime, err = time.Parse("Mon, 2 Jan 2006 15:04:05 GMT", t)
-		if err != nil {
-			return time.Now()
+
+	formats := []string{
+		"Mon, 02 Jan 2006 15:04:05 GMT",
+		"Mon, 2 Jan 2006 15:04:05 GMT",
+		time.RFC1123Z,
+		time.RFC3339,
+	}
+
+	for _, format := range formats {
+		parsedTime, err := time.Parse(format, t)
+		if err == nil {
+			return parsedTime, nil
 		}
 	}
 
-	return parsedTime
+	return time.Time{}, fmt.Errorf("unknown time format %q", t)
 }
 
 // =============================================================================
 // API FETCHING FUNCTIONS
 // =============================================================================
 
+// videoSourceFailures is returned by the fetchers when some of the sources could not
+// be fetched, wrapping either errNoContent or errPartialContent
+type videoSourceFailures struct {
+	err    error
+	failed []videoSourceError
+	total  int
+}
+
+func (e *videoSourceFailures) Error() string {
+	return fmt.Sprintf("%v: missing videos from %d of %d sources", e.err, len(e.failed), e.total)
+}
+
+func (e *videoSourceFailures) Unwrap() error {
+	return e.err
+}
+
+// youtubeFeedUrl returns the URL of the feed of a channel or playlist. Without shorts,
+// channels use the playlist of their uploads that leaves shorts out.
+func youtubeFeedUrl(channelOrPlaylistID string, includeShorts bool) string {
+	if strings.HasPrefix(channelOrPlaylistID, videosWidgetPlaylistPrefix) {
+		return "https://www.youtube.com/feeds/videos.xml?playlist_id=" +
+			strings.TrimPrefix(channelOrPlaylistID, videosWidgetPlaylistPrefix)
+	}
+
+	if !includeShorts && strings.HasPrefix(channelOrPlaylistID, "UC") {
+		return "https://www.youtube.com/feeds/videos.xml?playlist_id=" + strings.Replace(channelOrPlaylistID, "UC", "UULF", 1)
+	}
+
+	return "https://www.youtube.com/feeds/videos.xml?channel_id=" + channelOrPlaylistID
+}
+
 // fetchYoutubeChannelUploads fetches videos from YouTube channels/playlists
-func fetchYoutubeChannelUploads(channelOrPlaylistIDs []string, videoUrlTemplate string, includeShorts bool) (videoList, error) {
-	requests := make([]*http.Request, 0, len(channelOrPlaylistIDs))
+func fetchYoutubeChannelUploads(ctx context.Context, sources []videoSourceField, videoUrlTemplate string, authorUrlTemplate string, includeShorts bool, thumbnailQuality string, headers http.Header, client requestDoer, retry videoRetryOptions, workers int) (videoList, error) {
+	channelOrPlaylistIDs := videoSourceIDs(sources)
+	requests := make([]videoFeedRequest, 0, len(channelOrPlaylistIDs))
 
 	for i := range channelOrPlaylistIDs {
-		var feedUrl string
-		if strings.HasPrefix(channelOrPlaylistIDs[i], videosWidgetPlaylistPrefix) {
-			feedUrl = "https://www.youtube.com/feeds/videos.xml?playlist_id=" +
-				strings.TrimPrefix(channelOrPlaylistIDs[i], videosWidgetPlaylistPrefix)
-		} else if !includeShorts && strings.HasPrefix(channelOrPlaylistIDs[i], "UC") {
-			playlistId := strings.Replace(channelOrPlaylistIDs[i], "UC", "UULF", 1)
-			feedUrl = "https://www.youtube.com/feeds/videos.xml?playlist_id=" + playlistId
-		} else {
-			feedUrl = "https://www.youtube.com/feeds/videos.xml?channel_id=" + channelOrPlaylistIDs[i]
-		}
+		request, _ := http.NewRequestWithContext(ctx, "GET", youtubeFeedUrl(channelOrPlaylistIDs[i], includeShorts), nil)
+		setRequestHeaders(request, headers)
+		requests = append(requests, videoFeedRequest{request: request, client: retry.wrap(sources[i].clientFor(client))})
+	}
 
-		request, _ := http.NewRequest("GET", feedUrl, nil)
-		requests = append(requests, request)
+	task := decodeVideoFeedTask[youtubeFeedResponseXml]
+	// Feeds requested with custom headers may differ from the ones others get, so they're never shared
+	if len(headers) == 0 {
+		task = retry.shared.wrap(task)
 	}
 
-	job := newJob(decodeXmlFromRequestTask[youtubeFeedResponseXml](defaultHTTPClient), requests).withWorkers(30)
+	job := newJob(task, requests).withWorkers(workers)
 	responses, errs, err := workerPoolDo(job)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", errNoContent, err)
 	}
 
 	videos := make(videoList, 0, len(channelOrPlaylistIDs)*15)
-	var failed int
+	var failed []videoSourceError
 
 	for i := range responses {
 		if errs[i] != nil {
-			failed++
+			failed = append(failed, videoSourceError{source: channelOrPlaylistIDs[i], err: errs[i]})
 			slog.Error("Failed to fetch youtube feed", "channel", channelOrPlaylistIDs[i], "error", errs[i])
 			continue
 		}
 
 		response := responses[i]
+		channelID := cmp.Or(response.ChannelID, extractYoutubeChannelIDFromUrl(response.ChannelLink))
 
 		for j := range response.Videos {
 			v := &response.Videos[j]
+			if sources[i].excludesTitle(v.Title) {
+				continue
+			}
+
 			var videoUrl string
 
+			videoID := v.VideoID
+			if videoID == "" {
+				videoID = extractYoutubeVideoID(v.Link.Href)
+			}
+
 			if videoUrlTemplate == "" {
 				videoUrl = v.Link.Href
+			} else if videoID != "" {
+				videoUrl = strings.ReplaceAll(videoUrlTemplate, "{VIDEO-ID}", videoID)
 			} else {
-				parsedUrl, err := url.Parse(v.Link.Href)
+				videoUrl = "#"
+			}
 
-				if err == nil {
-					videoUrl = strings.ReplaceAll(videoUrlTemplate, "{VIDEO-ID}", parsedUrl.Query().Get("v"))
-				} else {
-					videoUrl = "#"
-				}
+			timePosted, err := parseYoutubeFeedTime(v.Published)
+			if err != nil {
+				slog.Warn("Skipping YouTube video with invalid publish time", "channel", response.Channel, "title", v.Title, "published", v.Published)
+				continue
 			}
 
 			thumbnailUrl := v.Group.Thumbnail.Url
+			if url := youtubeThumbnailUrl(videoID, thumbnailQuality); url != "" {
+				thumbnailUrl = url
+			}
+			if thumbnailUrl == "" && videoID != "" {
+				// The thumbnails of all videos are available at a predictable URL, so there's
+				// no need to resort to the placeholder when the feed omits the media group
+				thumbnailUrl = "https://i.ytimg.com/vi/" + videoID + "/hqdefault.jpg"
+			}
 			if thumbnailUrl == "" {
-				thumbnailUrl = "data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg' width='16' height='9'%3E%3Crect width='16' height='9' fill='%23ccc'/%3E%3C/svg%3E"
+				thumbnailUrl = videoThumbnailPlaceholder
+			}
+
+			// Left at zero when the feed doesn't include the view count
+			views, _ := strconv.Atoi(v.Group.Community.Statistics.Views)
+
+			categories := splitVideoKeywords(v.Group.Keywords)
+			for _, category := range v.Categories {
+				categories = append(categories, category.Term)
 			}
 
 			videos = append(videos, video{
 				ThumbnailUrl: thumbnailUrl,
 				Title:        v.Title,
 				Url:          videoUrl,
-				Author:       response.Channel,
-				AuthorUrl:    response.ChannelLink + "/videos",
-				TimePosted:   parseYoutubeFeedTime(v.Published),
+				Author:       cmp.Or(sources[i].Name, response.Channel),
+				AuthorUrl:    youtubeAuthorUrl(authorUrlTemplate, channelID, response.ChannelLink),
+				ChannelID:    channelID,
+				VideoID:      videoID,
+				Source:       videoSourceYoutube,
+				sourceID:     sources[i].ID,
+				TimePosted:   timePosted,
+				Views:        views,
+				Duration:     parseFeedDuration(v.Group.Content.Duration),
+				IsLive:       isYoutubeLiveThumbnail(v.Group.Thumbnail.Url),
+				Categories:   videoCategories(categories...),
 			})
 		}
 	}
 
 	if len(videos) == 0 {
+		if len(failed) > 0 {
+			return nil, &videoSourceFailures{err: errNoContent, failed: failed, total: len(sources)}
+		}
+
 		return nil, errNoContent
 	}
 
 	videos.sortByNewest()
 
-	if failed > 0 {
-		return videos, fmt.Errorf("%w: missing videos from %d channels", errPartialContent, failed)
+	if len(failed) > 0 {
+		return videos, &videoSourceFailures{err: errPartialContent, failed: failed, total: len(sources)}
 	}
 
 	return videos, nil
 }
 
+// The bridge used when Rumble's own feed fails, and the last one tried when rumble-feed-base is set
+const rumbleBridgeFeedUrl = "http://rumble-rss.xyz/rumble/"
+
+// normalizeRumbleChannelEntry turns links to a channel into what the widget expects:
+//
+//	https://rumble.com/c/Name/videos -> c/Name
+//	https://rumble.com/user/Name     -> user/Name
+//
+// Other entries are returned as they are.
+func normalizeRumbleChannelEntry(entry string) string {
+	rest := strings.TrimPrefix(entry, "https://")
+	rest = strings.TrimPrefix(rest, "http://")
+	rest = strings.TrimPrefix(rest, "www.")
+
+	rest, ok := strings.CutPrefix(rest, "rumble.com/")
+	if !ok {
+		return entry
+	}
+
+	path, _, _ := strings.Cut(rest, "?")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(segments) > 1 && (segments[0] == "c" || segments[0] == "user") && segments[1] != "" {
+		return segments[0] + "/" + segments[1]
+	}
+
+	return entry
+}
+
+// rumbleDirectFeedUrl returns the URL of the feed Rumble itself provides for a channel.
+// Names without a path are assumed to be channels rather than users.
+func rumbleDirectFeedUrl(channel string) string {
+	if !strings.Contains(channel, "/") {
+		channel = "c/" + channel
+	}
+
+	return "https://rumble.com/" + strings.Trim(channel, "/") + "/rss"
+}
+
+// rumbleBridges returns the base URLs of the bridges to try in order when Rumble's own
+// feed fails, starting with the one from rumble-feed-base when it's set
+func rumbleBridges(feedBase string) []string {
+	if feedBase == "" || feedBase == rumbleBridgeFeedUrl {
+		return []string{rumbleBridgeFeedUrl}
+	}
+
+	return []string{feedBase, rumbleBridgeFeedUrl}
+}
+
+type rumbleFeedRequest struct {
+	ctx     context.Context
+	channel string
+	bridges []string
+	client  requestDoer
+}
+
+// rumbleBridgeUnavailable reports whether the next bridge is worth trying, which is
+// when the bridge couldn't be reached or had an error of its own, as opposed to it
+// not knowing about the channel
+func rumbleBridgeUnavailable(status int, err error) bool {
+	return status == 0 || status >= 500 || errors.Is(err, context.DeadlineExceeded)
+}
+
+// fetchRumbleFeed fetches the feed at feedUrl, returning the status code of the
+// response or 0 when there was none
+func fetchRumbleFeed(r rumbleFeedRequest, feedUrl string) (rumbleFeedResponseXml, int, error) {
+	var feed rumbleFeedResponseXml
+
+	request, _ := http.NewRequestWithContext(r.ctx, "GET", feedUrl, nil)
+	response, err := r.client.Do(request)
+	if err != nil {
+		return feed, 0, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return feed, response.StatusCode, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		truncatedBody, _ := limitStringLength(string(body), 256)
+		err := fmt.Errorf("unexpected status code %d for %s, response: %s", response.StatusCode, feedUrl, truncatedBody)
+		return feed, response.StatusCode, &videoFeedStatusError{status: response.StatusCode, err: err}
+	}
+
+	if err := checkVideoFeedContent(response, body); err != nil {
+		return feed, response.StatusCode, err
+	}
+
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return feed, response.StatusCode, err
+	}
+
+	return feed, response.StatusCode, nil
+}
+
+// fetchRumbleFeedTask fetches the channel's feed directly from Rumble, only falling
+// back to the bridges when that fails so that none of them is a single point of
+// failure. The next bridge is only tried when the previous one is unavailable.
+func fetchRumbleFeedTask(r rumbleFeedRequest) (rumbleFeedResponseXml, error) {
+	feed, _, directErr := fetchRumbleFeed(r, rumbleDirectFeedUrl(r.channel))
+	if directErr == nil && len(feed.Videos) > 0 {
+		slog.Debug("Fetched Rumble feed", "channel", r.channel, "base", "https://rumble.com/")
+		return feed, nil
+	}
+
+	if directErr == nil {
+		directErr = errors.New("feed contains no videos")
+	}
+
+	errs := []string{fmt.Sprintf("direct feed: %v", directErr)}
+
+	for _, bridge := range r.bridges {
+		// No point in trying the bridge when there's no time left
+		if r.ctx.Err() != nil {
+			break
+		}
+
+		slog.Debug("Falling back to Rumble bridge", "channel", r.channel, "bridge", bridge, "error", directErr)
+
+		bridgeFeed, status, err := fetchRumbleFeed(r, bridge+r.channel)
+		if err == nil {
+			slog.Debug("Fetched Rumble feed", "channel", r.channel, "base", bridge)
+			return bridgeFeed, nil
+		}
+
+		errs = append(errs, fmt.Sprintf("bridge %s: %v", bridge, err))
+		if !rumbleBridgeUnavailable(status, err) {
+			break
+		}
+	}
+
+	err := errors.New(strings.Join(errs, ", "))
+
+	// Bridges only know about the channels that exist on Rumble, so whether the channel
+	// exists is up to the direct feed
+	var statusErr *videoFeedStatusError
+	if errors.As(directErr, &statusErr) {
+		return feed, &videoFeedStatusError{status: statusErr.status, err: err}
+	}
+
+	return feed, err
+}
+
 // fetchRumbleChannelUploads fetches videos from Rumble channels
-func fetchRumbleChannelUploads(channelNames []string, videoUrlTemplate string) (rumbleVideoList, error) {
-	requests := make([]*http.Request, 0, len(channelNames))
+func fetchRumbleChannelUploads(ctx context.Context, sources []videoSourceField, videoUrlTemplate string, bridges []string, client requestDoer, retry videoRetryOptions, workers int) (rumbleVideoList, error) {
+	channelNames := videoSourceIDs(sources)
+	requests := make([]rumbleFeedRequest, 0, len(channelNames))
 
 	for i := range channelNames {
-		feedUrl := "http://rumble-rss.xyz/rumble/" + channelNames[i]
-		request, _ := http.NewRequest("GET", feedUrl, nil)
-		requests = append(requests, request)
+		requests = append(requests, rumbleFeedRequest{ctx: ctx, channel: channelNames[i], bridges: bridges, client: retry.wrap(sources[i].clientFor(client))})
 	}
 
-	job := newJob(decodeXmlFromRequestTask[rumbleFeedResponseXml](defaultHTTPClient), requests).withWorkers(30)
+	job := newJob(fetchRumbleFeedTask, requests).withWorkers(workers)
 	responses, errs, err := workerPoolDo(job)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", errNoContent, err)
 	}
 
 	videos := make(rumbleVideoList, 0, len(channelNames)*15)
-	var failed int
+	var failed []videoSourceError
 
 	for i := range responses {
 		if errs[i] != nil {
-			failed++
+			failed = append(failed, videoSourceError{source: channelNames[i], err: errs[i]})
 			slog.Error("Failed to fetch rumble feed", "channel", channelNames[i], "error", errs[i])
 			continue
 		}
@@ -430,19 +3633,30 @@ func fetchRumbleChannelUploads(channelNames []string, videoUrlTemplate string) (
 
 		for j := range response.Videos {
 			v := &response.Videos[j]
-			
+
+			// The guid of Rumble's own feeds isn't always a URL, unlike the bridge's
+			if !strings.HasPrefix(v.Link, "http") {
+				v.Link = v.ItemLink
+			}
+
 			// Skip videos with empty titles or links
 			if v.Title == "" || v.Link == "" {
 				continue
 			}
-			
-			var videoUrl string
 
-			if videoUrlTemplate == "" {
-				videoUrl = v.Link
-			} else {
-				// For Rumble, we might want to extract video ID from the URL
-				videoUrl = v.Link
+			if sources[i].excludesTitle(v.Title) {
+				continue
+			}
+
+			videoUrl := v.Link
+			if id := cmp.Or(extractRumbleVideoID(v.Link), extractRumbleVideoID(v.ItemLink)); videoUrlTemplate != "" && id != "" {
+				videoUrl = strings.ReplaceAll(videoUrlTemplate, "{VIDEO-ID}", id)
+			}
+
+			timePosted, err := parseRumbleFeedTime(v.Published)
+			if err != nil {
+				slog.Warn("Skipping Rumble video with invalid publish time", "channel", response.Channel, "title", v.Title, "published", v.Published)
+				continue
 			}
 
 			// Use MediaThumbnail if available, otherwise use iTunes image
@@ -450,8 +3664,11 @@ func fetchRumbleChannelUploads(channelNames []string, videoUrlTemplate string) (
 			if thumbnailUrl == "" {
 				thumbnailUrl = v.Thumbnail.Url
 			}
+			if thumbnailUrl == "" && strings.HasPrefix(v.Enclosure.Type, "image/") {
+				thumbnailUrl = v.Enclosure.Url
+			}
 			if thumbnailUrl == "" {
-				thumbnailUrl = "data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg' width='16' height='9'%3E%3Crect width='16' height='9' fill='%23ccc'/%3E%3C/svg%3E"
+				thumbnailUrl = videoThumbnailPlaceholder
 			}
 
 			videos = append(videos, rumbleVideo{
@@ -459,20 +3676,27 @@ func fetchRumbleChannelUploads(channelNames []string, videoUrlTemplate string) (
 				Title:        v.Title,
 				Url:          videoUrl,
 				Author:       response.Channel,
-				AuthorUrl:    response.ChannelLink,
-				TimePosted:   parseRumbleFeedTime(v.Published),
+				AuthorUrl:    videoAuthorUrl(response.ChannelLink, ""),
+				TimePosted:   timePosted,
+				Duration:     parseFeedDuration(cmp.Or(v.MediaContent.Duration, v.ItunesDuration)),
+				Categories:   videoCategories(v.Categories...),
+				sourceID:     sources[i].ID,
 			})
 		}
 	}
 
 	if len(videos) == 0 {
+		if len(failed) > 0 {
+			return nil, &videoSourceFailures{err: errNoContent, failed: failed, total: len(sources)}
+		}
+
 		return nil, errNoContent
 	}
 
 	videos.sortByNewest()
 
-	if failed > 0 {
-		return videos, fmt.Errorf("%w: missing videos from %d channels", errPartialContent, failed)
+	if len(failed) > 0 {
+		return videos, &videoSourceFailures{err: errPartialContent, failed: failed, total: len(sources)}
 	}
 
 	return videos, nil
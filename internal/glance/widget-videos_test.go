@@ -0,0 +1,2133 @@
+package glance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func newTestVideosWidget(t *testing.T, config string) *videosWidget {
+	t.Helper()
+
+	widget := &videosWidget{}
+	if err := yaml.Unmarshal([]byte(config), widget); err != nil {
+		t.Fatalf("Failed to decode widget config: %v", err)
+	}
+
+	if err := widget.initialize(); err != nil {
+		t.Fatalf("Failed to initialize widget: %v", err)
+	}
+
+	return widget
+}
+
+type staticResponseDoer string
+
+func (body staticResponseDoer) Do(*http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(string(body))),
+	}, nil
+}
+
+type mapResponseDoer map[string]string
+
+func (responses mapResponseDoer) Do(request *http.Request) (*http.Response, error) {
+	body, exists := responses[request.URL.String()]
+	if !exists {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("not found"))}, nil
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+type requestDoerFunc func(*http.Request) (*http.Response, error)
+
+func (f requestDoerFunc) Do(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// redirectTransport sends every request to the given server regardless of its host
+type redirectTransport struct {
+	server *httptest.Server
+}
+
+func (t redirectTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request = request.Clone(request.Context())
+	request.URL.Scheme = "http"
+	request.URL.Host = strings.TrimPrefix(t.server.URL, "http://")
+
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+func TestVideosWidgetDeduplicatesSources(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+channels:
+  - UCXuqSBlHAE6Xw-yeJA0Tunw
+  - id: UCXuqSBlHAE6Xw-yeJA0Tunw
+  - UCxuqsblhae6xw-yeja0tunw
+  - "@LinusTechTips"
+  - id: "@linustechtips"
+  - "  UCBJycsmduvYEL83R_U4JriQ  "
+  - id: UCBJycsmduvYEL83R_U4JriQ
+playlists:
+  - PL8mG-RkN2uTyZZ00ObwZxxoG_nJbs3qec
+  - id: PL8mG-RkN2uTyZZ00ObwZxxoG_nJbs3qec
+rumble-channels:
+  - c/SomeChannel
+  - id: c/SomeChannel
+`)
+
+	expectedChannels := []string{
+		"UCXuqSBlHAE6Xw-yeJA0Tunw",
+		"UCxuqsblhae6xw-yeja0tunw",
+		"@LinusTechTips",
+		"UCBJycsmduvYEL83R_U4JriQ",
+		videosWidgetPlaylistPrefix + "PL8mG-RkN2uTyZZ00ObwZxxoG_nJbs3qec",
+	}
+
+	if channels := videoSourceIDs(widget.Channels); !slices.Equal(channels, expectedChannels) {
+		t.Fatalf("Expected channels %v, got %v", expectedChannels, channels)
+	}
+
+	if rumbleChannels := videoSourceIDs(widget.RumbleChannels); !slices.Equal(rumbleChannels, []string{"c/SomeChannel"}) {
+		t.Fatalf("Expected a single rumble channel, got %v", rumbleChannels)
+	}
+}
+
+func TestVideosWidgetDeduplicatesSourceLinks(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+channels:
+  - UCXuqSBlHAE6Xw-yeJA0Tunw
+  - https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/videos
+  - https://www.youtube.com/@LinusTechTips/videos
+  - "@linustechtips"
+  - https://www.youtube.com/feeds/videos.xml?channel_id=UCBJycsmduvYEL83R_U4JriQ
+  - UCBJycsmduvYEL83R_U4JriQ
+rumble-channels:
+  - SomeChannel
+  - https://rumble.com/c/SomeChannel/videos
+  - c/somechannel
+  - https://rumble.com/user/SomeUser
+  - user/SomeUser
+`)
+
+	expectedChannels := []string{"UCXuqSBlHAE6Xw-yeJA0Tunw", "@LinusTechTips", "UCBJycsmduvYEL83R_U4JriQ"}
+	if channels := videoSourceIDs(widget.Channels); !slices.Equal(channels, expectedChannels) {
+		t.Errorf("Expected channels %v, got %v", expectedChannels, channels)
+	}
+
+	// Only YouTube handles are compared case-insensitively
+	expectedRumble := []string{"SomeChannel", "c/somechannel", "user/SomeUser"}
+	if channels := videoSourceIDs(widget.RumbleChannels); !slices.Equal(channels, expectedRumble) {
+		t.Errorf("Expected rumble channels %v, got %v", expectedRumble, channels)
+	}
+
+	odysee := deduplicateVideoSources([]videoSourceField{{ID: "@Channel:1"}, {ID: "@channel:1"}}, videoSourceOdysee)
+	if len(odysee) != 2 {
+		t.Errorf("Expected Odysee channels that differ in case to be kept, got %v", videoSourceIDs(odysee))
+	}
+}
+
+func TestVideosWidgetSourceDisplayName(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+channels:
+  - UCXuqSBlHAE6Xw-yeJA0Tunw: Short
+  - id: UCBJycsmduvYEL83R_U4JriQ
+    name: Other
+  - UCBR8-60-B28hp2BmDPdntcQ
+`)
+
+	names := make([]string, len(widget.Channels))
+	for i := range widget.Channels {
+		names[i] = widget.Channels[i].Name
+	}
+
+	if expected := []string{"Short", "Other", ""}; !slices.Equal(names, expected) {
+		t.Fatalf("Expected names %v, got %v", expected, names)
+	}
+
+	if channels := videoSourceIDs(widget.Channels); channels[0] != "UCXuqSBlHAE6Xw-yeJA0Tunw" {
+		t.Fatalf("Expected the shorthand to set the ID, got %v", channels)
+	}
+
+	feed := staticResponseDoer(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <author><name>A Very Long Channel Name</name><uri>https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw</uri></author>
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`)
+
+	sources := []videoSourceField{widget.Channels[0], widget.Channels[2]}
+	videos, err := fetchYoutubeChannelUploads(context.Background(), sources, "", "", true, "", nil, feed, videoRetryOptions{}, videosDefaultConcurrency)
+	if err != nil {
+		t.Fatalf("Failed to fetch uploads: %v", err)
+	}
+
+	authors := make([]string, len(videos))
+	for i := range videos {
+		authors[i] = videos[i].Author
+	}
+	slices.Sort(authors)
+
+	if expected := []string{"A Very Long Channel Name", "Short"}; !slices.Equal(authors, expected) {
+		t.Errorf("Expected authors %v, got %v", expected, authors)
+	}
+}
+
+func TestVideosWidgetGroups(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+groups:
+  - title: Tech
+    channels:
+      - UCXuqSBlHAE6Xw-yeJA0Tunw
+      - UCXuqSBlHAE6Xw-yeJA0Tunw
+    playlists:
+      - PL8mG-RkN2uTyZZ00ObwZxxoG_nJbs3qec
+  - title: Gaming
+    rumble-channels:
+      - c/SomeChannel
+`)
+
+	sections := widget.Sections()
+	if len(sections) != 2 {
+		t.Fatalf("Expected 2 sections, got %d", len(sections))
+	}
+
+	expectedChannels := []string{"UCXuqSBlHAE6Xw-yeJA0Tunw", videosWidgetPlaylistPrefix + "PL8mG-RkN2uTyZZ00ObwZxxoG_nJbs3qec"}
+	if channels := videoSourceIDs(sections[0].Channels); !slices.Equal(channels, expectedChannels) {
+		t.Fatalf("Expected channels %v, got %v", expectedChannels, channels)
+	}
+
+	if rumbleChannels := videoSourceIDs(sections[1].RumbleChannels); !slices.Equal(rumbleChannels, []string{"c/SomeChannel"}) {
+		t.Fatalf("Expected a single rumble channel, got %v", rumbleChannels)
+	}
+
+	invalid := []string{
+		"channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\ngroups:\n  - title: Tech\n",
+		"groups:\n  - channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\n",
+	}
+
+	for _, config := range invalid {
+		widget := &videosWidget{}
+		if err := yaml.Unmarshal([]byte(config), widget); err != nil {
+			t.Fatalf("Failed to decode widget config: %v", err)
+		}
+
+		if err := widget.initialize(); err == nil {
+			t.Errorf("Expected an error when initializing %q", config)
+		}
+	}
+}
+
+func TestVideoListSortOrders(t *testing.T) {
+	base := time.Date(2025, 1, 14, 14, 0, 0, 0, time.UTC)
+	videos := videoList{
+		{Title: "banana", Author: "Zed", Url: "1", TimePosted: base},
+		{Title: "Apple", Author: "amy", Url: "2", TimePosted: base.Add(time.Hour)},
+		{Title: "cherry", Author: "Amy", Url: "3", TimePosted: base},
+		{Title: "apple", Author: "Bob", Url: "4", TimePosted: base.Add(-time.Hour)},
+		{Title: "date", Author: "Zed", Url: "5", TimePosted: base},
+	}
+
+	urls := func(videos videoList) []string {
+		result := make([]string, len(videos))
+		for i := range videos {
+			result[i] = videos[i].Url
+		}
+		return result
+	}
+
+	// Every case has videos that are tied on what they're sorted by and posted at the
+	// same time, listed out of the order of their URLs so that keeping the input order
+	// can't be mistaken for sorting by something else
+	tests := []struct {
+		sortBy   string
+		input    videoList
+		expected []string
+	}{
+		{
+			sortBy: videosSortNewest,
+			input: videoList{
+				{Url: "c", TimePosted: base},
+				{Url: "a", TimePosted: base.Add(time.Hour)},
+				{Url: "e", TimePosted: base},
+				{Url: "b", TimePosted: base},
+				{Url: "d", TimePosted: base.Add(-time.Hour)},
+			},
+			expected: []string{"a", "c", "e", "b", "d"},
+		},
+		{
+			sortBy: videosSortOldest,
+			input: videoList{
+				{Url: "c", TimePosted: base},
+				{Url: "a", TimePosted: base.Add(time.Hour)},
+				{Url: "e", TimePosted: base},
+				{Url: "b", TimePosted: base},
+				{Url: "d", TimePosted: base.Add(-time.Hour)},
+			},
+			expected: []string{"d", "c", "e", "b", "a"},
+		},
+		{
+			sortBy: videosSortTitle,
+			input: videoList{
+				{Title: "Same", Url: "c", TimePosted: base},
+				{Title: "other", Url: "a", TimePosted: base},
+				{Title: "same", Url: "e", TimePosted: base},
+				{Title: "Same", Url: "b", TimePosted: base},
+				{Title: "same", Url: "d", TimePosted: base.Add(time.Hour)},
+			},
+			expected: []string{"a", "d", "c", "e", "b"},
+		},
+		{
+			sortBy: videosSortAuthor,
+			input: videoList{
+				{Author: "Zed", Url: "c", TimePosted: base},
+				{Author: "amy", Url: "a", TimePosted: base},
+				{Author: "zed", Url: "e", TimePosted: base},
+				{Author: "Amy", Url: "b", TimePosted: base},
+				{Author: "Zed", Url: "d", TimePosted: base.Add(time.Hour)},
+			},
+			expected: []string{"a", "b", "d", "c", "e"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.sortBy, func(t *testing.T) {
+			widget := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\nsort-by: "+test.sortBy)
+
+			sorted := widget.arrangeVideos(slices.Clone(test.input))
+			if result := urls(sorted); !slices.Equal(result, test.expected) {
+				t.Errorf("Expected %v when sorting by %s, got %v", test.expected, test.sortBy, result)
+			}
+		})
+	}
+
+	widget := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\nsort-by: oldest\nlimit: 2")
+	if result := urls(widget.arrangeVideos(slices.Clone(videos))); !slices.Equal(result, []string{"4", "1"}) {
+		t.Errorf("Expected the sort order to be applied before the limit, got %v", result)
+	}
+
+	invalid := &videosWidget{}
+	if err := yaml.Unmarshal([]byte("channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\nsort-by: views\n"), invalid); err != nil {
+		t.Fatalf("Failed to decode widget config: %v", err)
+	}
+
+	if err := invalid.initialize(); err == nil {
+		t.Error("Expected an error for an unknown sort-by value")
+	}
+}
+
+func TestVideoListLimitWithMinPerSource(t *testing.T) {
+	now := time.Now()
+	videos := make(videoList, 0)
+
+	for i := range 8 {
+		videos = append(videos, video{
+			Title:      "yt-" + strconv.Itoa(i),
+			Source:     videoSourceYoutube,
+			TimePosted: now.Add(-time.Duration(i) * time.Hour),
+		})
+	}
+
+	for i := range 3 {
+		videos = append(videos, video{
+			Title:      "rumble-" + strconv.Itoa(i),
+			Source:     videoSourceRumble,
+			TimePosted: now.Add(-time.Duration(10+i) * time.Hour),
+		})
+	}
+
+	titles := func(videos videoList) []string {
+		result := make([]string, len(videos))
+		for i := range videos {
+			result[i] = videos[i].Title
+		}
+		return result
+	}
+
+	tests := []struct {
+		name         string
+		limit        int
+		minPerSource int
+		expected     []string
+	}{
+		{
+			name:         "reserves slots for the less prolific source",
+			limit:        5,
+			minPerSource: 2,
+			expected:     []string{"yt-0", "yt-1", "yt-2", "rumble-0", "rumble-1"},
+		},
+		{
+			name:         "reservation is capped by available videos",
+			limit:        7,
+			minPerSource: 5,
+			expected:     []string{"yt-0", "yt-1", "yt-2", "yt-3", "rumble-0", "rumble-1", "rumble-2"},
+		},
+		{
+			name:         "reservations exceeding the limit are handed out round-robin",
+			limit:        3,
+			minPerSource: 3,
+			expected:     []string{"yt-0", "yt-1", "rumble-0"},
+		},
+		{
+			name:         "list shorter than limit is unchanged",
+			limit:        20,
+			minPerSource: 2,
+			expected:     titles(videos),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := slices.Clone(videos)
+			result := titles(input.limitWithReservations(test.limit, nil, videoReservation{min: test.minPerSource, key: videoSourceKey}))
+
+			if !slices.Equal(result, test.expected) {
+				t.Fatalf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestVideoListLimitWithMinPerChannel(t *testing.T) {
+	now := time.Now()
+	videos := make(videoList, 0)
+
+	// a prolific channel followed by three channels that post less often
+	for i := range 6 {
+		videos = append(videos, video{
+			Title:      "a-" + strconv.Itoa(i),
+			Author:     "A",
+			Source:     videoSourceYoutube,
+			TimePosted: now.Add(-time.Duration(i) * time.Hour),
+		})
+	}
+
+	for i, author := range []string{"B", "C", "D"} {
+		for j := range 2 {
+			videos = append(videos, video{
+				Title:      strings.ToLower(author) + "-" + strconv.Itoa(j),
+				Author:     author,
+				Source:     videoSourceYoutube,
+				TimePosted: now.Add(-time.Duration(10+i*2+j) * time.Hour),
+			})
+		}
+	}
+
+	titles := func(videos videoList) []string {
+		result := make([]string, len(videos))
+		for i := range videos {
+			result[i] = videos[i].Title
+		}
+		return result
+	}
+
+	byChannel := func(min int) videoReservation {
+		return videoReservation{min: min, key: videoAuthorKey}
+	}
+
+	tests := []struct {
+		name         string
+		limit        int
+		reservations []videoReservation
+		expected     []string
+	}{
+		{
+			name:         "every channel gets its newest video",
+			limit:        6,
+			reservations: []videoReservation{byChannel(1)},
+			expected:     []string{"a-0", "a-1", "a-2", "b-0", "c-0", "d-0"},
+		},
+		{
+			name:         "reservations exceeding the limit are handed out round-robin",
+			limit:        5,
+			reservations: []videoReservation{byChannel(2)},
+			expected:     []string{"a-0", "a-1", "b-0", "c-0", "d-0"},
+		},
+		{
+			name:  "videos reserved for sources count towards channels",
+			limit: 5,
+			reservations: []videoReservation{
+				{min: 1, key: videoSourceKey},
+				byChannel(1),
+			},
+			expected: []string{"a-0", "a-1", "b-0", "c-0", "d-0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := slices.Clone(videos)
+			result := titles(input.limitWithReservations(test.limit, nil, test.reservations...))
+
+			if !slices.Equal(result, test.expected) {
+				t.Fatalf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestVideoListBoostRecentlyEnded(t *testing.T) {
+	now := time.Now()
+	videos := videoList{
+		{Title: "newest", TimePosted: now.Add(-1 * time.Hour)},
+		{Title: "ended long ago", TimePosted: now.Add(-2 * time.Hour), StreamEndedAt: now.Add(-48 * time.Hour)},
+		{Title: "ended earlier", TimePosted: now.Add(-3 * time.Hour), StreamEndedAt: now.Add(-5 * time.Hour)},
+		{Title: "older", TimePosted: now.Add(-4 * time.Hour)},
+		{Title: "ended just now", TimePosted: now.Add(-5 * time.Hour), StreamEndedAt: now.Add(-10 * time.Minute)},
+	}
+
+	videos.boostRecentlyEnded(6*time.Hour, now)
+
+	expected := []string{"ended just now", "ended earlier", "newest", "ended long ago", "older"}
+	for i := range videos {
+		if videos[i].Title != expected[i] {
+			t.Fatalf("Expected %q at position %d, got %q", expected[i], i, videos[i].Title)
+		}
+	}
+}
+
+func TestVideoListPlaceScheduled(t *testing.T) {
+	now := time.Now()
+	videos := videoList{
+		{Title: "far premiere", TimePosted: now.Add(48 * time.Hour)},
+		{Title: "soon premiere", TimePosted: now.Add(1 * time.Hour)},
+		{Title: "newest", TimePosted: now.Add(-1 * time.Hour)},
+		{Title: "older", TimePosted: now.Add(-2 * time.Hour)},
+	}
+
+	tests := []struct {
+		handling string
+		expected []string
+	}{
+		{videosFutureAsScheduled, []string{"far premiere", "soon premiere", "newest", "older"}},
+		{videosFutureTop, []string{"soon premiere", "far premiere", "newest", "older"}},
+		{videosFutureBottom, []string{"newest", "older", "soon premiere", "far premiere"}},
+		{videosFutureHide, []string{"newest", "older"}},
+	}
+
+	for _, test := range tests {
+		result := slices.Clone(videos).placeScheduled(test.handling, now)
+		titles := make([]string, len(result))
+		for i := range result {
+			titles[i] = result[i].Title
+		}
+
+		if !slices.Equal(titles, test.expected) {
+			t.Errorf("future-handling %s: expected %v, got %v", test.handling, test.expected, titles)
+		}
+	}
+
+	if !videos[0].IsScheduled() || videos[2].IsScheduled() {
+		t.Error("Expected only future-dated videos to be scheduled")
+	}
+
+	widget := &videosWidget{}
+	if err := yaml.Unmarshal([]byte("future-handling: sideways\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\n"), widget); err != nil {
+		t.Fatalf("Failed to decode widget config: %v", err)
+	}
+
+	if err := widget.initialize(); err == nil {
+		t.Fatal("Expected an error for an invalid future-handling value")
+	}
+}
+
+func TestVideoListDeduplicate(t *testing.T) {
+	videos := videoList{
+		{Title: "a", Source: videoSourceRumble, Url: "https://rumble.com/v1-video.html?e9s=src_v1_ucp"},
+		{Title: "b", Source: videoSourceRumble, Url: "https://www.rumble.com/v1-video.html?utm_source=feed&e9s=src_v1_ucp#comments"},
+		{Title: "c", Source: videoSourceRumble, Url: "http://rumble.com/v1-video.html/?e9s=src_v1_ucp&ref=home"},
+		{Title: "d", Source: videoSourceRumble, Url: "https://rumble.com/v2-other.html"},
+		{Title: "e", Source: videoSourceYoutube, VideoID: "dQw4w9WgXcQ", Url: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{Title: "f", Source: videoSourceYoutube, VideoID: "dQw4w9WgXcQ", Url: "https://invidious.example/watch?v=dQw4w9WgXcQ"},
+		{Title: "g", Url: "https://example.com/watch?v=abc&si=xyz&t=42s"},
+		{Title: "h", Url: "https://example.com/watch?t=10&v=abc"},
+		{Title: "i", Url: "https://example.com/watch?v=def"},
+	}
+
+	result := videos.deduplicate()
+	titles := make([]string, len(result))
+	for i := range result {
+		titles[i] = result[i].Title
+	}
+
+	expected := []string{"a", "d", "e", "g", "i"}
+	if !slices.Equal(titles, expected) {
+		t.Fatalf("Expected %v, got %v", expected, titles)
+	}
+}
+
+func TestNormalizeVideoUrl(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ&feature=youtu.be&t=42", "https://youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"http://YouTube.com/watch?utm_campaign=x&v=dQw4w9WgXcQ#t=1m", "https://youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"https://example.com/video/?b=2&a=1", "https://example.com/video?a=1&b=2"},
+		{"not a url", "not a url"},
+	}
+
+	for _, test := range tests {
+		if result := normalizeVideoUrl(test.input); result != test.expected {
+			t.Errorf("normalizeVideoUrl(%q) = %q, expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestNormalizeVideoTitle(t *testing.T) {
+	tests := []struct {
+		title      string
+		normalize  bool
+		stripEmoji bool
+		expected   string
+	}{
+		{"Hello\u200BWorld", true, false, "HelloWorld"},
+		{"\uFEFFZero\u200C\u200DWidth\u2060", true, false, "ZeroWidth"},
+		{"\uFF26\uFF55\uFF4C\uFF4C\uFF57\uFF49\uFF44\uFF54\uFF48 Title", true, false, "Fullwidth Title"},
+		{"Cafe\u0301", true, false, "Caf\u00E9"},
+		{"\uFB01nal \uFB02ight", true, false, "final flight"},
+		{"Soft\u00ADhyphen", true, false, "Softhyphen"},
+		{"Hello\u200BWorld", false, false, "Hello\u200BWorld"},
+		{"\U0001F525 New Video \U0001F525", true, false, "\U0001F525 New Video \U0001F525"},
+		{"\U0001F525 New Video \U0001F525", false, true, "New Video"},
+		{"Family \U0001F468\u200D\U0001F469\u200D\U0001F467 trip", true, true, "Family trip"},
+		{"Flags \U0001F1FA\U0001F1F8 and \u2764\uFE0F and \u2B50 and 1\uFE0F\u20E3", true, true, "Flags and and and"},
+		{"\u266A Song \u2605 review \u2713 \u2192 \u265E", false, true, "\u266A Song \u2605 review \u2713 \u2192 \u265E"},
+		{"Run \U0001F3C3\u200D\u2640\uFE0F and \U0001F3F3\uFE0F\u200D\U0001F308 flag", false, true, "Run and flag"},
+		{"\u0915\u094D\u200D\u0937", false, true, "\u0915\u094D\u200D\u0937"},
+		{"  Too   many\tspaces  ", true, false, "Too many spaces"},
+		{"\u65E5\u672C\u8A9E", true, true, "\u65E5\u672C\u8A9E"},
+	}
+
+	for _, test := range tests {
+		result := normalizeVideoTitle(test.title, test.normalize, test.stripEmoji)
+		if result != test.expected {
+			t.Errorf("normalizeVideoTitle(%q, %t, %t) = %q, expected %q", test.title, test.normalize, test.stripEmoji, result, test.expected)
+		}
+	}
+}
+
+func TestFetchYoutubeChannelUploadsThumbnailFallback(t *testing.T) {
+	feed := staticResponseDoer(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+  <yt:channelId>UCXuqSBlHAE6Xw-yeJA0Tunw</yt:channelId>
+  <author><name>Channel</name><uri>https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw</uri></author>
+  <entry>
+    <yt:videoId>dQw4w9WgXcQ</yt:videoId>
+    <title>Without media group</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+  <entry>
+    <title>Without video ID element</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=9bZkp7q19f0"/>
+    <published>2025-01-01T15:04:05+00:00</published>
+  </entry>
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>With media group</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2024-12-31T15:04:05+00:00</published>
+    <media:group><media:thumbnail url="https://i1.ytimg.com/vi/jNQXAC9IVRw/hqdefault.jpg"/></media:group>
+  </entry>
+</feed>`)
+
+	videos, err := fetchYoutubeChannelUploads(context.Background(), []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}}, "", "", true, youtubeThumbnailDefault, nil, feed, videoRetryOptions{}, videosDefaultConcurrency)
+	if err != nil {
+		t.Fatalf("Failed to fetch uploads: %v", err)
+	}
+
+	expected := []string{
+		"https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg",
+		"https://i.ytimg.com/vi/9bZkp7q19f0/hqdefault.jpg",
+		"https://i1.ytimg.com/vi/jNQXAC9IVRw/hqdefault.jpg",
+	}
+
+	if len(videos) != len(expected) {
+		t.Fatalf("Expected %d videos, got %d", len(expected), len(videos))
+	}
+
+	for i := range videos {
+		if videos[i].ThumbnailUrl != expected[i] {
+			t.Errorf("Expected thumbnail %q for %q, got %q", expected[i], videos[i].Title, videos[i].ThumbnailUrl)
+		}
+	}
+}
+
+func TestFetchYoutubeChannelUploadsThumbnailQuality(t *testing.T) {
+	feed := staticResponseDoer(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+  <title>Channel</title>
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>With video ID</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+    <media:group><media:thumbnail url="https://i1.ytimg.com/vi/jNQXAC9IVRw/hqdefault.jpg"/></media:group>
+  </entry>
+  <entry>
+    <title>Without video ID</title>
+    <link rel="alternate" href="https://example.com/video"/>
+    <published>2025-01-01T15:04:05+00:00</published>
+    <media:group><media:thumbnail url="https://example.com/thumbnail.jpg"/></media:group>
+  </entry>
+</feed>`)
+
+	expected := map[string][]string{
+		youtubeThumbnailDefault: {"https://i1.ytimg.com/vi/jNQXAC9IVRw/hqdefault.jpg", "https://example.com/thumbnail.jpg"},
+		youtubeThumbnailHigh:    {"https://i.ytimg.com/vi/jNQXAC9IVRw/hqdefault.jpg", "https://example.com/thumbnail.jpg"},
+		youtubeThumbnailMax:     {"https://i.ytimg.com/vi/jNQXAC9IVRw/maxresdefault.jpg", "https://example.com/thumbnail.jpg"},
+	}
+
+	for quality, thumbnails := range expected {
+		videos, err := fetchYoutubeChannelUploads(context.Background(), []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}}, "", "", true, quality, nil, feed, videoRetryOptions{}, videosDefaultConcurrency)
+		if err != nil {
+			t.Fatalf("Failed to fetch uploads: %v", err)
+		}
+
+		for i := range videos {
+			if videos[i].ThumbnailUrl != thumbnails[i] {
+				t.Errorf("Expected %s thumbnail %q for %q, got %q", quality, thumbnails[i], videos[i].Title, videos[i].ThumbnailUrl)
+			}
+		}
+	}
+
+	widget := &videosWidget{Channels: []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}}, ThumbnailQuality: "ultra"}
+	if err := widget.initialize(); err == nil || !strings.Contains(err.Error(), "thumbnail-quality") {
+		t.Errorf("Expected an error for an unknown thumbnail quality, got %v", err)
+	}
+}
+
+func TestFetchYoutubeChannelUploadsAuthorUrlTemplate(t *testing.T) {
+	// The feed leaves out the ID of the channel, which is then taken from its link
+	feed := staticResponseDoer(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+  <title>Channel</title>
+  <author><name>Channel</name><uri>https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw</uri></author>
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`)
+
+	sources := []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}}
+
+	videos, err := fetchYoutubeChannelUploads(context.Background(), sources, "", "https://invidious.example.com/channel/{CHANNEL-ID}", true, "", nil, feed, videoRetryOptions{}, videosDefaultConcurrency)
+	if err != nil {
+		t.Fatalf("Failed to fetch uploads: %v", err)
+	}
+
+	if len(videos) != 1 || videos[0].AuthorUrl != "https://invidious.example.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw" || videos[0].ChannelID != "UCXuqSBlHAE6Xw-yeJA0Tunw" {
+		t.Fatalf("Expected the author link to be built from the template, got %+v", videos)
+	}
+
+	videos, err = fetchYoutubeChannelUploads(context.Background(), sources, "", "", true, "", nil, feed, videoRetryOptions{}, videosDefaultConcurrency)
+	if err != nil {
+		t.Fatalf("Failed to fetch uploads: %v", err)
+	}
+
+	if videos[0].AuthorUrl != "https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw/videos" {
+		t.Errorf("Expected the default author link without a template, got %q", videos[0].AuthorUrl)
+	}
+}
+
+func TestVideosWidgetLiveVideos(t *testing.T) {
+	feed := staticResponseDoer(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+  <title>Channel</title>
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Regular video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+    <media:group><media:thumbnail url="https://i1.ytimg.com/vi/jNQXAC9IVRw/hqdefault.jpg"/></media:group>
+  </entry>
+  <entry>
+    <yt:videoId>dQw4w9WgXcQ</yt:videoId>
+    <title>Livestream</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"/>
+    <published>2025-01-01T15:04:05+00:00</published>
+    <media:group><media:thumbnail url="https://i1.ytimg.com/vi/dQw4w9WgXcQ/hqdefault_live.jpg"/></media:group>
+  </entry>
+</feed>`)
+
+	videos, err := fetchYoutubeChannelUploads(context.Background(), []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}}, "", "", true, "", nil, feed, videoRetryOptions{}, videosDefaultConcurrency)
+	if err != nil {
+		t.Fatalf("Failed to fetch uploads: %v", err)
+	}
+
+	if len(videos) != 2 || videos[0].IsLive || !videos[1].IsLive {
+		t.Fatalf("Expected only the livestream to be detected as live, got %+v", videos)
+	}
+
+	widget := newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+prioritize-live: true
+`)
+	arranged := widget.arrangeVideos(slices.Clone(videos))
+	if arranged[0].Title != "Livestream" {
+		t.Errorf("Expected the livestream to be moved to the top, got %q first", arranged[0].Title)
+	}
+
+	widget.Videos = arranged
+	widget.ContentAvailable = true
+
+	for _, style := range []string{"horizontal-cards", "vertical-list", "grouped-list"} {
+		html := string(widget.renderWithPreferences(widgetPreferences{Style: style}))
+		if strings.Count(html, "video-live-badge") != 1 {
+			t.Errorf("Expected a single live badge with the %s style", style)
+		}
+	}
+}
+
+func TestVideosWidgetCollapsePerStyle(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+collapse-after: 3
+collapse-after-rows: 2
+`)
+	widget.Videos = videoList{{Title: "Video", Url: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", TimePosted: time.Now()}}
+	widget.ContentAvailable = true
+
+	tests := []struct {
+		style    string
+		expected string
+	}{
+		{"vertical-list", `data-collapse-after="3"`},
+		{"horizontal-list", `data-collapse-after="3"`},
+		{"grouped-list", `data-collapse-after="3"`},
+		{"titles-only", `data-collapse-after="3"`},
+		{"grid-cards", `data-collapse-after-rows="2"`},
+	}
+
+	for _, test := range tests {
+		after, rows := widget.collapseFor(test.style)
+		if (after == -1) == (rows == -1) {
+			t.Errorf("Expected exactly one collapse value to be used by the %s style, got %d and %d", test.style, after, rows)
+		}
+
+		html := string(widget.renderWithPreferences(widgetPreferences{Style: test.style}))
+		if !strings.Contains(html, test.expected) {
+			t.Errorf("Expected %s to be rendered with the %s style", test.expected, test.style)
+		}
+	}
+
+	if after, rows := widget.collapseFor("horizontal-cards"); after != -1 || rows != -1 {
+		t.Errorf("Expected the horizontal-cards style to never collapse, got %d and %d", after, rows)
+	}
+
+	html := string(widget.renderWithPreferences(widgetPreferences{Style: "vertical-list", Expanded: true}))
+	if !strings.Contains(html, `data-collapse-after="-1"`) {
+		t.Error("Expected an expanded widget to not collapse")
+	}
+}
+
+func TestVideoSourceTitleExclude(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+channels:
+  - id: UCXuqSBlHAE6Xw-yeJA0Tunw
+    title-exclude: (?i)highlights|\d+-\d+
+`)
+
+	feed := staticResponseDoer(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <author><name>Channel</name><uri>https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw</uri></author>
+  <entry><yt:videoId>a</yt:videoId><title>Match HIGHLIGHTS</title><published>2025-01-03T15:04:05+00:00</published></entry>
+  <entry><yt:videoId>b</yt:videoId><title>Home wins 3-1</title><published>2025-01-02T15:04:05+00:00</published></entry>
+  <entry><yt:videoId>c</yt:videoId><title>Pre-match interview</title><published>2025-01-01T15:04:05+00:00</published></entry>
+</feed>`)
+
+	videos, err := fetchYoutubeChannelUploads(context.Background(), widget.Channels, "", "", true, youtubeThumbnailDefault, nil, feed, videoRetryOptions{}, videosDefaultConcurrency)
+	if err != nil {
+		t.Fatalf("Failed to fetch uploads: %v", err)
+	}
+
+	if len(videos) != 1 || videos[0].Title != "Pre-match interview" {
+		t.Fatalf("Expected only the non-matching video, got %v", videos)
+	}
+
+	invalid := &videosWidget{}
+	if err := yaml.Unmarshal([]byte("channels:\n  - id: UCXuqSBlHAE6Xw-yeJA0Tunw\n    title-exclude: \"(unclosed\"\n"), invalid); err != nil {
+		t.Fatalf("Failed to decode widget config: %v", err)
+	}
+
+	if err := invalid.initialize(); err == nil {
+		t.Fatal("Expected an error for an invalid title-exclude pattern")
+	}
+}
+
+func TestVideosWidgetPaginatedVideos(t *testing.T) {
+	widget := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	widget.nextUpdate = time.Now().Add(10 * time.Second)
+
+	request := httptest.NewRequest("GET", "/api/widgets/1/videos", nil)
+	request.SetPathValue("path", "videos")
+	unavailable := httptest.NewRecorder()
+	widget.handleRequest(unavailable, request)
+	if unavailable.Code != http.StatusServiceUnavailable || unavailable.Header().Get("Retry-After") == "" {
+		t.Fatalf("Expected status 503 with a Retry-After header before videos are fetched, got %d", unavailable.Code)
+	}
+
+	widget.ContentAvailable = true
+	for i := range 5 {
+		widget.Videos = append(widget.Videos, video{Title: strconv.Itoa(i), Url: "https://example.com/" + strconv.Itoa(i)})
+	}
+
+	fetchPage := func(query string) videosPageJson {
+		t.Helper()
+
+		request := httptest.NewRequest("GET", "/api/widgets/1/videos"+query, nil)
+		request.SetPathValue("path", "videos")
+		recorder := httptest.NewRecorder()
+		widget.handleRequest(recorder, request)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %q, got %d: %s", query, recorder.Code, recorder.Body.String())
+		}
+
+		var page videosPageJson
+		if err := json.Unmarshal(recorder.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		return page
+	}
+
+	titles := func(page videosPageJson) []string {
+		result := make([]string, len(page.Items))
+		for i := range page.Items {
+			result[i] = page.Items[i].Title
+		}
+		return result
+	}
+
+	first := fetchPage("?limit=2")
+	if !slices.Equal(titles(first), []string{"0", "1"}) || first.NextCursor == nil || first.TotalCount != 5 {
+		t.Fatalf("Unexpected first page: %+v", first)
+	}
+
+	// A new video showing up at the top shouldn't cause the next page to repeat videos
+	widget.Videos = append(videoList{{Title: "new", Url: "https://example.com/new"}}, widget.Videos...)
+
+	second := fetchPage("?limit=2&after=" + *first.NextCursor)
+	if !slices.Equal(titles(second), []string{"2", "3"}) || second.NextCursor == nil {
+		t.Fatalf("Unexpected second page: %+v", second)
+	}
+
+	last := fetchPage("?limit=2&after=" + *second.NextCursor)
+	if !slices.Equal(titles(last), []string{"4"}) || last.NextCursor != nil {
+		t.Fatalf("Unexpected last page: %+v", last)
+	}
+
+	request = httptest.NewRequest("GET", "/api/widgets/1/videos?after=not-a-cursor", nil)
+	request.SetPathValue("path", "videos")
+	recorder := httptest.NewRecorder()
+	widget.handleRequest(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400 for an invalid cursor, got %d", recorder.Code)
+	}
+}
+
+func TestFetchRumbleChannelUploadsFallsBackToBridge(t *testing.T) {
+	directFeed := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Direct Channel</title>
+    <link>https://rumble.com/c/Direct</link>
+    <item>
+      <title>Direct video</title>
+      <link>https://rumble.com/v1-direct.html</link>
+      <guid isPermaLink="false">v1</guid>
+      <pubDate>Thu, 02 Jan 2025 15:04:05 +0000</pubDate>
+      <enclosure url="https://rumble.com/thumb.jpg" type="image/jpeg"/>
+    </item>
+  </channel>
+</rss>`
+
+	bridgeFeed := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Bridged Channel</title>
+    <link>https://rumble.com/c/Bridged</link>
+    <item>
+      <title>Bridged video</title>
+      <guid>https://rumble.com/v2-bridged.html</guid>
+      <pubDate>Thu, 02 Jan 2025 15:04:05 GMT</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+	client := mapResponseDoer{
+		"https://rumble.com/c/Direct/rss":     directFeed,
+		rumbleBridgeFeedUrl + "Bridged":       bridgeFeed,
+		rumbleBridgeFeedUrl + "Direct":        "should not be requested",
+		"https://rumble.com/user/Someone/rss": directFeed,
+	}
+
+	videos, err := fetchRumbleChannelUploads(context.Background(), []videoSourceField{{ID: "Direct"}, {ID: "Bridged"}, {ID: "user/Someone"}}, "", rumbleBridges(""), client, videoRetryOptions{}, videosDefaultConcurrency)
+	if err != nil {
+		t.Fatalf("Failed to fetch uploads: %v", err)
+	}
+
+	urls := make([]string, len(videos))
+	for i := range videos {
+		urls[i] = videos[i].Url
+	}
+	slices.Sort(urls)
+
+	expected := []string{"https://rumble.com/v1-direct.html", "https://rumble.com/v1-direct.html", "https://rumble.com/v2-bridged.html"}
+	if !slices.Equal(urls, expected) {
+		t.Fatalf("Expected %v, got %v", expected, urls)
+	}
+
+	for i := range videos {
+		if videos[i].Author == "Direct Channel" && videos[i].ThumbnailUrl != "https://rumble.com/thumb.jpg" {
+			t.Errorf("Expected the enclosure to be used as the thumbnail, got %q", videos[i].ThumbnailUrl)
+		}
+
+		if videos[i].TimePosted.Year() != 2025 {
+			t.Errorf("Expected the time of %q to be parsed, got %v", videos[i].Title, videos[i].TimePosted)
+		}
+	}
+
+	if _, err := fetchRumbleChannelUploads(context.Background(), []videoSourceField{{ID: "Missing"}}, "", rumbleBridges(""), client, videoRetryOptions{}, videosDefaultConcurrency); err == nil {
+		t.Fatal("Expected an error when both the direct feed and the bridge fail")
+	}
+}
+
+func TestFetchRumbleChannelUploadsVideoUrlTemplate(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Channel</title>
+    <link>https://rumble.com/c/Channel</link>
+    <item>
+      <title>Page</title>
+      <link>https://rumble.com/v4abc12-some-title.html</link>
+      <guid isPermaLink="false">4abc12</guid>
+      <pubDate>Thu, 02 Jan 2025 15:04:05 +0000</pubDate>
+    </item>
+    <item>
+      <title>Embed</title>
+      <guid>https://rumble.com/embed/v5def34/?pub=4</guid>
+      <pubDate>Thu, 02 Jan 2025 14:04:05 +0000</pubDate>
+    </item>
+    <item>
+      <title>Other</title>
+      <guid>https://rumble.com/videos?date=this-week</guid>
+      <pubDate>Thu, 02 Jan 2025 13:04:05 +0000</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+	client := mapResponseDoer{"https://rumble.com/c/Channel/rss": feed}
+
+	videos, err := fetchRumbleChannelUploads(context.Background(), []videoSourceField{{ID: "Channel"}}, "https://rumble.example.com/watch/{VIDEO-ID}", rumbleBridges(""), client, videoRetryOptions{}, videosDefaultConcurrency)
+	if err != nil {
+		t.Fatalf("Failed to fetch uploads: %v", err)
+	}
+
+	urls := make([]string, len(videos))
+	for i := range videos {
+		urls[i] = videos[i].Url
+	}
+
+	expected := []string{"https://rumble.example.com/watch/v4abc12", "https://rumble.example.com/watch/v5def34", "https://rumble.com/videos?date=this-week"}
+	if !slices.Equal(urls, expected) {
+		t.Fatalf("Expected %v, got %v", expected, urls)
+	}
+}
+
+func TestFetchRumbleChannelUploadsFallsBackToNextBridge(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Bridged Channel</title>
+    <item>
+      <title>Bridged video</title>
+      <guid>https://rumble.com/v2-bridged.html</guid>
+      <pubDate>Thu, 02 Jan 2025 15:04:05 GMT</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+	var requested []string
+	var mu sync.Mutex
+
+	client := requestDoerFunc(func(request *http.Request) (*http.Response, error) {
+		mu.Lock()
+		requested = append(requested, request.URL.String())
+		mu.Unlock()
+
+		status, body := http.StatusNotFound, "not found"
+		switch request.URL.String() {
+		case "https://bridge.example/rumble/Down":
+			status = http.StatusBadGateway
+		case rumbleBridgeFeedUrl + "Down":
+			status, body = http.StatusOK, feed
+		case rumbleBridgeFeedUrl + "Unknown":
+			body = "should not be requested"
+		}
+
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})
+
+	widget := newTestVideosWidget(t, `
+rumble-channels: [Down]
+rumble-feed-base: https://bridge.example/rumble
+`)
+	bridges := rumbleBridges(widget.RumbleFeedBase)
+
+	videos, err := fetchRumbleChannelUploads(context.Background(), widget.RumbleChannels, "", bridges, client, videoRetryOptions{}, videosDefaultConcurrency)
+	if err != nil || len(videos) != 1 {
+		t.Fatalf("Expected the video from the fallback bridge, got %+v, %v", videos, err)
+	}
+
+	requested = nil
+	if _, err := fetchRumbleChannelUploads(context.Background(), []videoSourceField{{ID: "Unknown"}}, "", bridges, client, videoRetryOptions{}, videosDefaultConcurrency); err == nil {
+		t.Fatal("Expected an error for a channel none of the feeds know about")
+	}
+
+	// The configured bridge was reachable, so there's no point in asking the next one
+	if expected := []string{"https://rumble.com/c/Unknown/rss", "https://bridge.example/rumble/Unknown"}; !slices.Equal(requested, expected) {
+		t.Errorf("Expected %v to be requested, got %v", expected, requested)
+	}
+}
+
+func TestVideosWidgetFetchDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		channelID := r.URL.Query().Get("channel_id")
+
+		if channelID == "UCslow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+			return
+		}
+
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:yt="http://www.youtube.com/xml/schemas/2015">
+  <author><name>Fast Channel</name><uri>https://www.youtube.com/channel/UCfast</uri></author>
+  <entry>
+    <title>Fast video</title>
+    <yt:videoId>fast</yt:videoId>
+    <link href="https://www.youtube.com/watch?v=fast"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+fetch-deadline: 1s
+include-shorts: true
+channels:
+  - UCfast
+  - UCslow
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+
+	start := time.Now()
+	widget.update(context.Background())
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Expected the fetch to stop at the deadline, took %v", elapsed)
+	}
+
+	if len(widget.Videos) != 1 || widget.Videos[0].Title != "Fast video" {
+		t.Fatalf("Expected only the video of the fast channel, got %+v", widget.Videos)
+	}
+
+	if widget.Notice == nil || !strings.Contains(widget.Notice.Error(), "1 of 2 sources") {
+		t.Fatalf("Expected a notice counting the skipped source as failed, got %v", widget.Notice)
+	}
+}
+
+func TestVideosWidgetLanguageFilters(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+api-key: key
+language-include: [EN]
+language-exclude: [en-in]
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+`)
+
+	widget.videoDetails = map[string]youtubeVideoDetails{
+		"us":      {language: "en-US"},
+		"english": {language: "en"},
+		"india":   {language: "en-IN"},
+		"german":  {language: "de"},
+		"unknown": {},
+	}
+
+	videos := videoList{
+		{VideoID: "us", Source: videoSourceYoutube},
+		{VideoID: "english", Source: videoSourceYoutube},
+		{VideoID: "india", Source: videoSourceYoutube},
+		{VideoID: "german", Source: videoSourceYoutube},
+		{VideoID: "unknown", Source: videoSourceYoutube},
+		{VideoID: "german", Source: videoSourceRumble},
+	}
+
+	filtered := videos.filter(widget.languageAllowed)
+
+	ids := make([]string, len(filtered))
+	for i := range filtered {
+		ids[i] = filtered[i].VideoID + "/" + filtered[i].Source
+	}
+
+	expected := []string{"us/youtube", "english/youtube", "unknown/youtube", "german/rumble"}
+	if !slices.Equal(ids, expected) {
+		t.Fatalf("Expected %v, got %v", expected, ids)
+	}
+}
+
+func TestVideosWidgetRecoversFromEmptyFetches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+recover-after-empty: 2
+max-retries: -1
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+	widget.videoDetails = map[string]youtubeVideoDetails{"id": {}}
+
+	widget.update(context.Background())
+	if !widget.nextUpdate.After(time.Now()) || widget.videoDetails == nil {
+		t.Fatal("Expected the first empty update to not trigger a recovery")
+	}
+
+	widget.update(context.Background())
+	if widget.nextUpdate.After(time.Now()) {
+		t.Fatal("Expected the second empty update to schedule an immediate refetch")
+	}
+
+	if widget.videoDetails != nil {
+		t.Fatal("Expected the caches to be cleared")
+	}
+
+	widget.update(context.Background())
+	if !widget.nextUpdate.After(time.Now()) {
+		t.Fatal("Expected the count of empty updates to start over after a recovery")
+	}
+}
+
+func TestVideosWidgetBacksOffAfterFailedUpdates(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Back up</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+recover-after-empty: -1
+max-retries: -1
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+
+	for _, expected := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 15 * time.Minute, 15 * time.Minute} {
+		widget.update(context.Background())
+
+		if delay := time.Until(widget.nextUpdate); delay > expected || delay < expected-time.Minute {
+			t.Fatalf("Expected the next update in %v, got %v", expected, delay)
+		}
+	}
+
+	failing.Store(false)
+	widget.update(context.Background())
+
+	if delay := time.Until(widget.nextUpdate); delay < 29*time.Minute || widget.failedUpdates != 0 {
+		t.Fatalf("Expected the usual update interval once fetching succeeds, got %v", delay)
+	}
+}
+
+func TestVideosWidgetKeywordFilters(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+exclude-keywords: ["#Shorts", "live stream"]
+include-keywords: [review, Tutorial]
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+`)
+
+	videos := videoList{
+		{Title: "Camera REVIEW"},
+		{Title: "Go tutorial, part 2"},
+		{Title: "Quick review #shorts"},
+		{Title: "Tutorial (Live Stream VOD)"},
+		{Title: "Vlog"},
+	}
+
+	filtered := videos.filter(widget.titleMatchesKeywords)
+
+	result := make([]string, len(filtered))
+	for i := range filtered {
+		result[i] = filtered[i].Title
+	}
+
+	expected := []string{"Camera REVIEW", "Go tutorial, part 2"}
+	if !slices.Equal(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestVideosWidgetMaxAge(t *testing.T) {
+	now := time.Now()
+
+	videos := videoList{
+		{Title: "recent", TimePosted: now.Add(-time.Hour)},
+		{Title: "old", TimePosted: now.Add(-8 * 24 * time.Hour)},
+		{Title: "scheduled", TimePosted: now.Add(time.Hour)},
+		{Title: "unknown"},
+	}
+
+	filtered := videos.postedAfter(now.Add(-7 * 24 * time.Hour))
+
+	result := make([]string, len(filtered))
+	for i := range filtered {
+		result[i] = filtered[i].Title
+	}
+
+	expected := []string{"recent", "scheduled", "unknown"}
+	if !slices.Equal(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestVideoDurations(t *testing.T) {
+	durations := map[string]time.Duration{
+		"":         0,
+		"245":      245 * time.Second,
+		"4:05":     245 * time.Second,
+		"01:02:03": time.Hour + 2*time.Minute + 3*time.Second,
+		"1:-2":     0,
+		"PT4M":     0,
+	}
+
+	for value, expected := range durations {
+		if result := parseFeedDuration(value); result != expected {
+			t.Errorf("parseFeedDuration(%q) = %v, expected %v", value, result, expected)
+		}
+	}
+
+	formatted := map[time.Duration]string{
+		9 * time.Second:   "0:09",
+		245 * time.Second: "4:05",
+		time.Hour + 2*time.Minute + 3*time.Second: "1:02:03",
+	}
+
+	for duration, expected := range formatted {
+		if result := (video{Duration: duration}).FormattedDuration(); result != expected {
+			t.Errorf("FormattedDuration() of %v = %q, expected %q", duration, result, expected)
+		}
+	}
+}
+
+func TestVideosWidgetLoadingPlaceholder(t *testing.T) {
+	widget := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	widget.setID(4)
+	widget.nextUpdate = time.Now().Add(10 * time.Second)
+
+	delay := widget.loadingRefreshDelay()
+	if delay <= 10*time.Second || delay > 10*time.Second+videosWidgetLoadingRefreshBuffer {
+		t.Errorf("Expected the refresh delay to follow the next update, got %v", delay)
+	}
+
+	html := string(widget.Render())
+	if !strings.Contains(html, `data-loading-widget-id="4"`) || !strings.Contains(html, "data-refresh-after=") {
+		t.Errorf("Expected a placeholder which refreshes the widget, got %s", html)
+	}
+
+	widget.nextUpdate = time.Now().Add(-time.Minute)
+	if delay := widget.loadingRefreshDelay(); delay != videosWidgetLoadingRefreshBuffer {
+		t.Errorf("Expected an overdue update to only wait for the buffer, got %v", delay)
+	}
+}
+
+func TestVideosWidgetShowsErrorWhenAllSourcesFail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+max-retries: -1
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw, UCsBjURrPoezykLs9EqgamOA]
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+
+	if widget.IsContentAvailable() {
+		t.Fatal("Expected the widget to not be ready before its first update")
+	}
+
+	widget.update(context.Background())
+
+	if widget.ContentAvailable || !widget.IsContentAvailable() {
+		t.Fatal("Expected the failed widget to be ready without having any content")
+	}
+
+	if widget.Error == nil || !strings.Contains(widget.Error.Error(), "2 of 2 sources") {
+		t.Fatalf("Expected an error with the count of failed sources, got %v", widget.Error)
+	}
+
+	html := string(widget.Render())
+	if strings.Contains(html, "data-loading-widget-id") || !strings.Contains(html, "2 of 2 sources") {
+		t.Errorf("Expected the error to be rendered instead of the loading placeholder, got %s", html)
+	}
+}
+
+func TestVideosWidgetRefreshRequest(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <title>Channel</title>
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Refreshed video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+show-refresh-button: true
+`)
+	widget.setID(7)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+
+	app := &application{widgetPage: map[uint64]*page{7: {}}}
+
+	recorder := httptest.NewRecorder()
+	app.handleWidgetRefreshRequest(recorder, httptest.NewRequest("GET", "/api/widgets/7/refresh", nil), widget)
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected refreshing through a GET request to not be allowed, got status %d", recorder.Code)
+	}
+
+	// Requests made while the widget is being refreshed get the result of that refresh
+	recorders := []*httptest.ResponseRecorder{httptest.NewRecorder(), httptest.NewRecorder()}
+	var wg sync.WaitGroup
+	for _, recorder := range recorders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.handleWidgetRefreshRequest(recorder, httptest.NewRequest("POST", "/api/widgets/7/refresh", nil), widget)
+		}()
+	}
+	wg.Wait()
+
+	if count := requests.Load(); count != 1 {
+		t.Errorf("Expected concurrent refreshes to fetch the videos once, got %d fetches", count)
+	}
+
+	for _, recorder := range recorders {
+		body := recorder.Body.String()
+		if recorder.Code != http.StatusOK || !strings.Contains(body, "Refreshed video") || !strings.Contains(body, "data-refresh-widget") {
+			t.Errorf("Expected the refreshed widget with its refresh button, got status %d and %s", recorder.Code, body)
+		}
+	}
+}
+
+func TestClampVideosConcurrency(t *testing.T) {
+	tests := []struct {
+		value    int
+		expected int
+	}{
+		{value: 0, expected: videosDefaultConcurrency},
+		{value: -5, expected: 1},
+		{value: 1, expected: 1},
+		{value: 8, expected: 8},
+		{value: videosMaxConcurrency, expected: videosMaxConcurrency},
+		{value: videosMaxConcurrency + 1, expected: videosMaxConcurrency},
+	}
+
+	for _, test := range tests {
+		if result := clampVideosConcurrency(test.value); result != test.expected {
+			t.Errorf("Expected concurrency %d to be clamped to %d, got %d", test.value, test.expected, result)
+		}
+	}
+
+	widget := newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+concurrency: 500
+`)
+	if widget.Concurrency != videosMaxConcurrency {
+		t.Errorf("Expected the widget's concurrency to be clamped, got %d", widget.Concurrency)
+	}
+
+	// Never more workers than there are feeds to fetch
+	job := newJob(fetchGenericVideoFeedTask, make([]genericVideoFeedRequest, 3)).withWorkers(widget.Concurrency)
+	if job.workers != 3 {
+		t.Errorf("Expected as many workers as feeds, got %d", job.workers)
+	}
+}
+
+func TestVideosWidgetPlaceholderThumbnail(t *testing.T) {
+	placeholder := "data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg' width='16' height='9'%3E%3C/svg%3E"
+
+	widget := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\nplaceholder-thumbnail: \""+placeholder+"\"")
+	widget.setID(5)
+
+	videos := videoList{
+		{Title: "Missing", Url: "https://a", ThumbnailUrl: videoThumbnailPlaceholder, TimePosted: time.Now()},
+		{Title: "Present", Url: "https://b", ThumbnailUrl: "https://i.ytimg.com/vi/b/hqdefault.jpg", TimePosted: time.Now()},
+	}
+	widget.setPlaceholderThumbnails(videos)
+
+	if videos[0].ThumbnailUrl != placeholder || videos[1].ThumbnailUrl == placeholder {
+		t.Fatalf("Expected only the missing thumbnail to be replaced, got %+v", videos)
+	}
+
+	widget.Videos = videos
+	widget.ContentAvailable = true
+
+	// Data URIs are only kept by the templates when they're marked as safe
+	html := string(widget.Render())
+	if strings.Contains(html, "ZgotmplZ") || !strings.Contains(html, `src="data:image/svg`) {
+		t.Errorf("Expected the placeholder to be rendered, got %s", html)
+	}
+
+	if src, ok := (video{ThumbnailUrl: "data:text/html,<script>"}).ThumbnailSrc().(string); !ok || src == "" {
+		t.Error("Expected data URIs that aren't images to not be marked as safe")
+	}
+
+	invalid := &videosWidget{}
+	if err := yaml.Unmarshal([]byte("channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\nplaceholder-thumbnail: javascript:alert(1)\n"), invalid); err != nil {
+		t.Fatalf("Failed to decode widget config: %v", err)
+	}
+
+	if err := invalid.initialize(); err == nil {
+		t.Error("Expected an error for a placeholder thumbnail with an unsupported scheme")
+	}
+}
+
+func TestVideosWidgetThumbnailPreload(t *testing.T) {
+	videos := videoList{
+		{Title: "A", Url: "https://a", ThumbnailUrl: "https://i.ytimg.com/vi/a/hqdefault.jpg", TimePosted: time.Now()},
+		{Title: "B", Url: "https://b", ThumbnailUrl: "https://i.ytimg.com/vi/b/hqdefault.jpg", TimePosted: time.Now()},
+		{Title: "C", Url: "https://c", ThumbnailUrl: "https://i.ytimg.com/vi/c/hqdefault.jpg", TimePosted: time.Now()},
+	}
+
+	for _, test := range []struct {
+		config string
+		eager  int
+	}{
+		{config: "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]", eager: 0},
+		{config: "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\nthumbnail-preload: 2", eager: 2},
+		{config: "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]\nthumbnail-preload: 10\nstyle: vertical-list", eager: 3},
+	} {
+		widget := newTestVideosWidget(t, test.config)
+		widget.Videos = videos
+		widget.ContentAvailable = true
+
+		html := string(widget.Render())
+		if eager, lazy := strings.Count(html, `loading="eager"`), strings.Count(html, `loading="lazy"`); eager != test.eager || lazy != len(videos)-test.eager {
+			t.Errorf("Expected %d eagerly loaded thumbnails for %q, got %d eager and %d lazy", test.eager, test.config, eager, lazy)
+		}
+	}
+}
+
+func TestVideosWidgetMinViews(t *testing.T) {
+	feed := staticResponseDoer(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+  <author><name>Channel</name><uri>https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw</uri></author>
+  <entry>
+    <yt:videoId>dQw4w9WgXcQ</yt:videoId>
+    <title>Popular</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"/>
+    <published>2025-01-03T15:04:05+00:00</published>
+    <media:group><media:community><media:statistics views="15300"/></media:community></media:group>
+  </entry>
+  <entry>
+    <yt:videoId>9bZkp7q19f0</yt:videoId>
+    <title>New</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=9bZkp7q19f0"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+    <media:group><media:community><media:statistics views="12"/></media:community></media:group>
+  </entry>
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Unknown</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-01T15:04:05+00:00</published>
+  </entry>
+</feed>`)
+
+	videos, err := fetchYoutubeChannelUploads(context.Background(), []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}}, "", "", true, youtubeThumbnailDefault, nil, feed, videoRetryOptions{}, videosDefaultConcurrency)
+	if err != nil || len(videos) != 3 || videos[0].Views != 15300 || videos[2].Views != 0 {
+		t.Fatalf("Expected the view counts from the feed, got %+v, %v", videos, err)
+	}
+
+	widget := newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+min-views: 1000
+show-views: true
+`)
+
+	// Videos without a known view count are kept
+	widget.Videos = videos.filter(widget.hasMinViews)
+	if titles := []string{widget.Videos[0].Title, widget.Videos[len(widget.Videos)-1].Title}; len(widget.Videos) != 2 || !slices.Equal(titles, []string{"Popular", "Unknown"}) {
+		t.Fatalf("Expected the video with few views to be left out, got %+v", widget.Videos)
+	}
+
+	widget.ContentAvailable = true
+	if html := string(widget.Render()); strings.Count(html, " views</li>") != 1 || !strings.Contains(html, "15k views") {
+		t.Errorf("Expected only the known view count to be shown, got %s", html)
+	}
+}
+
+func TestVideosWidgetDisplayLimit(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+limit: 5
+`)
+
+	if widget.DisplayLimit != 5 {
+		t.Fatalf("Expected display-limit to default to limit, got %d", widget.DisplayLimit)
+	}
+
+	widget = newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+style: vertical-list
+limit: 5
+display-limit: 2
+`)
+
+	for i := range 5 {
+		author := "Channel"
+		if i == 0 {
+			author = "Hidden"
+		}
+
+		widget.Videos = append(widget.Videos, video{
+			Title:  fmt.Sprintf("Video %d", i),
+			Url:    fmt.Sprintf("https://www.youtube.com/watch?v=%d", i),
+			Author: author,
+		})
+	}
+	widget.ContentAvailable = true
+
+	// Hidden channels don't take up any of the displayed slots
+	html := string(widget.renderWithPreferences(widgetPreferences{HiddenChannels: []string{"Hidden"}}))
+	if strings.Contains(html, "Video 0") || !strings.Contains(html, "Video 2") || strings.Contains(html, "Video 3") {
+		t.Errorf("Expected only the first two visible videos to be rendered, got %s", html)
+	}
+
+	if len(widget.Videos) != 5 {
+		t.Errorf("Expected the stored videos to be left untouched, got %d", len(widget.Videos))
+	}
+}
+
+func TestVideosWidgetPartialSources(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("channel_id") == "UCbroken" && failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:yt="http://www.youtube.com/xml/schemas/2015">
+  <author><name>Channel</name><uri>https://www.youtube.com/channel/UCworking</uri></author>
+  <entry>
+    <title>Video</title>
+    <yt:videoId>video</yt:videoId>
+    <link href="https://www.youtube.com/watch?v=video"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+include-shorts: true
+max-retries: -1
+channels:
+  - UCworking
+  - UCbroken
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+
+	widget.update(context.Background())
+	if html := string(widget.Render()); !strings.Contains(html, "showing 1 of 2 sources") {
+		t.Fatalf("Expected the header to mention the failed source, got %s", html)
+	}
+
+	failing.Store(false)
+	widget.update(context.Background())
+	if html := string(widget.Render()); strings.Contains(html, "videos-partial-sources") {
+		t.Errorf("Expected the indicator to disappear once every source is fetched, got %s", html)
+	}
+}
+
+// barrierTransport only responds once the expected number of requests are waiting on
+// it at the same time, failing the requests when that doesn't happen in time
+type barrierTransport struct {
+	expected  int32
+	arrived   atomic.Int32
+	released  chan struct{}
+	responses map[string]string
+}
+
+func (t *barrierTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if t.arrived.Add(1) == t.expected {
+		close(t.released)
+	}
+
+	select {
+	case <-t.released:
+	case <-time.After(2 * time.Second):
+		return nil, errors.New("requests weren't made at the same time")
+	}
+
+	body, ok := t.responses[request.URL.Host]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestVideosWidgetFetchesSourcesConcurrently(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+include-shorts: true
+max-retries: -1
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+odysee-channels: ["@someone:a"]
+`)
+
+	// Both videos are posted at the same time so that only their order of sources decides
+	// which comes first
+	widget.Proxy.client = &http.Client{Transport: &barrierTransport{
+		expected: 2,
+		released: make(chan struct{}),
+		responses: map[string]string{
+			"www.youtube.com": `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:yt="http://www.youtube.com/xml/schemas/2015">
+  <author><name>YouTube Channel</name><uri>https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw</uri></author>
+  <entry>
+    <title>YouTube video</title>
+    <yt:videoId>youtube</yt:videoId>
+    <link href="https://www.youtube.com/watch?v=youtube"/>
+    <published>2025-01-14T14:00:00+00:00</published>
+  </entry>
+</feed>`,
+			"odysee.com": `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Someone</title>
+    <item>
+      <title>Odysee video</title>
+      <link>https://odysee.com/@someone:a/first:1</link>
+      <pubDate>Tue, 14 Jan 2025 14:00:00 GMT</pubDate>
+    </item>
+  </channel>
+</rss>`,
+		},
+	}}
+
+	widget.update(context.Background())
+
+	if widget.failedSources != 0 || widget.totalSources != 2 {
+		t.Fatalf("Expected both sources to be fetched at the same time, got %d failed of %d", widget.failedSources, widget.totalSources)
+	}
+
+	if len(widget.Videos) != 2 || widget.Videos[0].Title != "YouTube video" || widget.Videos[1].Title != "Odysee video" {
+		t.Errorf("Expected the videos in the order of their sources, got %+v", widget.Videos)
+	}
+}
+
+func TestVideosWidgetPinnedChannels(t *testing.T) {
+	widget := newTestVideosWidget(t, `
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+limit: 4
+pinned-channels: [family, "@friend"]
+`)
+	widget.resolvedHandles = map[string]string{"@friend": "UCfriend"}
+
+	now := time.Now()
+	videos := videoList{
+		{Title: "Newest", Author: "Other", TimePosted: now.Add(-time.Hour)},
+		{Title: "Old family", Author: "Family", TimePosted: now.Add(-72 * time.Hour)},
+		{Title: "Middle", Author: "Other", TimePosted: now.Add(-2 * time.Hour)},
+		{Title: "Friend", Author: "Someone", ChannelID: "UCfriend", TimePosted: now.Add(-48 * time.Hour)},
+		{Title: "Older", Author: "Another", TimePosted: now.Add(-3 * time.Hour)},
+		{Title: "Oldest", Author: "Other", TimePosted: now.Add(-4 * time.Hour)},
+	}
+
+	arranged := widget.arrangeVideos(videos)
+
+	// Pinned videos come first and count towards the limit, the rest keep their order below
+	titles := make([]string, len(arranged))
+	for i := range arranged {
+		titles[i] = arranged[i].Title
+	}
+
+	if expected := []string{"Friend", "Old family", "Newest", "Middle"}; !slices.Equal(titles, expected) {
+		t.Errorf("Expected %v, got %v", expected, titles)
+	}
+}
+
+func TestVideosWidgetSnapshotSanitizesUrls(t *testing.T) {
+	widget := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	widget.Videos = videoList{
+		{Title: "Malicious", Url: "https://example.com/1", ThumbnailUrl: "javascript:alert(1)", Author: "Channel", AuthorUrl: "javascript:alert(2)", TimePosted: time.Now()},
+		{Title: "Placeholder", Url: "https://example.com/2", ThumbnailUrl: videoThumbnailPlaceholder, Author: "Channel", TimePosted: time.Now()},
+	}
+	widget.ContentAvailable = true
+
+	request := httptest.NewRequest("GET", "/api/widgets/1/snapshot", nil)
+	request.SetPathValue("path", "snapshot")
+	recorder := httptest.NewRecorder()
+	widget.handleRequest(recorder, request)
+
+	body := recorder.Body.String()
+	if recorder.Code != http.StatusOK || strings.Contains(body, "javascript:") {
+		t.Fatalf("Expected the URLs from the feed to be sanitized, got %d: %s", recorder.Code, body)
+	}
+
+	if !strings.Contains(body, `src="data:image/svg`) {
+		t.Errorf("Expected the placeholder thumbnail to be kept, got %s", body)
+	}
+}
+
+func TestVideosWidgetVideoUrlTemplate(t *testing.T) {
+	widget := newTestVideosWidget(t, "video-url-template: \" https://invidious.example.com/watch?v={VIDEO-ID} \"\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	if widget.VideoUrlTemplate != "https://invidious.example.com/watch?v={VIDEO-ID}" {
+		t.Errorf("Expected the template to be trimmed, got %q", widget.VideoUrlTemplate)
+	}
+
+	for _, template := range []string{"/watch?v={VIDEO-ID}", "http://localhost:3000/watch?v={VIDEO-ID}"} {
+		if err := validateVideoUrlTemplate(template); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", template, err)
+		}
+	}
+
+	tests := map[string]string{
+		"https://invidious.example.com/watch":           "placeholder",
+		"invidious.example.com/watch?v={VIDEO-ID}":      "http(s) URL",
+		"javascript:alert('{VIDEO-ID}')":                "http(s) URL",
+		"https://invidious.example.com:port/{VIDEO-ID}": "valid URL",
+	}
+
+	for template, expected := range tests {
+		invalid := &videosWidget{}
+		if err := yaml.Unmarshal([]byte("channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]"), invalid); err != nil {
+			t.Fatal(err)
+		}
+		invalid.VideoUrlTemplate = template
+
+		if err := invalid.initialize(); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected an error mentioning %q for %q, got %v", expected, template, err)
+		}
+	}
+}
+
+func TestVideosWidgetEmptyMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <yt:videoId>jNQXAC9IVRw</yt:videoId>
+    <title>Video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=jNQXAC9IVRw"/>
+    <published>2025-01-02T15:04:05+00:00</published>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+include-shorts: true
+include-keywords: [unmatched]
+empty-message: Nothing to watch
+channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+	widget.update(context.Background())
+
+	if len(widget.Videos) != 0 || !widget.ContentAvailable {
+		t.Fatalf("Expected content to be available without any videos, got %d videos", len(widget.Videos))
+	}
+
+	for _, style := range videosWidgetStyles {
+		html := string(widget.renderWithPreferences(widgetPreferences{Style: style}))
+		if !strings.Contains(html, "Nothing to watch") || strings.Contains(html, "Loading videos") {
+			t.Errorf("Expected the empty message with the %s style, got %s", style, html)
+		}
+	}
+
+	if defaulted := newTestVideosWidget(t, "channels: [UCXuqSBlHAE6Xw-yeJA0Tunw]"); defaulted.EmptyMessage != videosDefaultEmptyMessage {
+		t.Errorf("Expected the default empty message, got %q", defaulted.EmptyMessage)
+	}
+}
+
+func TestParseFeedTimes(t *testing.T) {
+	tests := []struct {
+		name     string
+		parse    func(string) (time.Time, error)
+		value    string
+		expected time.Time
+		valid    bool
+	}{
+		{
+			name:     "youtube",
+			parse:    parseYoutubeFeedTime,
+			value:    "2025-01-14T14:00:00+00:00",
+			expected: time.Date(2025, 1, 14, 14, 0, 0, 0, time.UTC),
+			valid:    true,
+		},
+		{name: "youtube empty", parse: parseYoutubeFeedTime, value: ""},
+		{name: "youtube invalid", parse: parseYoutubeFeedTime, value: "Invalid Date"},
+		{
+			name:     "rumble padded day",
+			parse:    parseRumbleFeedTime,
+			value:    "Tue, 07 Jan 2025 14:00:00 GMT",
+			expected: time.Date(2025, 1, 7, 14, 0, 0, 0, time.UTC),
+			valid:    true,
+		},
+		{
+			name:     "rumble unpadded day",
+			parse:    parseRumbleFeedTime,
+			value:    "Tue, 7 Jan 2025 14:00:00 GMT",
+			expected: time.Date(2025, 1, 7, 14, 0, 0, 0, time.UTC),
+			valid:    true,
+		},
+		{name: "rumble empty", parse: parseRumbleFeedTime, value: ""},
+		{name: "rumble invalid", parse: parseRumbleFeedTime, value: "Invalid Date"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.parse(test.value)
+
+			if !test.valid {
+				if err == nil || !result.IsZero() {
+					t.Fatalf("Expected an error and a zero time for %q, got %v, %v", test.value, result, err)
+				}
+				return
+			}
+
+			if err != nil || !result.Equal(test.expected) {
+				t.Fatalf("Expected %v for %q, got %v, %v", test.expected, test.value, result, err)
+			}
+		})
+	}
+}
+
+func TestVideosWidgetSourceWeights(t *testing.T) {
+	now := time.Now()
+	videos := make(videoList, 0)
+
+	for i := range 8 {
+		videos = append(videos, video{Title: "rumble-" + strconv.Itoa(i), Source: videoSourceRumble, TimePosted: now.Add(-time.Duration(i) * time.Minute)})
+	}
+
+	for i := range 3 {
+		videos = append(videos, video{Title: "yt-" + strconv.Itoa(i), Source: videoSourceYoutube, TimePosted: now.Add(-time.Duration(i+1) * time.Hour)})
+	}
+
+	titles := func(videos videoList) []string {
+		result := make([]string, len(videos))
+		for i := range videos {
+			result[i] = videos[i].Title
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		config   string
+		expected []string
+	}{
+		{
+			name:     "newest videos without weights",
+			config:   "limit: 6",
+			expected: []string{"rumble-0", "rumble-1", "rumble-2", "rumble-3", "rumble-4", "rumble-5"},
+		},
+		{
+			name:     "weighted round-robin between sources",
+			config:   "limit: 6\nsource-weights:\n  youtube: 2\n  rumble: 1",
+			expected: []string{"rumble-0", "rumble-1", "rumble-2", "yt-0", "yt-1", "yt-2"},
+		},
+		{
+			name:     "unweighted sources default to 1",
+			config:   "limit: 4\nsource-weights:\n  youtube: 1",
+			expected: []string{"rumble-0", "rumble-1", "yt-0", "yt-1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			widget := newTestVideosWidget(t, test.config+"\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+			result := titles(widget.arrangeVideos(slices.Clone(videos)))
+
+			if !slices.Equal(result, test.expected) {
+				t.Fatalf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+
+	widget := &videosWidget{Channels: []videoSourceField{{ID: "UCXuqSBlHAE6Xw-yeJA0Tunw"}}, SourceWeights: map[string]int{"dailymotion": 1}}
+	if err := widget.initialize(); err == nil || !strings.Contains(err.Error(), "source-weights") {
+		t.Errorf("Expected an error for an unknown source, got %v", err)
+	}
+}
+
+func TestVideosWidgetVerticalThumbnails(t *testing.T) {
+	client := staticResponseDoer(`<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+  <title>PeerTube channel</title>
+  <entry>
+    <title>Vertical video</title>
+    <link href="https://peertube.example/w/1"/>
+    <published>2025-01-14T14:00:00Z</published>
+    <media:group>
+      <media:content url="https://peertube.example/1.mp4" duration="245"/>
+      <media:thumbnail url="https://peertube.example/1.jpg" width="720" height="1280"/>
+    </media:group>
+  </entry>
+</feed>`)
+
+	feedVideos, err := fetchGenericVideoFeeds(context.Background(), []videoSourceField{{ID: "https://peertube.example/feeds/videos.atom"}}, client, videoRetryOptions{}, 1)
+	if err != nil || len(feedVideos) != 1 {
+		t.Fatalf("Expected a single video from the feed, got %+v, %v", feedVideos, err)
+	}
+
+	if v := feedVideos[0]; v.ThumbnailWidth != 720 || v.ThumbnailHeight != 1280 {
+		t.Fatalf("Expected the dimensions of the thumbnail from the feed, got %dx%d", v.ThumbnailWidth, v.ThumbnailHeight)
+	}
+
+	widget := newTestVideosWidget(t, "include-shorts: true\nchannels: [UCXuqSBlHAE6Xw-yeJA0Tunw]")
+	widget.Videos = videoList{
+		{Title: "short", Url: "https://youtube.com/shorts/a", ThumbnailUrl: "https://i.ytimg.com/vi/a/hqdefault.jpg"},
+		{Title: "video", Url: "https://youtube.com/watch?v=b", ThumbnailUrl: "https://i.ytimg.com/vi/b/hqdefault.jpg"},
+		{Title: "landscape clip", Url: "https://example.com/c", Duration: 30 * time.Second, ThumbnailWidth: 1280, ThumbnailHeight: 720},
+		feedVideos[0],
+	}
+	widget.setThumbnailOrientations(widget.Videos)
+	widget.ContentAvailable = true
+
+	vertical := make(map[string]bool)
+	for _, v := range widget.Videos {
+		vertical[v.Title] = v.VerticalThumbnail()
+	}
+
+	expected := map[string]bool{"short": true, "video": false, "landscape clip": false, "Vertical video": true}
+	if !maps.Equal(vertical, expected) {
+		t.Errorf("Expected vertical thumbnails %v, got %v", expected, vertical)
+	}
+
+	if html := string(widget.Render()); strings.Count(html, "video-thumbnail-vertical") != 2 {
+		t.Error("Expected the vertical thumbnails to be rendered with their own class")
+	}
+}
+
+func TestVideosWidgetSourceLimit(t *testing.T) {
+	now := time.Now().UTC()
+	feedFor := func(channelID string, count int, offset int) string {
+		var entries strings.Builder
+		for i := range count {
+			fmt.Fprintf(&entries, `
+  <entry>
+    <title>%s %d</title>
+    <yt:videoId>%s-%d</yt:videoId>
+    <link href="https://www.youtube.com/watch?v=%s-%d"/>
+    <published>%s</published>
+  </entry>`, channelID, i, channelID, i, channelID, i, now.Add(-time.Duration(offset+i)*time.Hour).Format("2006-01-02T15:04:05-07:00"))
+		}
+
+		return `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:yt="http://www.youtube.com/xml/schemas/2015">
+  <yt:channelId>` + channelID + `</yt:channelId>
+  <author><name>` + channelID + `</name></author>` + entries.String() + `
+</feed>`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("channel_id") {
+		case "UCnews":
+			w.Write([]byte(feedFor("UCnews", 5, 1)))
+		case "UCother":
+			w.Write([]byte(feedFor("UCother", 3, 6)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	widget := newTestVideosWidget(t, `
+limit: 4
+include-shorts: true
+channels:
+  - id: UCnews
+    limit: 2
+  - UCother
+`)
+	widget.Proxy.client = &http.Client{Transport: redirectTransport{server: server}}
+	widget.update(context.Background())
+
+	titles := make([]string, 0, len(widget.Videos))
+	for _, v := range widget.Videos {
+		titles = append(titles, v.Title)
+	}
+
+	// The news channel's newest videos would otherwise take up the whole limit
+	if expected := []string{"UCnews 0", "UCnews 1", "UCother 0", "UCother 1"}; !slices.Equal(titles, expected) {
+		t.Errorf("Expected videos %v, got %v", expected, titles)
+	}
+
+	invalid := &videosWidget{}
+	yaml.Unmarshal([]byte("channels:\n  - id: UCnews\n    limit: -1"), invalid)
+	if err := invalid.initialize(); err == nil {
+		t.Error("Expected an error for a negative limit")
+	}
+}
@@ -189,6 +189,12 @@ func (w *widgetBase) IsWIP() bool {
 	return w.WIP
 }
 
+// HasRefreshButton reports whether the header should have a button which updates
+// the widget on demand, only widgets that can be refreshed override it
+func (w *widgetBase) HasRefreshButton() bool {
+	return false
+}
+
 func (w *widgetBase) update(ctx context.Context) {
 
 }